@@ -0,0 +1,172 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/FeelPulse/feelpulse/internal/apikey"
+	"github.com/FeelPulse/feelpulse/internal/gateway"
+	"github.com/FeelPulse/feelpulse/internal/scheduler"
+	"github.com/FeelPulse/feelpulse/internal/store"
+)
+
+func printKeysUsage() {
+	fmt.Println(`Usage:
+  fp keys create <name> --scopes chat,hooks [--models m1,m2] [--rate 60] [--expires 90d] [--server-tools]
+  fp keys list
+  fp keys revoke <id>
+
+Scopes: chat (/v1 APIs), hooks (/hooks), config (config page), admin (everything)`)
+}
+
+// cmdKeys manages API keys. It writes to the database directly, so changes
+// apply to a running gateway immediately.
+func cmdKeys() {
+	if len(os.Args) < 3 {
+		printKeysUsage()
+		os.Exit(1)
+	}
+
+	db, err := store.NewSQLiteStore(store.DefaultDBPath())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Failed to open database: %v\n", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	keys, err := gateway.NewAPIKeyManager(db)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	args := os.Args[3:]
+	switch os.Args[2] {
+	case "create":
+		err = cmdKeysCreate(keys, args)
+	case "list", "ls":
+		err = cmdKeysList(keys)
+	case "revoke":
+		if len(args) != 1 {
+			printKeysUsage()
+			os.Exit(1)
+		}
+		var key *apikey.Key
+		if key, err = keys.Revoke(args[0]); err == nil {
+			fmt.Printf("🔒 Revoked %s (%s)\n", key.ID, key.Name)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown keys command: %s\n", os.Args[2])
+		printKeysUsage()
+		os.Exit(1)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func cmdKeysCreate(keys *apikey.Manager, args []string) error {
+	fs := flag.NewFlagSet("keys create", flag.ExitOnError)
+	scopes := fs.String("scopes", "", "comma-separated scopes: chat, hooks, config, admin")
+	models := fs.String("models", "", "comma-separated models the key may use (default: all)")
+	rate := fs.Int("rate", 0, "requests per minute (default: the agent rate limit only)")
+	expires := fs.String("expires", "", "lifetime, e.g. 90d or 12h (default: never)")
+	serverTools := fs.Bool("server-tools", false, "let chat requests use FeelPulse's own tools (exec, files, ...)")
+
+	// The name may come before or after the flags
+	var name string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+	fs.Parse(args)
+	if name == "" && fs.NArg() > 0 {
+		name = fs.Arg(0)
+	}
+	if name == "" {
+		printKeysUsage()
+		os.Exit(1)
+	}
+
+	opts := apikey.CreateOptions{
+		Name:        name,
+		Scopes:      apikey.SplitList(*scopes),
+		Models:      apikey.SplitList(*models),
+		RateLimit:   *rate,
+		ServerTools: *serverTools,
+	}
+	if *expires != "" {
+		d, err := scheduler.ParseDuration(*expires)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("invalid expiry '%s' (use e.g. 90d or 12h)", *expires)
+		}
+		opts.ExpiresIn = d
+	}
+
+	token, key, err := keys.Create(opts)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("🔑 Created API key %s (%s)\n", key.ID, key.Name)
+	fmt.Printf("   Scopes: %s\n", strings.Join(key.Scopes, ", "))
+	if len(key.Models) > 0 {
+		fmt.Printf("   Models: %s\n", strings.Join(key.Models, ", "))
+	}
+	if key.RateLimit > 0 {
+		fmt.Printf("   Rate limit: %d/min\n", key.RateLimit)
+	}
+	if key.ServerTools {
+		fmt.Println("   Server tools: yes")
+	}
+	if !key.ExpiresAt.IsZero() {
+		fmt.Printf("   Expires: %s\n", key.ExpiresAt.Format("2006-01-02 15:04"))
+	}
+	fmt.Println()
+	fmt.Println(token)
+	fmt.Println()
+	fmt.Println("⚠️  Store it now — it will not be shown again.")
+	return nil
+}
+
+func cmdKeysList(keys *apikey.Manager) error {
+	list, err := keys.List()
+	if err != nil {
+		return err
+	}
+	if len(list) == 0 {
+		fmt.Println("📭 No API keys. Create one with: fp keys create <name> --scopes chat")
+		return nil
+	}
+
+	now := time.Now()
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tNAME\tKEY\tSCOPES\tMODELS\tRATE\tSTATUS\tEXPIRES\tLAST USED\tREQUESTS\tTOKENS")
+	for _, k := range list {
+		models := "all"
+		if len(k.Models) > 0 {
+			models = strings.Join(k.Models, ",")
+		}
+		rate := "-"
+		if k.RateLimit > 0 {
+			rate = fmt.Sprintf("%d/min", k.RateLimit)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s…\t%s\t%s\t%s\t%s\t%s\t%s\t%d\t%d\n",
+			k.ID, k.Name, k.Prefix, strings.Join(k.Scopes, ","), models, rate, k.Status(now),
+			formatKeyTime(k.ExpiresAt, "never"), formatKeyTime(k.LastUsedAt, "never"),
+			k.Requests, k.InputTokens+k.OutputTokens)
+	}
+	return tw.Flush()
+}
+
+func formatKeyTime(t time.Time, zero string) string {
+	if t.IsZero() {
+		return zero
+	}
+	return t.Format("2006-01-02 15:04")
+}
@@ -40,6 +40,8 @@ func main() {
 		cmdGatewayLogs()
 	case "reset":
 		cmdReset()
+	case "keys":
+		cmdKeys()
 	case "version", "-v", "--version":
 		cmdVersion()
 	case "help", "-h", "--help":
@@ -65,6 +67,7 @@ Commands:
   status         Check gateway status
   logs           View gateway logs (live, Ctrl+C to exit)
   reset          Clear all memory and sessions (requires confirmation)
+  keys           Manage API keys (create, list, revoke)
   version        Print version
   help           Show this help`)
 }
@@ -457,7 +460,7 @@ func cmdReset() {
 	fmt.Println("This will:")
 	fmt.Println("  - Clear ALL session history (conversations, reminders, sub-agents, pins)")
 	fmt.Println("  - Remove IDENTITY.md, MEMORY.md, memory/, and skills/")
-	fmt.Println("  - Delete database: ~/.feelpulse/sessions.db")
+	fmt.Println("  - Delete database: ~/.feelpulse/sessions.db (including API keys)")
 	fmt.Println()
 	fmt.Println("User config files are preserved:")
 	fmt.Println("  - AGENTS.md, SOUL.md, USER.md, TOOLS.md, HEARTBEAT.md")
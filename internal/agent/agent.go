@@ -80,15 +80,52 @@ type Agent interface {
 	Name() string
 }
 
+// ContextStreamer is implemented by agents whose streaming requests can be
+// cancelled through a context
+type ContextStreamer interface {
+	ChatStreamContext(ctx context.Context, messages []types.Message, systemPrompt string, callback StreamCallback) (*types.AgentResponse, error)
+}
+
+// SystemChatter is implemented by agents that accept a system prompt for
+// non-streaming chats
+type SystemChatter interface {
+	ChatWithSystem(messages []types.Message, systemPrompt string) (*types.AgentResponse, error)
+}
+
+// RequestParams overrides generation settings for a single request (zero
+// values keep the provider defaults)
+type RequestParams struct {
+	Model       string
+	MaxTokens   int
+	Temperature *float64
+	TopP        *float64
+	Stop        []string
+}
+
+// IsZero reports whether no setting is overridden
+func (p RequestParams) IsZero() bool {
+	return p.Model == "" && p.MaxTokens == 0 && p.Temperature == nil && p.TopP == nil && len(p.Stop) == 0
+}
+
+// Configurable is implemented by agents that accept per-request settings
+type Configurable interface {
+	// WithParams returns a copy of the agent that applies params
+	WithParams(params RequestParams) Agent
+}
+
 // SystemPromptBuilder builds the system prompt dynamically
 type SystemPromptBuilder func(defaultPrompt string) string
 
+// ToolObserver is told about each registry tool the agent runs
+type ToolObserver func(sessionKey, name string, input map[string]any, err error)
+
 // Router manages AI agent providers
 type Router struct {
 	cfg           *config.Config
 	agent         Agent
 	promptBuilder SystemPromptBuilder
 	toolRegistry  *tools.Registry
+	toolObserver  ToolObserver
 }
 
 // NewRouter creates a new agent router
@@ -124,8 +161,30 @@ func (r *Router) ProcessWithHistory(messages []types.Message) (*types.Message, e
 	return r.ProcessWithHistoryStream(messages, nil, nil)
 }
 
+// ProcessOptions holds optional callbacks for a single Process call
+type ProcessOptions struct {
+	OnText          StreamCallback             // Streamed text deltas
+	OnIterationText func(string)               // Complete text of each agentic loop iteration
+	OnToolCall      func(name, summary string) // Tool about to run (agentic loop only)
+	Context         context.Context            // Cancels upstream requests and tools, e.g. on client disconnect (nil = never)
+	Params          RequestParams              // Per-request model and sampling settings
+	System          string                     // Extra system prompt, e.g. from an API client
+	ReplaceSystem   bool                       // System replaces the FeelPulse prompt instead of extending it
+	ClientTools     []AnthropicTool            // Tools defined by an API client; calls to them are returned, not run
+	ToolChoice      *AnthropicToolChoice       // Tool choice for the first request (nil = auto)
+	NoServerTools   bool                       // Don't offer the tool registry, e.g. to API clients without access
+}
+
 // ProcessWithHistoryStream handles messages with optional streaming callback and iteration text callback
 func (r *Router) ProcessWithHistoryStream(messages []types.Message, callback StreamCallback, onIterationText func(string)) (*types.Message, error) {
+	return r.ProcessWithOptions(messages, ProcessOptions{
+		OnText:          callback,
+		OnIterationText: onIterationText,
+	})
+}
+
+// ProcessWithOptions handles messages with full conversation history and the given options
+func (r *Router) ProcessWithOptions(messages []types.Message, opts ProcessOptions) (*types.Message, error) {
 	if r.agent == nil {
 		return nil, fmt.Errorf("no agent configured")
 	}
@@ -145,27 +204,69 @@ func (r *Router) ProcessWithHistoryStream(messages []types.Message, callback Str
 	if r.promptBuilder != nil {
 		systemPrompt = r.promptBuilder(systemPrompt)
 	}
+	if opts.System != "" {
+		if opts.ReplaceSystem {
+			systemPrompt = opts.System
+		} else {
+			systemPrompt += "\n\n" + opts.System
+		}
+	}
+
+	// Apply per-request settings to a copy of the agent
+	ag := r.agent
+	if configurable, ok := ag.(Configurable); ok && !opts.Params.IsZero() {
+		ag = configurable.WithParams(opts.Params)
+	}
 
 	// Extract session key from messages (channel:userID)
 	sessionKey := r.extractSessionKey(messages)
 
 	// Check if we have tools and an Anthropic client - use agentic loop
-	anthropicClient, isAnthropic := r.agent.(*AnthropicClient)
-	if isAnthropic && r.toolRegistry != nil && len(r.toolRegistry.List()) > 0 {
-		// Build Anthropic tool definitions
-		anthropicTools := r.buildAnthropicTools()
+	anthropicClient, isAnthropic := ag.(*AnthropicClient)
+	if len(opts.ClientTools) > 0 && !isAnthropic {
+		return nil, fmt.Errorf("client tools require the anthropic provider")
+	}
+	var anthropicTools []AnthropicTool
+	if isAnthropic {
+		anthropicTools = r.requestTools(opts)
+	}
 
+	if len(anthropicTools) > 0 {
 		// Create tool executor with session context
-		executor := r.createToolExecutor(sessionKey)
+		executor := r.createToolExecutor(opts.Context, sessionKey)
+		if opts.NoServerTools {
+			executor = func(name string, _ map[string]any) (string, error) {
+				return "", fmt.Errorf("unknown tool: %s", name)
+			}
+		}
+
+		clientTools := make(map[string]bool, len(opts.ClientTools))
+		for _, tool := range opts.ClientTools {
+			clientTools[tool.Name] = true
+		}
 
 		// Use agentic loop with tools
-		resp, err = anthropicClient.ChatWithTools(messages, systemPrompt, anthropicTools, executor, 10, callback, onIterationText)
-	} else if callback != nil {
+		resp, err = anthropicClient.ChatWithToolsOptions(messages, systemPrompt, anthropicTools, executor, ToolLoopOptions{
+			MaxIterations:   10,
+			OnText:          opts.OnText,
+			OnIterationText: opts.OnIterationText,
+			OnToolCall:      opts.OnToolCall,
+			Context:         opts.Context,
+			ClientTools:     clientTools,
+			ToolChoice:      opts.ToolChoice,
+		})
+	} else if streamer, ok := ag.(ContextStreamer); ok && opts.OnText != nil && opts.Context != nil {
+		// Use cancellable streaming without tools
+		resp, err = streamer.ChatStreamContext(opts.Context, messages, systemPrompt, opts.OnText)
+	} else if opts.OnText != nil {
 		// Use streaming without tools
-		resp, err = r.agent.ChatStream(messages, systemPrompt, callback)
+		resp, err = ag.ChatStream(messages, systemPrompt, opts.OnText)
+	} else if chatter, ok := ag.(SystemChatter); ok {
+		// Use simple chat with the system prompt
+		resp, err = chatter.ChatWithSystem(messages, systemPrompt)
 	} else {
 		// Use simple chat
-		resp, err = r.agent.Chat(messages)
+		resp, err = ag.Chat(messages)
 	}
 
 	if err != nil {
@@ -181,13 +282,16 @@ func (r *Router) ProcessWithHistoryStream(messages []types.Message, callback Str
 		"input_tokens":  resp.Usage.InputTokens,
 		"output_tokens": resp.Usage.OutputTokens,
 	}
-	if onIterationText != nil {
+	if opts.OnIterationText != nil || opts.OnText != nil {
 		// Real-time sending was used; caller should not re-send
 		meta["realtime_sent"] = true
 	} else if len(resp.TextBlocks) > 1 {
 		// Fallback: send each block separately at end of turn
 		meta["text_blocks"] = resp.TextBlocks
 	}
+	if len(resp.ToolCalls) > 0 {
+		meta["tool_calls"] = resp.ToolCalls
+	}
 	reply := &types.Message{
 		Text:     resp.Text,
 		Channel:  channel,
@@ -217,6 +321,25 @@ func (r *Router) buildAnthropicTools() []AnthropicTool {
 	return anthropicTools
 }
 
+// requestTools returns the tools offered for a request: the registry's
+// (unless disabled) and the client's, which win on a name clash
+func (r *Router) requestTools(opts ProcessOptions) []AnthropicTool {
+	clientNames := make(map[string]bool, len(opts.ClientTools))
+	for _, tool := range opts.ClientTools {
+		clientNames[tool.Name] = true
+	}
+
+	var list []AnthropicTool
+	if !opts.NoServerTools && r.toolRegistry != nil {
+		for _, tool := range r.buildAnthropicTools() {
+			if !clientNames[tool.Name] {
+				list = append(list, tool)
+			}
+		}
+	}
+	return append(list, opts.ClientTools...)
+}
+
 // extractSessionKey extracts session key (channel:userID) from messages
 func (r *Router) extractSessionKey(messages []types.Message) string {
 	if len(messages) == 0 {
@@ -227,6 +350,10 @@ func (r *Router) extractSessionKey(messages []types.Message) string {
 	for i := len(messages) - 1; i >= 0; i-- {
 		msg := messages[i]
 		if msg.Channel != "" && msg.Metadata != nil {
+			// Group chats share a session keyed by session_id
+			if sessionID, ok := msg.Metadata["session_id"].(string); ok && sessionID != "" {
+				return fmt.Sprintf("%s:%s", msg.Channel, sessionID)
+			}
 			if userID, ok := msg.Metadata["user_id"]; ok {
 				var sessionKey string
 				switch v := userID.(type) {
@@ -252,7 +379,10 @@ func (r *Router) extractSessionKey(messages []types.Message) string {
 }
 
 // createToolExecutor creates a function that executes tools from the registry
-func (r *Router) createToolExecutor(sessionKey string) ToolExecutor {
+func (r *Router) createToolExecutor(parent context.Context, sessionKey string) ToolExecutor {
+	if parent == nil {
+		parent = context.Background()
+	}
 	return func(name string, input map[string]any) (string, error) {
 		tool := r.toolRegistry.Get(name)
 		if tool == nil {
@@ -260,14 +390,18 @@ func (r *Router) createToolExecutor(sessionKey string) ToolExecutor {
 		}
 
 		// Create context with session key for sub-agent tools
-		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		ctx, cancel := context.WithTimeout(parent, 60*time.Second)
 		defer cancel()
 		
 		ctx = context.WithValue(ctx, "session_key", sessionKey)
 		
 		logger.Debug("🔧 Executing tool '%s' with session key: %s", name, sessionKey)
 
-		return tool.Handler(ctx, input)
+		result, err := tool.Handler(ctx, input)
+		if r.toolObserver != nil {
+			r.toolObserver(sessionKey, name, input, err)
+		}
+		return result, err
 	}
 }
 
@@ -291,7 +425,27 @@ func (r *Router) SetToolRegistry(registry *tools.Registry) {
 	r.toolRegistry = registry
 }
 
+// SetToolObserver sets a function told about each tool run, e.g. for events
+func (r *Router) SetToolObserver(observer ToolObserver) {
+	r.toolObserver = observer
+}
+
 // ToolRegistry returns the current tool registry
 func (r *Router) ToolRegistry() *tools.Registry {
 	return r.toolRegistry
 }
+
+// messageContent returns the prompt text for a message. When the user replied
+// to an earlier message, the quoted text is prepended so the model keeps the
+// reference.
+func messageContent(msg types.Message) string {
+	quoted, _ := msg.Metadata["reply_to_text"].(string)
+	if quoted == "" || msg.IsBot {
+		return msg.Text
+	}
+	author, _ := msg.Metadata["reply_to_author"].(string)
+	if author == "" {
+		author = "unknown"
+	}
+	return fmt.Sprintf("[Replying to %s: \"%s\"]\n%s", author, quoted, msg.Text)
+}
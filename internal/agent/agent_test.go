@@ -1,7 +1,9 @@
 package agent
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -10,6 +12,8 @@ import (
 	"sync/atomic"
 	"testing"
 
+	"github.com/FeelPulse/feelpulse/internal/config"
+	"github.com/FeelPulse/feelpulse/internal/tools"
 	"github.com/FeelPulse/feelpulse/pkg/types"
 )
 
@@ -639,3 +643,203 @@ func TestRouter_SystemPromptBuilder(t *testing.T) {
 		t.Error("Expected prompt builder to be set")
 	}
 }
+
+func TestMessageContent(t *testing.T) {
+	tests := []struct {
+		name     string
+		msg      types.Message
+		expected string
+	}{
+		{"plain", types.Message{Text: "hi"}, "hi"},
+		{
+			"reply to assistant",
+			types.Message{Text: "why?", Metadata: map[string]any{"reply_to_text": "Use a mutex", "reply_to_author": "assistant"}},
+			"[Replying to assistant: \"Use a mutex\"]\nwhy?",
+		},
+		{
+			"missing author",
+			types.Message{Text: "agreed", Metadata: map[string]any{"reply_to_text": "ship it"}},
+			"[Replying to unknown: \"ship it\"]\nagreed",
+		},
+		{
+			"bot messages unchanged",
+			types.Message{Text: "ok", IsBot: true, Metadata: map[string]any{"reply_to_text": "x"}},
+			"ok",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := messageContent(tt.msg); got != tt.expected {
+				t.Errorf("messageContent() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+// contextStreamAgent records whether the cancellable stream was used
+type contextStreamAgent struct {
+	MockAgent
+	gotCtx bool
+}
+
+func (a *contextStreamAgent) ChatStreamContext(ctx context.Context, messages []types.Message, systemPrompt string, callback StreamCallback) (*types.AgentResponse, error) {
+	a.gotCtx = true
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	callback("hi")
+	return &types.AgentResponse{Text: "hi", Model: "mock"}, nil
+}
+
+func TestRouter_ProcessWithOptions_Context(t *testing.T) {
+	mock := &contextStreamAgent{MockAgent: MockAgent{name: "mock"}}
+	r := &Router{cfg: &config.Config{}, agent: mock}
+	messages := []types.Message{{Text: "hello", Channel: "api"}}
+
+	var streamed string
+	reply, err := r.ProcessWithOptions(messages, ProcessOptions{
+		Context: context.Background(),
+		OnText:  func(delta string) { streamed += delta },
+	})
+	if err != nil {
+		t.Fatalf("ProcessWithOptions error: %v", err)
+	}
+	if !mock.gotCtx || streamed != "hi" || reply.Text != "hi" {
+		t.Errorf("expected cancellable stream, got ctx=%v streamed=%q reply=%q", mock.gotCtx, streamed, reply.Text)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = r.ProcessWithOptions(messages, ProcessOptions{Context: ctx, OnText: func(string) {}})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+// paramsAgent records the system prompt and settings it was called with
+type paramsAgent struct {
+	MockAgent
+	params RequestParams
+	system *string
+}
+
+func (a *paramsAgent) WithParams(params RequestParams) Agent {
+	clone := *a
+	clone.params = params
+	return &clone
+}
+
+func (a *paramsAgent) ChatWithSystem(messages []types.Message, systemPrompt string) (*types.AgentResponse, error) {
+	*a.system = systemPrompt
+	return &types.AgentResponse{Text: "ok", Model: a.params.Model}, nil
+}
+
+func TestRouter_ProcessWithOptions_SystemAndParams(t *testing.T) {
+	var system string
+	r := &Router{cfg: &config.Config{Agent: config.AgentConfig{System: "Be FeelPulse."}}, agent: &paramsAgent{system: &system}}
+	messages := []types.Message{{Text: "hello", Channel: "api"}}
+
+	reply, err := r.ProcessWithOptions(messages, ProcessOptions{
+		Params: RequestParams{Model: "claude-3-haiku-20240307"},
+		System: "Answer in French.",
+	})
+	if err != nil {
+		t.Fatalf("ProcessWithOptions error: %v", err)
+	}
+	if system != "Be FeelPulse.\n\nAnswer in French." {
+		t.Errorf("extended system prompt = %q", system)
+	}
+	if reply.Metadata["model"] != "claude-3-haiku-20240307" {
+		t.Errorf("expected requested model, got %v", reply.Metadata["model"])
+	}
+
+	_, _ = r.ProcessWithOptions(messages, ProcessOptions{System: "Answer in French.", ReplaceSystem: true})
+	if system != "Answer in French." {
+		t.Errorf("replaced system prompt = %q", system)
+	}
+}
+
+func TestAnthropicClient_WithParams(t *testing.T) {
+	temp := 0.2
+	base := NewAnthropicClient("key", "", "claude-sonnet-4-20250514")
+	client := base.WithParams(RequestParams{Model: "claude-3-haiku-20240307", MaxTokens: 100, Temperature: &temp, Stop: []string{"END"}}).(*AnthropicClient)
+
+	if client.model != "claude-3-haiku-20240307" || base.model != "claude-sonnet-4-20250514" {
+		t.Errorf("WithParams should change the copy only: copy=%s base=%s", client.model, base.model)
+	}
+
+	req := AnthropicRequest{MaxTokens: defaultMaxTokens}
+	client.applyParams(&req)
+	if req.MaxTokens != 100 || req.Temperature == nil || *req.Temperature != 0.2 || len(req.StopSequences) != 1 {
+		t.Errorf("unexpected request: %+v", req)
+	}
+
+	data, _ := json.Marshal(AnthropicRequest{Model: "m", MaxTokens: 1})
+	if strings.Contains(string(data), "temperature") || strings.Contains(string(data), "stop_sequences") {
+		t.Errorf("unset params should be omitted: %s", data)
+	}
+}
+
+func TestRouter_RequestTools(t *testing.T) {
+	registry := tools.NewRegistry()
+	registry.Register(&tools.Tool{Name: "exec", Description: "Run a command"})
+	registry.Register(&tools.Tool{Name: "get_weather", Description: "Server weather"})
+	r := &Router{toolRegistry: registry}
+
+	client := []AnthropicTool{{Name: "get_weather", Description: "Client weather", InputSchema: json.RawMessage(`{"type":"object"}`)}}
+
+	names := func(list []AnthropicTool) map[string]string {
+		m := make(map[string]string)
+		for _, tool := range list {
+			m[tool.Name] = tool.Description
+		}
+		return m
+	}
+
+	got := names(r.requestTools(ProcessOptions{ClientTools: client}))
+	if len(got) != 2 || got["get_weather"] != "Client weather" || got["exec"] == "" {
+		t.Errorf("client tools should override server tools: %v", got)
+	}
+
+	got = names(r.requestTools(ProcessOptions{ClientTools: client, NoServerTools: true}))
+	if len(got) != 1 || got["get_weather"] != "Client weather" {
+		t.Errorf("server tools should be hidden: %v", got)
+	}
+}
+
+func TestRouter_ClientToolsNeedAnthropic(t *testing.T) {
+	r := &Router{cfg: &config.Config{}, agent: &MockAgent{}}
+	_, err := r.ProcessWithOptions([]types.Message{{Text: "hi"}}, ProcessOptions{
+		ClientTools: []AnthropicTool{{Name: "get_weather"}},
+	})
+	if err == nil {
+		t.Error("expected an error for client tools on a non-Anthropic agent")
+	}
+}
+
+func TestRouter_ToolObserver(t *testing.T) {
+	registry := tools.NewRegistry()
+	registry.Register(&tools.Tool{Name: "echo", Handler: func(ctx context.Context, params map[string]any) (string, error) {
+		return "ok", nil
+	}})
+	registry.Register(&tools.Tool{Name: "broken", Handler: func(ctx context.Context, params map[string]any) (string, error) {
+		return "", errors.New("boom")
+	}})
+
+	var observed []string
+	r := &Router{toolRegistry: registry}
+	r.SetToolObserver(func(sessionKey, name string, input map[string]any, err error) {
+		observed = append(observed, fmt.Sprintf("%s %s %v", sessionKey, name, err))
+	})
+
+	executor := r.createToolExecutor(nil, "telegram:42")
+	_, _ = executor("echo", nil)
+	_, _ = executor("broken", nil)
+	_, _ = executor("missing", nil) // Unknown tools never ran
+
+	want := []string{"telegram:42 echo <nil>", "telegram:42 broken boom"}
+	if fmt.Sprint(observed) != fmt.Sprint(want) {
+		t.Errorf("observed %v, want %v", observed, want)
+	}
+}
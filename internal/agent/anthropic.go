@@ -3,6 +3,7 @@ package agent
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -36,6 +37,7 @@ type AnthropicClient struct {
 	authMode  AuthMode
 	model     string
 	client    *http.Client
+	params    RequestParams // Per-request overrides, see WithParams
 }
 
 // AnthropicRequest represents the request body for Claude API
@@ -46,6 +48,12 @@ type AnthropicRequest struct {
 	System    string             `json:"system,omitempty"`
 	Stream    bool               `json:"stream,omitempty"`
 	Tools     []AnthropicTool    `json:"tools,omitempty"`
+
+	Temperature   *float64 `json:"temperature,omitempty"`
+	TopP          *float64 `json:"top_p,omitempty"`
+	StopSequences []string `json:"stop_sequences,omitempty"`
+
+	ToolChoice *AnthropicToolChoice `json:"tool_choice,omitempty"`
 }
 
 // AnthropicTool represents a tool definition for Claude
@@ -55,6 +63,12 @@ type AnthropicTool struct {
 	InputSchema json.RawMessage `json:"input_schema"`
 }
 
+// AnthropicToolChoice controls whether and which tool the model must call
+type AnthropicToolChoice struct {
+	Type string `json:"type"`           // "auto", "any", "tool" or "none"
+	Name string `json:"name,omitempty"` // for type="tool"
+}
+
 // SSEEvent represents a Server-Sent Event from the streaming API
 type SSEEvent struct {
 	Type         string           `json:"type"`
@@ -192,6 +206,26 @@ func (c *AnthropicClient) Name() string {
 	return "anthropic"
 }
 
+// WithParams returns a copy of the client that applies params to its requests
+func (c *AnthropicClient) WithParams(params RequestParams) Agent {
+	clone := *c
+	if params.Model != "" {
+		clone.model = params.Model
+	}
+	clone.params = params
+	return &clone
+}
+
+// applyParams sets the per-request overrides on a request body
+func (c *AnthropicClient) applyParams(req *AnthropicRequest) {
+	if c.params.MaxTokens > 0 {
+		req.MaxTokens = c.params.MaxTokens
+	}
+	req.Temperature = c.params.Temperature
+	req.TopP = c.params.TopP
+	req.StopSequences = c.params.Stop
+}
+
 // AuthModeName returns a human-readable auth mode description
 func (c *AnthropicClient) AuthModeName() string {
 	if c.authMode == AuthModeOAuth {
@@ -242,10 +276,10 @@ func convertMessagesToAnthropic(messages []types.Message) []AnthropicMessage {
 								Data:      data,
 							},
 						},
-						{
-							Type: "text",
-							Text: msg.Text,
-						},
+					}
+					// The API rejects empty text blocks
+					if text := messageContent(msg); text != "" {
+						content = append(content, ContentBlock{Type: "text", Text: text})
 					}
 					anthropicMsgs = append(anthropicMsgs, AnthropicMessage{
 						Role:    role,
@@ -256,16 +290,84 @@ func convertMessagesToAnthropic(messages []types.Message) []AnthropicMessage {
 			}
 		}
 
+		// Tool calls and results exchanged with an API client
+		if calls := toolCallsFromMetadata(msg.Metadata["tool_calls"]); len(calls) > 0 && msg.IsBot {
+			anthropicMsgs = append(anthropicMsgs, AnthropicMessage{
+				Role:    role,
+				Content: toolUseContent(msg.Text, calls),
+			})
+			continue
+		}
+		if id, ok := msg.Metadata["tool_call_id"].(string); ok && !msg.IsBot {
+			anthropicMsgs = appendToolResult(anthropicMsgs, ContentBlock{
+				Type:      "tool_result",
+				ToolUseID: id,
+				Content:   msg.Text,
+			})
+			continue
+		}
+
 		// Regular text message
 		anthropicMsgs = append(anthropicMsgs, AnthropicMessage{
 			Role:    role,
-			Content: msg.Text,
+			Content: messageContent(msg),
 		})
 	}
 
 	return anthropicMsgs
 }
 
+// toolCallsFromMetadata reads tool calls stored in message metadata, which
+// are decoded as plain JSON values when a session is loaded from disk
+func toolCallsFromMetadata(value any) []types.ToolCall {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []types.ToolCall:
+		return v
+	default:
+		var calls []types.ToolCall
+		data, err := json.Marshal(v)
+		if err != nil || json.Unmarshal(data, &calls) != nil {
+			return nil
+		}
+		return calls
+	}
+}
+
+// toolUseContent builds the content of an assistant turn that called tools
+func toolUseContent(text string, calls []types.ToolCall) []ContentBlock {
+	var content []ContentBlock
+	if text != "" {
+		content = append(content, ContentBlock{Type: "text", Text: text})
+	}
+	for _, call := range calls {
+		input := call.Input
+		if len(input) == 0 {
+			input = json.RawMessage("{}")
+		}
+		content = append(content, ContentBlock{
+			Type:  "tool_use",
+			ID:    call.ID,
+			Name:  call.Name,
+			Input: input,
+		})
+	}
+	return content
+}
+
+// appendToolResult adds a tool result, joining results of the same turn into
+// one user message as the API requires
+func appendToolResult(msgs []AnthropicMessage, result ContentBlock) []AnthropicMessage {
+	if n := len(msgs); n > 0 && msgs[n-1].Role == "user" {
+		if blocks, ok := msgs[n-1].Content.([]ContentBlock); ok && len(blocks) > 0 && blocks[0].Type == "tool_result" {
+			msgs[n-1].Content = append(blocks, result)
+			return msgs
+		}
+	}
+	return append(msgs, AnthropicMessage{Role: "user", Content: []ContentBlock{result}})
+}
+
 // Chat sends messages to Claude and returns a response (uses default system prompt)
 func (c *AnthropicClient) Chat(messages []types.Message) (*types.AgentResponse, error) {
 	return c.ChatWithSystem(messages, "")
@@ -288,6 +390,7 @@ func (c *AnthropicClient) ChatWithSystem(messages []types.Message, systemPrompt
 		Messages:  anthropicMsgs,
 		System:    systemPrompt,
 	}
+	c.applyParams(&reqBody)
 
 	bodyData, err := json.Marshal(reqBody)
 	if err != nil {
@@ -352,6 +455,11 @@ func (c *AnthropicClient) ChatWithSystem(messages []types.Message, systemPrompt
 
 // ChatStream sends messages to Claude with streaming and calls callback for each delta
 func (c *AnthropicClient) ChatStream(messages []types.Message, systemPrompt string, callback StreamCallback) (*types.AgentResponse, error) {
+	return c.ChatStreamContext(context.Background(), messages, systemPrompt, callback)
+}
+
+// ChatStreamContext is ChatStream with a context that cancels the request
+func (c *AnthropicClient) ChatStreamContext(ctx context.Context, messages []types.Message, systemPrompt string, callback StreamCallback) (*types.AgentResponse, error) {
 	// Convert our messages to Anthropic format
 	anthropicMsgs := convertMessagesToAnthropic(messages)
 
@@ -368,6 +476,7 @@ func (c *AnthropicClient) ChatStream(messages []types.Message, systemPrompt stri
 		System:    systemPrompt,
 		Stream:    true,
 	}
+	c.applyParams(&reqBody)
 
 	bodyData, err := json.Marshal(reqBody)
 	if err != nil {
@@ -377,7 +486,7 @@ func (c *AnthropicClient) ChatStream(messages []types.Message, systemPrompt stri
 	// Log the full request payload
 
 	// Create HTTP request
-	req, err := http.NewRequest(http.MethodPost, anthropicAPIURL, bytes.NewReader(bodyData))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, anthropicAPIURL, bytes.NewReader(bodyData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -478,6 +587,17 @@ func parseSSEEvent(data string) (*SSEEvent, error) {
 // ToolExecutor is a function that executes a tool and returns the result
 type ToolExecutor func(name string, input map[string]any) (string, error)
 
+// ToolLoopOptions configures a run of the agentic loop
+type ToolLoopOptions struct {
+	MaxIterations   int                        // Max LLM round trips (default 10 if <= 0)
+	OnText          StreamCallback             // Called for each streamed text delta
+	OnIterationText func(string)               // Called with the complete text of each iteration
+	OnToolCall      func(name, summary string) // Called before each tool runs
+	Context         context.Context            // Cancels in-flight requests and stops the loop (nil = never)
+	ClientTools     map[string]bool            // Tools run by the caller; calling one ends the loop and returns the calls
+	ToolChoice      *AnthropicToolChoice       // Tool choice for the first request (nil = auto)
+}
+
 // ChatWithTools sends messages to Claude with tools and implements the full agentic loop.
 // Uses streaming for real-time text delivery. Calls tools as requested and continues until done.
 // maxIterations prevents infinite loops (default 10 if <= 0).
@@ -490,9 +610,31 @@ func (c *AnthropicClient) ChatWithTools(
 	callback StreamCallback,
 	onIterationText func(string),
 ) (*types.AgentResponse, error) {
+	return c.ChatWithToolsOptions(messages, systemPrompt, tools, executor, ToolLoopOptions{
+		MaxIterations:   maxIterations,
+		OnText:          callback,
+		OnIterationText: onIterationText,
+	})
+}
+
+// ChatWithToolsOptions runs the agentic loop with the given options
+func (c *AnthropicClient) ChatWithToolsOptions(
+	messages []types.Message,
+	systemPrompt string,
+	tools []AnthropicTool,
+	executor ToolExecutor,
+	opts ToolLoopOptions,
+) (*types.AgentResponse, error) {
+	maxIterations := opts.MaxIterations
 	if maxIterations <= 0 {
 		maxIterations = 10
 	}
+	callback := opts.OnText
+	onIterationText := opts.OnIterationText
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
 
 	anthropicMsgs := convertMessagesToAnthropic(messages)
 
@@ -502,6 +644,7 @@ func (c *AnthropicClient) ChatWithTools(
 
 	var totalUsage types.Usage
 	var textBlocks []string
+	var toolCalls []types.ToolCall
 	var model string
 
 	for iteration := 0; iteration < maxIterations; iteration++ {
@@ -515,10 +658,15 @@ func (c *AnthropicClient) ChatWithTools(
 			Tools:     tools,
 			Stream:    true,
 		}
+		c.applyParams(&reqBody)
+		if iteration == 0 {
+			// Forcing a tool on every iteration would never let the loop end
+			reqBody.ToolChoice = opts.ToolChoice
+		}
 
 		logger.Debug("🤖 [LLM] Sending request: %d messages, %d tools", len(anthropicMsgs), len(tools))
 
-		textContent, toolUseBlocks, respModel, usage, stopReason, err := c.callAPIStreamTools(reqBody, callback)
+		textContent, toolUseBlocks, respModel, usage, stopReason, err := c.callAPIStreamTools(ctx, reqBody, callback)
 		if err != nil {
 			logger.Error("❌ [LLM] API call failed: %v", err)
 			return nil, err
@@ -546,6 +694,12 @@ func (c *AnthropicClient) ChatWithTools(
 			break
 		}
 
+		// Calls to client tools are answered by the caller in its next request
+		if toolCalls = clientToolCalls(toolUseBlocks, opts.ClientTools); len(toolCalls) > 0 {
+			logger.Debug("🤖 [LLM] Claude called %d client tools, returning them to the caller", len(toolCalls))
+			break
+		}
+
 		logger.Debug("🤖 [LLM] Claude wants to use %d tools, continuing to iteration %d", len(toolUseBlocks), iteration+2)
 
 		// Build assistant content blocks for conversation history
@@ -569,28 +723,15 @@ func (c *AnthropicClient) ChatWithTools(
 			}
 
 			// Log tool call with key param for context
-			switch toolUse.Name {
-			case "exec":
-				if cmd, ok := input["command"].(string); ok {
-					logger.Debug("🔧 [tool] exec: %s", cmd)
-				} else {
-					logger.Debug("🔧 [tool] executing exec")
-				}
-			case "file_read", "file_write", "file_list":
-				if path, ok := input["path"].(string); ok {
-					logger.Debug("🔧 [tool] %s: %s", toolUse.Name, path)
-				} else {
-					logger.Debug("🔧 [tool] executing %s", toolUse.Name)
-				}
-			case "web_search":
-				if q, ok := input["query"].(string); ok {
-					logger.Debug("🔧 [tool] web_search: %s", q)
-				} else {
-					logger.Debug("🔧 [tool] executing web_search")
-				}
-			default:
+			summary := DescribeToolCall(toolUse.Name, input)
+			if summary != "" {
+				logger.Debug("🔧 [tool] %s: %s", toolUse.Name, summary)
+			} else {
 				logger.Debug("🔧 [tool] executing %s", toolUse.Name)
 			}
+			if opts.OnToolCall != nil {
+				opts.OnToolCall(toolUse.Name, summary)
+			}
 
 			result, err := executor(toolUse.Name, input)
 			if err != nil {
@@ -628,11 +769,47 @@ func (c *AnthropicClient) ChatWithTools(
 		TextBlocks: textBlocks,
 		Model:      model,
 		Usage:      totalUsage,
+		ToolCalls:  toolCalls,
 	}, nil
 }
 
+// clientToolCalls returns the calls to client tools among the requested tool
+// uses. Server tools requested in the same turn are not run.
+func clientToolCalls(toolUses []ContentBlock, clientTools map[string]bool) []types.ToolCall {
+	var calls []types.ToolCall
+	for _, toolUse := range toolUses {
+		if clientTools[toolUse.Name] {
+			calls = append(calls, types.ToolCall{ID: toolUse.ID, Name: toolUse.Name, Input: toolUse.Input})
+		}
+	}
+	return calls
+}
+
+// DescribeToolCall returns the key parameter of a tool call for display
+// (the command for exec, the path for file tools, the query for web_search).
+// Returns "" for tools without a well-known parameter.
+func DescribeToolCall(name string, input map[string]any) string {
+	var key string
+	switch name {
+	case "exec":
+		key = "command"
+	case "file_read", "file_write", "file_list":
+		key = "path"
+	case "web_search":
+		key = "query"
+	case "read_skill":
+		key = "name"
+	case "spawn_agent":
+		key = "task"
+	default:
+		return ""
+	}
+	value, _ := input[key].(string)
+	return value
+}
+
 // callAPIStreamTools makes a streaming API call and returns parsed text, tool_use blocks, model, usage, and stop_reason.
-func (c *AnthropicClient) callAPIStreamTools(reqBody AnthropicRequest, callback StreamCallback) (
+func (c *AnthropicClient) callAPIStreamTools(ctx context.Context, reqBody AnthropicRequest, callback StreamCallback) (
 	text string, toolUseBlocks []ContentBlock, model string, usage types.Usage, stopReason string, err error,
 ) {
 	bodyData, err := json.Marshal(reqBody)
@@ -642,7 +819,7 @@ func (c *AnthropicClient) callAPIStreamTools(reqBody AnthropicRequest, callback
 
 	// Log the full request payload
 
-	req, err := http.NewRequest(http.MethodPost, anthropicAPIURL, bytes.NewReader(bodyData))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, anthropicAPIURL, bytes.NewReader(bodyData))
 	if err != nil {
 		return "", nil, "", types.Usage{}, "", fmt.Errorf("failed to create request: %w", err)
 	}
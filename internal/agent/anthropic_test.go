@@ -1,7 +1,10 @@
 package agent
 
 import (
+	"encoding/json"
 	"testing"
+
+	"github.com/FeelPulse/feelpulse/pkg/types"
 )
 
 func TestIsOAuthToken(t *testing.T) {
@@ -243,3 +246,91 @@ func TestAnthropicToolDefinition(t *testing.T) {
 		t.Error("Expected non-empty InputSchema")
 	}
 }
+
+func TestDescribeToolCall(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    map[string]any
+		expected string
+	}{
+		{"exec", map[string]any{"command": "git status"}, "git status"},
+		{"file_read", map[string]any{"path": "notes.md"}, "notes.md"},
+		{"web_search", map[string]any{"query": "golang"}, "golang"},
+		{"spawn_agent", map[string]any{"task": "summarize"}, "summarize"},
+		{"exec", map[string]any{}, ""},
+		{"unknown_tool", map[string]any{"command": "ls"}, ""},
+	}
+
+	for _, tt := range tests {
+		if got := DescribeToolCall(tt.name, tt.input); got != tt.expected {
+			t.Errorf("DescribeToolCall(%q, %v) = %q, want %q", tt.name, tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestConvertMessagesToAnthropic_ClientTools(t *testing.T) {
+	messages := []types.Message{
+		{Text: "What's the weather in Paris and Rome?"},
+		{Text: "Checking.", IsBot: true, Metadata: map[string]any{"tool_calls": []types.ToolCall{
+			{ID: "call_1", Name: "get_weather", Input: json.RawMessage(`{"city":"Paris"}`)},
+			{ID: "call_2", Name: "get_weather"},
+		}}},
+		{Text: "18°C", Metadata: map[string]any{"tool_call_id": "call_1"}},
+		{Text: "24°C", Metadata: map[string]any{"tool_call_id": "call_2"}},
+	}
+
+	converted := convertMessagesToAnthropic(messages)
+	if len(converted) != 3 {
+		t.Fatalf("expected 3 messages, got %d", len(converted))
+	}
+
+	assistant, ok := converted[1].Content.([]ContentBlock)
+	if !ok || len(assistant) != 3 || assistant[0].Type != "text" || assistant[1].Type != "tool_use" {
+		t.Fatalf("unexpected assistant content: %+v", converted[1].Content)
+	}
+	if string(assistant[2].Input) != "{}" {
+		t.Errorf("missing input should default to {}, got %s", assistant[2].Input)
+	}
+
+	results, ok := converted[2].Content.([]ContentBlock)
+	if !ok || converted[2].Role != "user" || len(results) != 2 {
+		t.Fatalf("tool results should share one user message: %+v", converted[2])
+	}
+	if results[1].ToolUseID != "call_2" || results[1].Content != "24°C" {
+		t.Errorf("unexpected tool result: %+v", results[1])
+	}
+}
+
+func TestClientToolCalls(t *testing.T) {
+	toolUses := []ContentBlock{
+		{Type: "tool_use", ID: "1", Name: "exec", Input: json.RawMessage(`{}`)},
+		{Type: "tool_use", ID: "2", Name: "get_weather", Input: json.RawMessage(`{"city":"Paris"}`)},
+	}
+
+	if calls := clientToolCalls(toolUses, nil); len(calls) != 0 {
+		t.Errorf("expected no client calls, got %v", calls)
+	}
+
+	calls := clientToolCalls(toolUses, map[string]bool{"get_weather": true})
+	if len(calls) != 1 || calls[0].ID != "2" || string(calls[0].Input) != `{"city":"Paris"}` {
+		t.Errorf("unexpected client calls: %+v", calls)
+	}
+}
+
+func TestToolCallsFromMetadata(t *testing.T) {
+	// Sessions loaded from disk hold decoded JSON instead of typed calls
+	var decoded any
+	json.Unmarshal([]byte(`[{"id": "call_1", "name": "get_weather", "input": {"city": "Paris"}}]`), &decoded)
+
+	calls := toolCallsFromMetadata(decoded)
+	if len(calls) != 1 || calls[0].ID != "call_1" || string(calls[0].Input) != `{"city":"Paris"}` {
+		t.Errorf("unexpected calls: %+v", calls)
+	}
+
+	if calls := toolCallsFromMetadata(nil); calls != nil {
+		t.Errorf("expected nil, got %+v", calls)
+	}
+	if calls := toolCallsFromMetadata("not calls"); calls != nil {
+		t.Errorf("expected nil, got %+v", calls)
+	}
+}
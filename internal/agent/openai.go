@@ -3,6 +3,7 @@ package agent
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -24,6 +25,7 @@ type OpenAIClient struct {
 	apiKey string
 	model  string
 	client *http.Client
+	params RequestParams // Per-request overrides, see WithParams
 }
 
 // OpenAIRequest represents the request body for OpenAI Chat API
@@ -32,7 +34,9 @@ type OpenAIRequest struct {
 	Messages    []OpenAIMessage `json:"messages"`
 	MaxTokens   int             `json:"max_tokens,omitempty"`
 	Stream      bool            `json:"stream,omitempty"`
-	Temperature float64         `json:"temperature,omitempty"`
+	Temperature *float64        `json:"temperature,omitempty"`
+	TopP        *float64        `json:"top_p,omitempty"`
+	Stop        []string        `json:"stop,omitempty"`
 }
 
 // OpenAIMessage represents a message in OpenAI format
@@ -94,6 +98,26 @@ func (c *OpenAIClient) Name() string {
 	return "openai"
 }
 
+// WithParams returns a copy of the client that applies params to its requests
+func (c *OpenAIClient) WithParams(params RequestParams) Agent {
+	clone := *c
+	if params.Model != "" {
+		clone.model = params.Model
+	}
+	clone.params = params
+	return &clone
+}
+
+// applyParams sets the per-request overrides on a request body
+func (c *OpenAIClient) applyParams(req *OpenAIRequest) {
+	if c.params.MaxTokens > 0 {
+		req.MaxTokens = c.params.MaxTokens
+	}
+	req.Temperature = c.params.Temperature
+	req.TopP = c.params.TopP
+	req.Stop = c.params.Stop
+}
+
 // convertMessages converts internal messages to OpenAI format
 func (c *OpenAIClient) convertMessages(messages []types.Message) []OpenAIMessage {
 	openaiMsgs := make([]OpenAIMessage, 0, len(messages))
@@ -106,7 +130,7 @@ func (c *OpenAIClient) convertMessages(messages []types.Message) []OpenAIMessage
 
 		openaiMsgs = append(openaiMsgs, OpenAIMessage{
 			Role:    role,
-			Content: msg.Text,
+			Content: messageContent(msg),
 		})
 	}
 
@@ -133,6 +157,7 @@ func (c *OpenAIClient) ChatWithSystem(messages []types.Message, systemPrompt str
 		Messages:  openaiMsgs,
 		MaxTokens: defaultMaxTokens,
 	}
+	c.applyParams(&reqBody)
 
 	bodyData, err := json.Marshal(reqBody)
 	if err != nil {
@@ -190,6 +215,11 @@ func (c *OpenAIClient) ChatWithSystem(messages []types.Message, systemPrompt str
 
 // ChatStream sends messages with streaming and calls callback for each delta
 func (c *OpenAIClient) ChatStream(messages []types.Message, systemPrompt string, callback StreamCallback) (*types.AgentResponse, error) {
+	return c.ChatStreamContext(context.Background(), messages, systemPrompt, callback)
+}
+
+// ChatStreamContext is ChatStream with a context that cancels the request
+func (c *OpenAIClient) ChatStreamContext(ctx context.Context, messages []types.Message, systemPrompt string, callback StreamCallback) (*types.AgentResponse, error) {
 	openaiMsgs := c.convertMessages(messages)
 
 	// Prepend system message if provided
@@ -204,13 +234,14 @@ func (c *OpenAIClient) ChatStream(messages []types.Message, systemPrompt string,
 		MaxTokens: defaultMaxTokens,
 		Stream:    true,
 	}
+	c.applyParams(&reqBody)
 
 	bodyData, err := json.Marshal(reqBody)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	req, err := http.NewRequest(http.MethodPost, openaiAPIURL, bytes.NewReader(bodyData))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, openaiAPIURL, bytes.NewReader(bodyData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -0,0 +1,326 @@
+// Package apikey manages scoped API keys for the gateway's HTTP endpoints.
+// Keys are random secrets shown once at creation; only their SHA-256 hash
+// is stored.
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Scopes
+const (
+	ScopeChat   = "chat"   // OpenAI- and Anthropic-compatible APIs
+	ScopeHooks  = "hooks"  // Incoming webhooks
+	ScopeConfig = "config" // Config page and /api/config
+	ScopeAdmin  = "admin"  // Dashboard, session and key management; implies all scopes
+)
+
+// Scopes lists the valid scopes
+var Scopes = []string{ScopeChat, ScopeHooks, ScopeConfig, ScopeAdmin}
+
+// TokenPrefix starts every API key
+const TokenPrefix = "fpk_"
+
+// Errors returned by Authenticate
+var (
+	ErrInvalid = errors.New("invalid API key")
+	ErrExpired = errors.New("API key expired")
+	ErrRevoked = errors.New("API key revoked")
+)
+
+// Key is an API key without its secret
+type Key struct {
+	ID           string
+	Name         string
+	Prefix       string // Start of the secret, for display
+	Hash         string // SHA-256 of the secret, hex
+	Scopes       []string
+	Models       []string // Models the key may use (empty = all)
+	RateLimit    int      // Requests per minute (0 = the agent rate limit only)
+	ServerTools  bool     // May use FeelPulse's own tools through the chat APIs
+	ExpiresAt    time.Time
+	CreatedAt    time.Time
+	LastUsedAt   time.Time
+	RevokedAt    time.Time
+	Requests     int64
+	InputTokens  int64
+	OutputTokens int64
+}
+
+// HasScope reports whether the key grants a scope; admin grants all
+func (k *Key) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope || s == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsServerTools reports whether chat requests made with the key are
+// offered FeelPulse's own tools; admin keys always are
+func (k *Key) AllowsServerTools() bool {
+	return k.ServerTools || k.HasScope(ScopeAdmin)
+}
+
+// AllowsModel reports whether the key may use a model
+func (k *Key) AllowsModel(model string) bool {
+	if len(k.Models) == 0 {
+		return true
+	}
+	for _, m := range k.Models {
+		if m == model {
+			return true
+		}
+	}
+	return false
+}
+
+// Status returns "active", "expired" or "revoked"
+func (k *Key) Status(now time.Time) string {
+	switch {
+	case !k.RevokedAt.IsZero():
+		return "revoked"
+	case !k.ExpiresAt.IsZero() && !now.Before(k.ExpiresAt):
+		return "expired"
+	default:
+		return "active"
+	}
+}
+
+// Persister stores API keys
+type Persister interface {
+	SaveAPIKey(k *Key) error
+	LoadAPIKeys() ([]*Key, error)
+	LoadAPIKeyByHash(hash string) (*Key, error)
+	CountAPIKeys() (int, error) // All keys, including revoked and expired ones
+	TouchAPIKey(id string, at time.Time) error
+	AddAPIKeyUsage(id string, inputTokens, outputTokens int) error
+}
+
+// CreateOptions describes a new key
+type CreateOptions struct {
+	Name        string
+	Scopes      []string
+	Models      []string
+	RateLimit   int
+	ServerTools bool
+	ExpiresIn   time.Duration // 0 = never expires
+}
+
+// Manager creates, checks and revokes API keys. It reads through to the
+// persister on every request, so keys created or revoked by the CLI take
+// effect in a running gateway right away.
+type Manager struct {
+	persister Persister
+	now       func() time.Time
+}
+
+// NewManager creates a key manager
+func NewManager(p Persister) *Manager {
+	return &Manager{persister: p, now: time.Now}
+}
+
+// Create makes a new key and returns its secret, which is not stored
+func (m *Manager) Create(opts CreateOptions) (string, *Key, error) {
+	name := strings.TrimSpace(opts.Name)
+	if name == "" {
+		return "", nil, fmt.Errorf("name is required")
+	}
+	scopes, err := NormalizeScopes(opts.Scopes)
+	if err != nil {
+		return "", nil, err
+	}
+	if opts.RateLimit < 0 {
+		return "", nil, fmt.Errorf("rate limit cannot be negative")
+	}
+	if opts.ExpiresIn < 0 {
+		return "", nil, fmt.Errorf("expiry must be in the future")
+	}
+
+	secret := make([]byte, 24)
+	if _, err := rand.Read(secret); err != nil {
+		return "", nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+	id := make([]byte, 6)
+	if _, err := rand.Read(id); err != nil {
+		return "", nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+	token := TokenPrefix + hex.EncodeToString(secret)
+
+	now := m.now()
+	key := &Key{
+		ID:          hex.EncodeToString(id),
+		Name:        name,
+		Prefix:      token[:len(TokenPrefix)+8],
+		Hash:        Hash(token),
+		Scopes:      scopes,
+		Models:      trimAll(opts.Models),
+		RateLimit:   opts.RateLimit,
+		ServerTools: opts.ServerTools,
+		CreatedAt:   now,
+	}
+	if opts.ExpiresIn > 0 {
+		key.ExpiresAt = now.Add(opts.ExpiresIn)
+	}
+
+	if err := m.persister.SaveAPIKey(key); err != nil {
+		return "", nil, fmt.Errorf("failed to save API key: %w", err)
+	}
+	return token, key, nil
+}
+
+// Authenticate returns the key for a secret and records its use
+func (m *Manager) Authenticate(token string) (*Key, error) {
+	if !strings.HasPrefix(token, TokenPrefix) {
+		return nil, ErrInvalid
+	}
+	key, err := m.persister.LoadAPIKeyByHash(Hash(token))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load API key: %w", err)
+	}
+	if key == nil {
+		return nil, ErrInvalid
+	}
+
+	now := m.now()
+	switch key.Status(now) {
+	case "revoked":
+		return nil, ErrRevoked
+	case "expired":
+		return nil, ErrExpired
+	}
+
+	if err := m.persister.TouchAPIKey(key.ID, now); err != nil {
+		return nil, fmt.Errorf("failed to record API key use: %w", err)
+	}
+	key.LastUsedAt = now
+	key.Requests++
+	return key, nil
+}
+
+// RecordUsage adds token usage to a key
+func (m *Manager) RecordUsage(id string, inputTokens, outputTokens int) error {
+	if inputTokens == 0 && outputTokens == 0 {
+		return nil
+	}
+	return m.persister.AddAPIKeyUsage(id, inputTokens, outputTokens)
+}
+
+// List returns all keys, newest first
+func (m *Manager) List() ([]*Key, error) {
+	return m.persister.LoadAPIKeys()
+}
+
+// HasKeys reports whether any key was ever created. Revoked and expired
+// keys count, so authentication stays on when the last key runs out.
+func (m *Manager) HasKeys() (bool, error) {
+	n, err := m.persister.CountAPIKeys()
+	return n > 0, err
+}
+
+// Revoke revokes the key with an ID or ID prefix
+func (m *Manager) Revoke(idPrefix string) (*Key, error) {
+	idPrefix = strings.TrimSpace(idPrefix)
+	if idPrefix == "" {
+		return nil, fmt.Errorf("key ID is required")
+	}
+
+	keys, err := m.persister.LoadAPIKeys()
+	if err != nil {
+		return nil, err
+	}
+	var match *Key
+	for _, k := range keys {
+		if !strings.HasPrefix(k.ID, idPrefix) {
+			continue
+		}
+		if match != nil {
+			return nil, fmt.Errorf("key ID '%s' is ambiguous", idPrefix)
+		}
+		match = k
+	}
+	if match == nil {
+		return nil, fmt.Errorf("API key '%s' not found", idPrefix)
+	}
+	if !match.RevokedAt.IsZero() {
+		return match, nil
+	}
+
+	match.RevokedAt = m.now()
+	if err := m.persister.SaveAPIKey(match); err != nil {
+		return nil, fmt.Errorf("failed to revoke API key: %w", err)
+	}
+	return match, nil
+}
+
+// Hash returns the hex SHA-256 of a secret
+func Hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// NormalizeScopes validates and deduplicates scopes; at least one is required
+func NormalizeScopes(scopes []string) ([]string, error) {
+	var result []string
+	seen := make(map[string]bool)
+	for _, s := range trimAll(scopes) {
+		s = strings.ToLower(s)
+		if !IsScope(s) {
+			return nil, fmt.Errorf("unknown scope '%s' (use %s)", s, strings.Join(Scopes, ", "))
+		}
+		if !seen[s] {
+			seen[s] = true
+			result = append(result, s)
+		}
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("at least one scope is required (%s)", strings.Join(Scopes, ", "))
+	}
+	return result, nil
+}
+
+// IsScope reports whether s is a valid scope
+func IsScope(s string) bool {
+	for _, scope := range Scopes {
+		if scope == s {
+			return true
+		}
+	}
+	return false
+}
+
+// SplitList splits a comma-separated list, dropping empty items
+func SplitList(s string) []string {
+	return trimAll(strings.Split(s, ","))
+}
+
+func trimAll(items []string) []string {
+	var result []string
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+type contextKey struct{}
+
+// NewContext returns a context carrying the key a request was made with
+func NewContext(ctx context.Context, key *Key) context.Context {
+	return context.WithValue(ctx, contextKey{}, key)
+}
+
+// FromContext returns the key a request was made with, or nil
+func FromContext(ctx context.Context) *Key {
+	key, _ := ctx.Value(contextKey{}).(*Key)
+	return key
+}
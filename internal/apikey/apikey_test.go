@@ -0,0 +1,198 @@
+package apikey
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// memPersister keeps keys in memory
+type memPersister struct {
+	keys map[string]*Key // By ID
+}
+
+func newMemPersister() *memPersister {
+	return &memPersister{keys: make(map[string]*Key)}
+}
+
+func (p *memPersister) SaveAPIKey(k *Key) error {
+	copied := *k
+	p.keys[k.ID] = &copied
+	return nil
+}
+
+func (p *memPersister) LoadAPIKeys() ([]*Key, error) {
+	var keys []*Key
+	for _, k := range p.keys {
+		copied := *k
+		keys = append(keys, &copied)
+	}
+	return keys, nil
+}
+
+func (p *memPersister) LoadAPIKeyByHash(hash string) (*Key, error) {
+	for _, k := range p.keys {
+		if k.Hash == hash {
+			copied := *k
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (p *memPersister) CountAPIKeys() (int, error) {
+	return len(p.keys), nil
+}
+
+func (p *memPersister) TouchAPIKey(id string, at time.Time) error {
+	p.keys[id].LastUsedAt = at
+	p.keys[id].Requests++
+	return nil
+}
+
+func (p *memPersister) AddAPIKeyUsage(id string, inputTokens, outputTokens int) error {
+	p.keys[id].InputTokens += int64(inputTokens)
+	p.keys[id].OutputTokens += int64(outputTokens)
+	return nil
+}
+
+func TestManager_CreateAndAuthenticate(t *testing.T) {
+	p := newMemPersister()
+	m := NewManager(p)
+
+	if ok, _ := m.HasKeys(); ok {
+		t.Error("new manager should have no keys")
+	}
+
+	token, key, err := m.Create(CreateOptions{Name: "ci", Scopes: []string{"chat", " chat"}, Models: []string{"claude-3-haiku-20240307"}})
+	if err != nil {
+		t.Fatalf("Create error: %v", err)
+	}
+	if !strings.HasPrefix(token, TokenPrefix) || !strings.HasPrefix(token, key.Prefix) {
+		t.Errorf("unexpected token %q for prefix %q", token, key.Prefix)
+	}
+	if key.Hash == token || p.keys[key.ID].Hash != Hash(token) {
+		t.Error("only the hash of the token should be stored")
+	}
+	if len(key.Scopes) != 1 {
+		t.Errorf("scopes should be deduplicated: %v", key.Scopes)
+	}
+	if ok, _ := m.HasKeys(); !ok {
+		t.Error("manager should have a key")
+	}
+
+	got, err := m.Authenticate(token)
+	if err != nil {
+		t.Fatalf("Authenticate error: %v", err)
+	}
+	if got.ID != key.ID || p.keys[key.ID].Requests != 1 || p.keys[key.ID].LastUsedAt.IsZero() {
+		t.Errorf("use not recorded: %+v", p.keys[key.ID])
+	}
+	if !got.HasScope(ScopeChat) || got.HasScope(ScopeHooks) {
+		t.Errorf("unexpected scopes: %v", got.Scopes)
+	}
+	if !got.AllowsModel("claude-3-haiku-20240307") || got.AllowsModel("claude-opus-4-20250514") {
+		t.Errorf("unexpected models: %v", got.Models)
+	}
+
+	m.RecordUsage(key.ID, 100, 20)
+	if p.keys[key.ID].InputTokens != 100 || p.keys[key.ID].OutputTokens != 20 {
+		t.Errorf("usage not recorded: %+v", p.keys[key.ID])
+	}
+
+	for _, bad := range []string{"", "secret", token + "x", TokenPrefix} {
+		if _, err := m.Authenticate(bad); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Authenticate(%q) error = %v, want ErrInvalid", bad, err)
+		}
+	}
+}
+
+func TestManager_ExpiryAndRevoke(t *testing.T) {
+	p := newMemPersister()
+	m := NewManager(p)
+	now := time.Now()
+	m.now = func() time.Time { return now }
+
+	token, key, err := m.Create(CreateOptions{Name: "temp", Scopes: []string{"hooks"}, ExpiresIn: time.Hour})
+	if err != nil {
+		t.Fatalf("Create error: %v", err)
+	}
+	if _, err := m.Authenticate(token); err != nil {
+		t.Fatalf("Authenticate error: %v", err)
+	}
+
+	now = now.Add(2 * time.Hour)
+	if _, err := m.Authenticate(token); !errors.Is(err, ErrExpired) {
+		t.Errorf("error = %v, want ErrExpired", err)
+	}
+
+	revoked, err := m.Revoke(key.ID[:4])
+	if err != nil || revoked.RevokedAt.IsZero() {
+		t.Fatalf("Revoke error: %v", err)
+	}
+	if _, err := m.Authenticate(token); !errors.Is(err, ErrRevoked) {
+		t.Errorf("error = %v, want ErrRevoked", err)
+	}
+	if _, err := m.Revoke("zzzz"); err == nil {
+		t.Error("revoking an unknown key should fail")
+	}
+}
+
+func TestManager_CreateValidation(t *testing.T) {
+	m := NewManager(newMemPersister())
+
+	tests := []struct {
+		name string
+		opts CreateOptions
+		want string
+	}{
+		{"no name", CreateOptions{Scopes: []string{"chat"}}, "name is required"},
+		{"no scopes", CreateOptions{Name: "ci"}, "at least one scope"},
+		{"unknown scope", CreateOptions{Name: "ci", Scopes: []string{"root"}}, "unknown scope"},
+		{"negative rate", CreateOptions{Name: "ci", Scopes: []string{"chat"}, RateLimit: -1}, "rate limit"},
+		{"past expiry", CreateOptions{Name: "ci", Scopes: []string{"chat"}, ExpiresIn: -time.Hour}, "expiry"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := m.Create(tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestKey_HasScope(t *testing.T) {
+	tests := []struct {
+		scopes []string
+		scope  string
+		want   bool
+	}{
+		{[]string{ScopeChat}, ScopeChat, true},
+		{[]string{ScopeChat}, ScopeConfig, false},
+		{[]string{ScopeHooks, ScopeConfig}, ScopeConfig, true},
+		{[]string{ScopeAdmin}, ScopeHooks, true},
+		{nil, ScopeChat, false},
+	}
+
+	for _, tt := range tests {
+		k := &Key{Scopes: tt.scopes}
+		if got := k.HasScope(tt.scope); got != tt.want {
+			t.Errorf("HasScope(%v, %s) = %v, want %v", tt.scopes, tt.scope, got, tt.want)
+		}
+	}
+}
+
+func TestKey_AllowsServerTools(t *testing.T) {
+	if (&Key{Scopes: []string{ScopeChat}}).AllowsServerTools() {
+		t.Error("chat keys should not get server tools by default")
+	}
+	if !(&Key{Scopes: []string{ScopeChat}, ServerTools: true}).AllowsServerTools() {
+		t.Error("key with server tools enabled should get them")
+	}
+	if !(&Key{Scopes: []string{ScopeAdmin}}).AllowsServerTools() {
+		t.Error("admin keys should get server tools")
+	}
+}
@@ -0,0 +1,376 @@
+package channel
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// asciiPunctuation lists the characters that can be backslash-escaped
+const asciiPunctuation = "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+
+// htmlEscaper escapes the characters Telegram's HTML parse mode requires
+var htmlEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// attrEscaper additionally escapes quotes for use in attribute values
+var attrEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+
+var (
+	headingRe      = regexp.MustCompile(`^#{1,6}\s+(.*?)\s*#*\s*$`)
+	bulletRe       = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	orderedRe      = regexp.MustCompile(`^(\s*)(\d+)[.)]\s+(.*)$`)
+	ruleRe         = regexp.MustCompile(`^\s{0,3}(-(\s*-){2,}|\*(\s*\*){2,}|_(\s*_){2,})\s*$`)
+	tableDividerRe = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$`)
+)
+
+// RenderHTML converts Markdown as written by the model into Telegram's HTML
+// parse mode. Code blocks become <pre>, tables are laid out as preformatted
+// text, lists use bullets, and unbalanced emphasis markers are left as
+// literal text, so the result always parses.
+//
+// Single asterisks render as bold, as in Telegram's legacy Markdown, so
+// command replies written for that dialect keep their formatting.
+func RenderHTML(text string) string {
+	lines := strings.Split(text, "\n")
+	var out []string
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		// Fenced code block
+		if fence, lang, ok := parseFence(line); ok {
+			var code []string
+			for i++; i < len(lines); i++ {
+				if closesFence(lines[i], fence) {
+					break
+				}
+				code = append(code, lines[i])
+			}
+			out = append(out, renderCodeBlock(strings.Join(code, "\n"), lang))
+			continue
+		}
+
+		// Table: header row followed by a |---| divider
+		if strings.Contains(trimmed, "|") && i+1 < len(lines) && isTableDivider(lines[i+1]) {
+			rows := [][]string{splitTableRow(trimmed)}
+			for i += 2; i < len(lines); i++ {
+				row := strings.TrimSpace(lines[i])
+				if row == "" || !strings.Contains(row, "|") {
+					break
+				}
+				rows = append(rows, splitTableRow(row))
+			}
+			i--
+			out = append(out, renderTable(rows))
+			continue
+		}
+
+		// Blockquote: consecutive "> " lines
+		if strings.HasPrefix(trimmed, ">") {
+			var quote []string
+			for ; i < len(lines); i++ {
+				q := strings.TrimSpace(lines[i])
+				if !strings.HasPrefix(q, ">") {
+					break
+				}
+				q = strings.TrimPrefix(q, ">")
+				quote = append(quote, renderInline(strings.TrimPrefix(q, " ")))
+			}
+			i--
+			out = append(out, "<blockquote>"+strings.Join(quote, "\n")+"</blockquote>")
+			continue
+		}
+
+		switch {
+		case headingRe.MatchString(trimmed):
+			m := headingRe.FindStringSubmatch(trimmed)
+			out = append(out, "<b>"+renderInline(m[1])+"</b>")
+		case ruleRe.MatchString(line):
+			out = append(out, "──────────")
+		case bulletRe.MatchString(line):
+			m := bulletRe.FindStringSubmatch(line)
+			out = append(out, m[1]+"• "+renderInline(m[2]))
+		case orderedRe.MatchString(line):
+			m := orderedRe.FindStringSubmatch(line)
+			out = append(out, m[1]+m[2]+". "+renderInline(m[3]))
+		default:
+			out = append(out, renderInline(line))
+		}
+	}
+
+	return strings.Join(out, "\n")
+}
+
+// parseFence reports whether a line opens a fenced code block and returns
+// the fence marker and info string (language)
+func parseFence(line string) (fence, lang string, ok bool) {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 {
+		return "", "", false
+	}
+	for _, marker := range []string{"```", "~~~"} {
+		if strings.HasPrefix(trimmed, marker) {
+			n := len(trimmed) - len(strings.TrimLeft(trimmed, marker[:1]))
+			info := strings.TrimSpace(trimmed[n:])
+			if marker == "```" && strings.Contains(info, "`") {
+				return "", "", false
+			}
+			if fields := strings.Fields(info); len(fields) > 0 {
+				lang = fields[0]
+			}
+			return trimmed[:n], lang, true
+		}
+	}
+	return "", "", false
+}
+
+// closesFence reports whether a line closes a block opened with fence
+func closesFence(line, fence string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == ""
+}
+
+// renderCodeBlock formats a code block as <pre>, tagging the language
+func renderCodeBlock(code, lang string) string {
+	if lang == "" {
+		return "<pre>" + htmlEscaper.Replace(code) + "</pre>"
+	}
+	return `<pre><code class="language-` + attrEscaper.Replace(lang) + `">` + htmlEscaper.Replace(code) + "</code></pre>"
+}
+
+// isTableDivider reports whether a line is a Markdown table header divider
+func isTableDivider(line string) bool {
+	return strings.Contains(line, "-") && strings.Contains(line, "|") && tableDividerRe.MatchString(line)
+}
+
+// splitTableRow splits "| a | b |" into its cells with inline markers removed
+func splitTableRow(row string) []string {
+	row = strings.TrimPrefix(strings.TrimSuffix(row, "|"), "|")
+	cells := strings.Split(row, "|")
+	for i, cell := range cells {
+		cells[i] = stripInline(strings.TrimSpace(cell))
+	}
+	return cells
+}
+
+// stripInline removes emphasis and code markers from table cells, which are
+// shown as preformatted text
+func stripInline(s string) string {
+	return strings.NewReplacer("**", "", "__", "", "`", "").Replace(s)
+}
+
+// renderTable lays out table rows as aligned preformatted text
+func renderTable(rows [][]string) string {
+	var widths []int
+	for _, row := range rows {
+		for i, cell := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			if n := utf8.RuneCountInString(cell); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	formatRow := func(cells []string) string {
+		padded := make([]string, len(widths))
+		for i, w := range widths {
+			cell := ""
+			if i < len(cells) {
+				cell = cells[i]
+			}
+			padded[i] = cell + strings.Repeat(" ", w-utf8.RuneCountInString(cell))
+		}
+		return strings.TrimRight(strings.Join(padded, " | "), " ")
+	}
+
+	lines := []string{formatRow(rows[0])}
+	dividers := make([]string, len(widths))
+	for i, w := range widths {
+		dividers[i] = strings.Repeat("-", w)
+	}
+	lines = append(lines, strings.Join(dividers, "-+-"))
+	for _, row := range rows[1:] {
+		lines = append(lines, formatRow(row))
+	}
+
+	return "<pre>" + htmlEscaper.Replace(strings.Join(lines, "\n")) + "</pre>"
+}
+
+// renderInline converts inline Markdown (code spans, links, bold, italic,
+// strikethrough) to HTML. Markers without a matching closer are kept as text.
+func renderInline(s string) string {
+	var sb strings.Builder
+
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch c {
+		case '\\':
+			// Backslash escape of ASCII punctuation
+			if i+1 < len(s) && strings.IndexByte(asciiPunctuation, s[i+1]) >= 0 {
+				sb.WriteString(htmlEscaper.Replace(s[i+1 : i+2]))
+				i += 2
+				continue
+			}
+
+		case '`':
+			n := runLength(s, i, '`')
+			delim := s[i : i+n]
+			if end := strings.Index(s[i+n:], delim); end >= 0 && !strings.HasPrefix(s[i+n+end+n:], "`") {
+				code := strings.TrimSpace(s[i+n : i+n+end])
+				sb.WriteString("<code>" + htmlEscaper.Replace(code) + "</code>")
+				i += n + end + n
+				continue
+			}
+			sb.WriteString(delim)
+			i += n
+			continue
+
+		case '[':
+			if label, url, width, ok := parseLink(s[i:]); ok {
+				sb.WriteString(`<a href="` + attrEscaper.Replace(url) + `">` + renderInline(label) + "</a>")
+				i += width
+				continue
+			}
+
+		case '*', '_', '~':
+			n := runLength(s, i, c)
+			if inner, tag, width, ok := parseEmphasis(s, i, n); ok {
+				sb.WriteString("<" + tag + ">" + renderInline(inner) + "</" + tag + ">")
+				i += width
+				continue
+			}
+			sb.WriteString(s[i : i+n])
+			i += n
+			continue
+		}
+
+		_, size := utf8.DecodeRuneInString(s[i:])
+		sb.WriteString(htmlEscaper.Replace(s[i : i+size]))
+		i += size
+	}
+
+	return sb.String()
+}
+
+// runLength counts consecutive occurrences of c starting at i
+func runLength(s string, i int, c byte) int {
+	n := 0
+	for i+n < len(s) && s[i+n] == c {
+		n++
+	}
+	return n
+}
+
+// parseEmphasis matches an emphasis span opening at s[i] with a run of n
+// markers. Returns the inner text, the HTML tag and the span's byte width.
+func parseEmphasis(s string, i, n int) (inner, tag string, width int, ok bool) {
+	c := s[i]
+	var delim string
+	switch {
+	case c == '~' && n == 2:
+		delim, tag = "~~", "s"
+	case c == '~':
+		return "", "", 0, false
+	case n >= 2:
+		delim, tag = s[i:i+2], "b"
+	case c == '*':
+		delim, tag = "*", "b"
+	default:
+		delim, tag = "_", "i"
+	}
+
+	start := i + len(delim)
+	if start >= len(s) || s[start] == ' ' {
+		return "", "", 0, false
+	}
+	// Underscores inside words (snake_case) are not emphasis
+	if c == '_' && i > 0 && isWordByte(s[i-1]) {
+		return "", "", 0, false
+	}
+
+	for j := start + 1; j+len(delim) <= len(s); j++ {
+		if s[j:j+len(delim)] != delim || s[j-1] == ' ' {
+			continue
+		}
+		after := j + len(delim)
+		if len(delim) == 1 && after < len(s) && s[after] == c {
+			// Part of a longer run; not our closer
+			j = after
+			continue
+		}
+		if c == '_' && after < len(s) && isWordByte(s[after]) {
+			continue
+		}
+		return s[start:j], tag, after - i, true
+	}
+	return "", "", 0, false
+}
+
+// parseLink matches "[label](url)" at the start of s. Only web, mail and
+// tg:// links are accepted.
+func parseLink(s string) (label, url string, width int, ok bool) {
+	closeLabel := strings.Index(s, "](")
+	if closeLabel < 0 || strings.Contains(s[:closeLabel], "\n") {
+		return "", "", 0, false
+	}
+	closeURL := strings.IndexByte(s[closeLabel+2:], ')')
+	if closeURL < 0 {
+		return "", "", 0, false
+	}
+
+	label = s[1:closeLabel]
+	url = strings.TrimSpace(s[closeLabel+2 : closeLabel+2+closeURL])
+	if fields := strings.Fields(url); len(fields) > 0 {
+		url = strings.Trim(fields[0], "<>") // Drop an optional "title"
+	}
+	if label == "" || !safeLinkScheme(url) {
+		return "", "", 0, false
+	}
+	return label, url, closeLabel + 2 + closeURL + 1, true
+}
+
+// safeLinkScheme reports whether Telegram accepts the URL in a link
+func safeLinkScheme(url string) bool {
+	lower := strings.ToLower(url)
+	for _, scheme := range []string{"http://", "https://", "tg://", "mailto:"} {
+		if strings.HasPrefix(lower, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// isWordByte reports whether b is an ASCII letter, digit or underscore
+func isWordByte(b byte) bool {
+	return b == '_' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
+
+// isParseError reports whether Telegram rejected a message's formatting
+func isParseError(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "can't parse entities")
+}
+
+// openFence returns the opening line of a code fence left unclosed at the
+// end of text, or "" if all fences are balanced
+func openFence(text string) string {
+	var open, fence string
+	for _, line := range strings.Split(text, "\n") {
+		if open == "" {
+			if f, _, ok := parseFence(line); ok {
+				open, fence = strings.TrimSpace(line), f
+			}
+		} else if closesFence(line, fence) {
+			open, fence = "", ""
+		}
+	}
+	return open
+}
+
+// closingFence returns the marker that closes a block opened by the given line
+func closingFence(opener string) string {
+	fence, _, _ := parseFence(opener)
+	return fence
+}
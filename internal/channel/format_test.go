@@ -0,0 +1,84 @@
+package channel
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRenderHTML(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"plain text", "Hello world", "Hello world"},
+		{"escapes html", "a < b && c > d", "a &lt; b &amp;&amp; c &gt; d"},
+		{"bold", "**important**", "<b>important</b>"},
+		{"single asterisk bold", "*Title*", "<b>Title</b>"},
+		{"italic", "_note_", "<i>note</i>"},
+		{"strikethrough", "~~old~~", "<s>old</s>"},
+		{"nested", "**bold with `code`**", "<b>bold with <code>code</code></b>"},
+		{"inline code escapes", "`a<b>`", "<code>a&lt;b&gt;</code>"},
+		{"markers inside code kept", "`*not bold*`", "<code>*not bold*</code>"},
+		{"unbalanced asterisk", "2 * 3 = 6", "2 * 3 = 6"},
+		{"unclosed bold", "**oops", "**oops"},
+		{"snake case", "use my_var_name here", "use my_var_name here"},
+		{"unclosed underscore", "file_name", "file_name"},
+		{"backslash escape", `\*literal\*`, "*literal*"},
+		{"link", "[docs](https://example.com/a?b=1&c=2)", `<a href="https://example.com/a?b=1&amp;c=2">docs</a>`},
+		{"unsafe link scheme", "[x](javascript:alert(1))", "[x](javascript:alert(1))"},
+		{"brackets without link", "[1/3] done", "[1/3] done"},
+		{"heading", "## Setup", "<b>Setup</b>"},
+		{"bullets", "- one\n* two", "• one\n• two"},
+		{"ordered list", "1. first\n2) second", "1. first\n2. second"},
+		{"blockquote", "> quoted\n> text", "<blockquote>quoted\ntext</blockquote>"},
+		{"rule", "---", "──────────"},
+		{"code block", "```go\nif a < b {}\n```", `<pre><code class="language-go">if a &lt; b {}</code></pre>`},
+		{"code block no lang", "```\n**x**\n```", "<pre>**x**</pre>"},
+		{"unclosed code block", "```\ncode", "<pre>code</pre>"},
+		{"table", "| Name | Qty |\n|---|--:|\n| **apple** | 3 |", "<pre>Name  | Qty\n------+----\napple | 3</pre>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenderHTML(tt.input); got != tt.expected {
+				t.Errorf("RenderHTML(%q)\n got: %q\nwant: %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSplitLongMessage_BalancesCodeFences(t *testing.T) {
+	var sb strings.Builder
+	sb.WriteString("Here is the file:\n\n```go\n")
+	for i := 0; i < 300; i++ {
+		sb.WriteString("fmt.Println(\"line\")\n")
+	}
+	sb.WriteString("```\n\nDone.")
+
+	parts := SplitLongMessage(sb.String(), SafeMessageLength)
+	if len(parts) < 2 {
+		t.Fatalf("expected multiple parts, got %d", len(parts))
+	}
+	for i, part := range parts {
+		if fence := openFence(part); fence != "" {
+			t.Errorf("part %d leaves code fence %q open", i, fence)
+		}
+		if i > 0 && !strings.HasPrefix(part, "```go") && strings.Contains(part, "fmt.Println") {
+			t.Errorf("part %d should reopen the code block, starts with %q", i, part[:20])
+		}
+	}
+}
+
+func TestIsParseError(t *testing.T) {
+	if !isParseError(errors.New("telegram API error: Bad Request: can't parse entities: Can't find end of the entity starting at byte offset 12 (code: 400)")) {
+		t.Error("expected parse error to be detected")
+	}
+	if isParseError(errors.New("telegram API error: Too Many Requests (code: 429)")) {
+		t.Error("rate limit is not a parse error")
+	}
+	if isParseError(nil) {
+		t.Error("nil is not a parse error")
+	}
+}
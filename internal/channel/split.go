@@ -14,7 +14,8 @@ const (
 
 // SplitLongMessage splits a message into chunks that fit Telegram's limit.
 // It tries to split at paragraph boundaries, then sentence boundaries,
-// then word boundaries, to avoid breaking markdown formatting.
+// then word boundaries, to avoid breaking markdown formatting. Code blocks
+// cut by a split are closed and reopened so each part renders on its own.
 func SplitLongMessage(text string, maxLen int) []string {
 	if maxLen <= 0 {
 		maxLen = SafeMessageLength
@@ -40,6 +41,15 @@ func SplitLongMessage(text string, maxLen int) []string {
 		if len(parts) > 0 {
 			// Not the first part - no indicator needed at start
 		}
+
+		rest := strings.TrimSpace(remaining[splitPoint:])
+
+		// Never leave a code block open across messages: close it here and
+		// reopen it at the start of the next part
+		if fence := openFence(part); fence != "" && rest != "" {
+			part += "\n" + closingFence(fence)
+			rest = fence + "\n" + rest
+		}
 		
 		// Add continuation indicator if this isn't the last part
 		if len(remaining) > splitPoint {
@@ -47,7 +57,7 @@ func SplitLongMessage(text string, maxLen int) []string {
 		}
 		
 		parts = append(parts, part)
-		remaining = strings.TrimSpace(remaining[splitPoint:])
+		remaining = rest
 	}
 
 	return parts
@@ -0,0 +1,216 @@
+package channel
+
+import (
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultStreamInterval is the minimum time between edits of a streaming message
+	DefaultStreamInterval = time.Second
+	// groupStreamInterval keeps edits under Telegram's ~20 messages/minute group limit
+	groupStreamInterval = 3 * time.Second
+	// streamPlaceholder is shown until the first text arrives
+	streamPlaceholder = "💭 …"
+)
+
+// streamWriter renders a streaming reply into Telegram messages by editing
+// them in place. Deltas are buffered and flushed on a fixed cadence so edits
+// stay within Telegram rate limits. When the current message reaches
+// SafeMessageLength it is finalized and the stream continues in a new one.
+type streamWriter struct {
+	bot      *TelegramBot
+	target   ReplyTarget
+	interval time.Duration
+	maxLen   int
+
+	// Written by the agent goroutine, guarded by mu
+	mu        sync.Mutex
+	content   string   // Text of the current message
+	status    []string // Tool-in-progress lines shown below the text
+	separator bool     // Insert a paragraph break before the next delta
+
+	// Owned by the flush goroutine (and Finish, after it has stopped)
+	messageID int64
+	shown     string
+	delivered bool // Some reply text reached the chat
+
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+// newStreamWriter creates a writer for the target and starts its flush loop
+func (t *TelegramBot) newStreamWriter(target ReplyTarget, interval time.Duration) *streamWriter {
+	if interval <= 0 {
+		interval = DefaultStreamInterval
+	}
+	if target.ChatID < 0 && interval < groupStreamInterval {
+		interval = groupStreamInterval
+	}
+
+	w := &streamWriter{
+		bot:      t,
+		target:   target,
+		interval: interval,
+		maxLen:   SafeMessageLength,
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// Write appends a streamed text delta
+func (w *streamWriter) Write(delta string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.separator && strings.TrimSpace(w.content) != "" {
+		w.content = strings.TrimRight(w.content, "\n") + "\n\n"
+	}
+	w.separator = false
+	w.status = nil
+	w.content += delta
+}
+
+// EndBlock marks the end of an agentic loop iteration; the next text starts
+// a new paragraph
+func (w *streamWriter) EndBlock(string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.separator = true
+}
+
+// ToolStatus shows a status line for a tool that is about to run
+func (w *streamWriter) ToolStatus(name, summary string) {
+	line := "🔧 " + name
+	if summary != "" {
+		line += ": " + TruncateForPreview(strings.Join(strings.Fields(summary), " "), 60)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.status = append(w.status, line)
+	w.separator = true
+}
+
+// Finish stops the flush loop and renders the final text with formatting.
+// Returns true if any reply text was delivered to the chat; otherwise the
+// placeholder is removed so the caller can send the reply normally.
+func (w *streamWriter) Finish() bool {
+	close(w.done)
+	<-w.stopped
+
+	w.flush(true)
+	if !w.delivered && w.messageID != 0 {
+		if err := w.bot.DeleteMessage(w.target.ChatID, w.messageID); err != nil {
+			w.bot.log.Warn("⚠️ Failed to remove streaming placeholder: %v", err)
+		}
+	}
+	return w.delivered
+}
+
+// run sends the placeholder, then flushes buffered text on every tick until
+// Finish is called
+func (w *streamWriter) run() {
+	defer close(w.stopped)
+
+	w.render(streamPlaceholder, false)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			w.flush(false)
+		case <-w.done:
+			return
+		}
+	}
+}
+
+// flush pushes the buffered text to Telegram. Intermediate flushes are sent
+// as plain text because partial Markdown renders unpredictably; the final
+// flush (and each message that rolls over) is formatted.
+func (w *streamWriter) flush(final bool) {
+	for {
+		w.mu.Lock()
+		if len(w.content) <= w.maxLen {
+			break
+		}
+		split := findSplitPoint(w.content, w.maxLen)
+		for split > 0 && !utf8.RuneStart(w.content[split]) {
+			split--
+		}
+		head := strings.TrimSpace(w.content[:split])
+		w.content = strings.TrimLeft(w.content[split:], "\n ")
+		if fence := openFence(head); fence != "" {
+			head += "\n" + closingFence(fence)
+			w.content = fence + "\n" + w.content
+		}
+		w.mu.Unlock()
+
+		// Roll over: finalize the full message and continue in a new one
+		if w.render(head, true) {
+			w.delivered = true
+		}
+		w.messageID = 0
+		w.shown = ""
+	}
+
+	body := strings.TrimSpace(w.content)
+	var status []string
+	if !final {
+		status = append(status, w.status...)
+	}
+	w.mu.Unlock()
+
+	text := body
+	if len(status) > 0 {
+		if text != "" {
+			text += "\n\n"
+		}
+		text += strings.Join(status, "\n")
+	}
+
+	if text == "" {
+		return
+	}
+	if w.render(text, final) && body != "" {
+		w.delivered = true
+	}
+}
+
+// render shows text in the current message, sending it first if needed.
+// Returns false if Telegram rejected the update.
+func (w *streamWriter) render(text string, markdown bool) bool {
+	if text == w.shown && !markdown {
+		return true
+	}
+
+	if w.messageID == 0 {
+		target := w.target
+		if w.delivered {
+			target.ReplyToID = 0 // Only the first message quotes the original
+		}
+		id, err := w.bot.sendText(target, text, markdown, nil)
+		if err != nil {
+			w.bot.log.Warn("⚠️ Failed to send streaming message: %v", err)
+			return false
+		}
+		w.messageID = id
+		w.shown = text
+		return true
+	}
+
+	err := w.bot.EditMessageTextMarkdown(w.target.ChatID, w.messageID, text, nil, markdown)
+	if err != nil && !strings.Contains(err.Error(), "message is not modified") {
+		w.bot.log.Warn("⚠️ Failed to update streaming message: %v", err)
+		return false
+	}
+	w.shown = text
+	return true
+}
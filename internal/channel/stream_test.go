@@ -0,0 +1,49 @@
+package channel
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStreamWriter_Buffering(t *testing.T) {
+	w := &streamWriter{maxLen: SafeMessageLength}
+
+	w.Write("Let me check")
+	w.Write(" that.")
+	w.EndBlock("Let me check that.")
+	w.ToolStatus("exec", "git   status\n--short")
+
+	if len(w.status) != 1 || w.status[0] != "🔧 exec: git status --short" {
+		t.Errorf("status = %q", w.status)
+	}
+
+	w.Write("Clean tree.")
+	if w.content != "Let me check that.\n\nClean tree." {
+		t.Errorf("content = %q", w.content)
+	}
+	if w.status != nil {
+		t.Errorf("status should clear once text resumes, got %q", w.status)
+	}
+}
+
+func TestStreamWriter_NoLeadingSeparator(t *testing.T) {
+	w := &streamWriter{maxLen: SafeMessageLength}
+
+	w.ToolStatus("web_search", "")
+	if w.status[0] != "🔧 web_search" {
+		t.Errorf("status = %q", w.status[0])
+	}
+
+	w.Write("Found it.")
+	if w.content != "Found it." {
+		t.Errorf("content = %q", w.content)
+	}
+}
+
+func TestStreamWriter_ToolStatusTruncated(t *testing.T) {
+	w := &streamWriter{maxLen: SafeMessageLength}
+	w.ToolStatus("exec", strings.Repeat("x", 200))
+	if len([]rune(w.status[0])) > 80 {
+		t.Errorf("status line too long: %d runes", len([]rune(w.status[0])))
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 	"sync"
@@ -24,13 +25,30 @@ import (
 type TelegramBot struct {
 	token   string
 	baseURL string
+	fileURL string
 	client  *http.Client
 	offset  int64
 	log     *logger.Logger
 
 	handler         func(msg *types.Message) (*types.Message, error)
 	callbackHandler func(chatID int64, userID int64, action, value string) (string, *InlineKeyboard, error)
-	allowedUsers    []string // empty = allow all; non-empty = only these usernames
+	allowedUsers    []string      // empty = allow all; non-empty = only these usernames or numeric user/chat IDs
+	access          AccessControl // nil = no access requests, allowlist only
+	groupPolicy     GroupPolicy
+	me              *TelegramUser  // Bot identity, set on Start
+	mention         *regexp.Regexp // Matches "@botname", set with me; nil without a username
+	transcriber     Transcriber    // nil = voice messages not supported
+	synthesizer     SpeechSynthesizer
+	documents       DocumentStore // nil = document uploads not supported
+	localMode       bool          // Bot API server runs with --local: large files, paths on disk
+	streaming       bool          // Stream replies by editing messages in place
+	streamInterval  time.Duration
+	maxVoiceSeconds int
+	queue           *sendQueue       // Per-chat ordering and flood control for outbound messages
+	updates         UpdateStore      // nil = offset kept in memory only, no duplicate detection
+	feedback        FeedbackRecorder // nil = reactions are not tracked
+	locations       LocationUpdater  // nil = live-location updates are ignored
+	lastCleanup     time.Time
 	mu              sync.Mutex
 	running         bool
 	cancel          context.CancelFunc
@@ -38,9 +56,11 @@ type TelegramBot struct {
 
 // TelegramUpdate represents a Telegram update from getUpdates
 type TelegramUpdate struct {
-	UpdateID      int64            `json:"update_id"`
-	Message       *TelegramMessage `json:"message,omitempty"`
-	CallbackQuery *CallbackQuery   `json:"callback_query,omitempty"`
+	UpdateID        int64                   `json:"update_id"`
+	Message         *TelegramMessage        `json:"message,omitempty"`
+	EditedMessage   *TelegramMessage        `json:"edited_message,omitempty"`
+	MessageReaction *MessageReactionUpdated `json:"message_reaction,omitempty"`
+	CallbackQuery   *CallbackQuery          `json:"callback_query,omitempty"`
 }
 
 // CallbackQuery represents a callback query from an inline keyboard button press
@@ -53,13 +73,59 @@ type CallbackQuery struct {
 
 // TelegramMessage represents a Telegram message
 type TelegramMessage struct {
-	MessageID int64           `json:"message_id"`
-	From      *TelegramUser   `json:"from,omitempty"`
-	Chat      *TelegramChat   `json:"chat"`
-	Date      int64           `json:"date"`
-	Text      string          `json:"text,omitempty"`
-	Caption   string          `json:"caption,omitempty"`
-	Photo     []TelegramPhoto `json:"photo,omitempty"` // Array of PhotoSize, largest last
+	MessageID       int64             `json:"message_id"`
+	MessageThreadID int64             `json:"message_thread_id,omitempty"` // Forum topic ID
+	IsTopicMessage  bool              `json:"is_topic_message,omitempty"`
+	From            *TelegramUser     `json:"from,omitempty"`
+	Chat            *TelegramChat     `json:"chat"`
+	Date            int64             `json:"date"`
+	ReplyToMessage  *TelegramMessage  `json:"reply_to_message,omitempty"`
+	Quote           *TextQuote        `json:"quote,omitempty"`     // Part of the replied message the user selected
+	EditDate        int64             `json:"edit_date,omitempty"` // Set on edited messages
+	Text            string            `json:"text,omitempty"`
+	Entities        []MessageEntity   `json:"entities,omitempty"`
+	Caption         string            `json:"caption,omitempty"`
+	CaptionEntities []MessageEntity   `json:"caption_entities,omitempty"`
+	Photo           []TelegramPhoto   `json:"photo,omitempty"` // Array of PhotoSize, largest last
+	Voice           *TelegramVoice    `json:"voice,omitempty"`
+	Audio           *TelegramAudio    `json:"audio,omitempty"`
+	Document        *TelegramDocument `json:"document,omitempty"`
+	Location        *TelegramLocation `json:"location,omitempty"`
+	Venue           *TelegramVenue    `json:"venue,omitempty"` // Venues carry a location too
+}
+
+// TextQuote is the part of a replied-to message quoted by the user
+type TextQuote struct {
+	Text     string `json:"text"`
+	Position int    `json:"position"`
+	IsManual bool   `json:"is_manual,omitempty"`
+}
+
+// TelegramVoice represents a voice note (OGG/Opus)
+type TelegramVoice struct {
+	FileID       string `json:"file_id"`
+	FileUniqueID string `json:"file_unique_id"`
+	Duration     int    `json:"duration"` // Seconds
+	MimeType     string `json:"mime_type,omitempty"`
+	FileSize     int    `json:"file_size,omitempty"`
+}
+
+// TelegramAudio represents an audio file sent as music
+type TelegramAudio struct {
+	FileID       string `json:"file_id"`
+	FileUniqueID string `json:"file_unique_id"`
+	Duration     int    `json:"duration"` // Seconds
+	FileName     string `json:"file_name,omitempty"`
+	MimeType     string `json:"mime_type,omitempty"`
+	FileSize     int    `json:"file_size,omitempty"`
+}
+
+// MessageEntity represents a special entity in a text message (mention, command, ...)
+type MessageEntity struct {
+	Type   string        `json:"type"`
+	Offset int           `json:"offset"`
+	Length int           `json:"length"`
+	User   *TelegramUser `json:"user,omitempty"` // For "text_mention"
 }
 
 // TelegramPhoto represents a photo size in Telegram
@@ -89,17 +155,24 @@ type TelegramUser struct {
 
 // TelegramChat represents a Telegram chat
 type TelegramChat struct {
-	ID    int64  `json:"id"`
-	Type  string `json:"type"`
-	Title string `json:"title,omitempty"`
+	ID      int64  `json:"id"`
+	Type    string `json:"type"` // "private", "group", "supergroup" or "channel"
+	Title   string `json:"title,omitempty"`
+	IsForum bool   `json:"is_forum,omitempty"`
 }
 
 // TelegramResponse is the generic API response wrapper
 type TelegramResponse struct {
-	OK          bool            `json:"ok"`
-	Result      json.RawMessage `json:"result,omitempty"`
-	Description string          `json:"description,omitempty"`
-	ErrorCode   int             `json:"error_code,omitempty"`
+	OK          bool                `json:"ok"`
+	Result      json.RawMessage     `json:"result,omitempty"`
+	Description string              `json:"description,omitempty"`
+	ErrorCode   int                 `json:"error_code,omitempty"`
+	Parameters  *ResponseParameters `json:"parameters,omitempty"`
+}
+
+// ResponseParameters carries extra error details (e.g. flood-control delay)
+type ResponseParameters struct {
+	RetryAfter int `json:"retry_after,omitempty"` // Seconds to wait after a 429
 }
 
 // NewTelegramBot creates a new Telegram bot instance
@@ -107,14 +180,18 @@ func NewTelegramBot(token string, log *logger.Logger) *TelegramBot {
 	if log == nil {
 		log = logger.GetDefaultLogger()
 	}
-	return &TelegramBot{
+	bot := &TelegramBot{
 		token:   token,
-		baseURL: "https://api.telegram.org/bot" + token,
+		baseURL: DefaultAPIURL + "/bot" + token,
+		fileURL: DefaultAPIURL + "/file/bot" + token,
 		client: &http.Client{
 			Timeout: 60 * time.Second, // Long polling timeout
 		},
-		log: log.WithComponent("telegram"),
+		log:         log.WithComponent("telegram"),
+		groupPolicy: DefaultGroupPolicy(),
 	}
+	bot.queue = newSendQueue(bot.log.Warn)
+	return bot
 }
 
 // SetHandler sets the message handler function
@@ -127,6 +204,22 @@ func (t *TelegramBot) SetCallbackHandler(handler func(chatID int64, userID int64
 	t.callbackHandler = handler
 }
 
+// SetTranscriber enables voice messages using the given speech-to-text engine.
+// maxDurationSeconds limits the length of accepted recordings (0 = no limit).
+func (t *TelegramBot) SetTranscriber(transcriber Transcriber, maxDurationSeconds int) {
+	t.transcriber = transcriber
+	t.maxVoiceSeconds = maxDurationSeconds
+}
+
+// SetStreaming enables live replies: text is streamed into a message that is
+// edited at most once per interval
+func (t *TelegramBot) SetStreaming(enabled bool, interval time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.streaming = enabled
+	t.streamInterval = interval
+}
+
 // SetAllowedUsers sets the allowlist of usernames
 // Empty list means all users are allowed
 func (t *TelegramBot) SetAllowedUsers(users []string) {
@@ -150,6 +243,28 @@ func (t *TelegramBot) IsUserAllowed(username string) bool {
 	return false
 }
 
+// IsAllowed checks a message sender against the allowlist.
+// Entries match the username, the numeric user ID, or the numeric chat ID
+// (allowing everyone in a group by listing the group's ID).
+func (t *TelegramBot) IsAllowed(username string, userID, chatID int64) bool {
+	if t.IsUserAllowed(username) {
+		return true
+	}
+
+	for _, allowed := range t.allowedUsers {
+		id, err := strconv.ParseInt(allowed, 10, 64)
+		if err != nil {
+			continue
+		}
+		if (userID != 0 && id == userID) || (chatID != 0 && id == chatID) {
+			return true
+		}
+	}
+
+	// Users approved at runtime are keyed by ID, since usernames can change
+	return t.access != nil && userID != 0 && t.access.IsApproved(userID)
+}
+
 // Start begins polling for updates
 func (t *TelegramBot) Start(ctx context.Context) error {
 	t.mu.Lock()
@@ -173,6 +288,13 @@ func (t *TelegramBot) Start(ctx context.Context) error {
 	}
 	t.log.Info("📱 Telegram bot connected: @%s", me.Username)
 
+	t.mu.Lock()
+	t.me = me
+	if me.Username != "" {
+		t.mention = mentionPattern(me.Username)
+	}
+	t.mu.Unlock()
+
 	// Register bot commands menu
 	if err := t.SetMyCommands(); err != nil {
 		t.log.Warn("⚠️ Failed to set bot commands: %v", err)
@@ -180,6 +302,9 @@ func (t *TelegramBot) Start(ctx context.Context) error {
 		t.log.Info("📋 Bot commands menu registered")
 	}
 
+	// Resume from the persisted offset so a restart does not replay updates
+	t.restoreOffset()
+
 	// Start polling loop
 	go t.pollLoop(ctx)
 
@@ -231,9 +356,9 @@ func (t *TelegramBot) pollLoop(ctx context.Context) {
 // poll fetches and processes updates
 func (t *TelegramBot) poll(ctx context.Context) error {
 	params := map[string]any{
-		"offset":  t.offset,
-		"timeout": 30, // Long polling
-		"allowed_updates": []string{"message", "callback_query"},
+		"offset":          t.offset,
+		"timeout":         30, // Long polling
+		"allowed_updates": []string{"message", "edited_message", "message_reaction", "callback_query"},
 	}
 
 	resp, err := t.call("getUpdates", params)
@@ -252,23 +377,63 @@ func (t *TelegramBot) poll(ctx context.Context) error {
 			t.offset = update.UpdateID + 1
 		}
 
-		if update.Message != nil {
-			// Handle text messages
-			if update.Message.Text != "" {
-				t.handleMessage(ctx, update.Message)
-			}
-			// Handle photo messages
-			if len(update.Message.Photo) > 0 {
-				t.handlePhotoMessage(ctx, update.Message)
-			}
+		t.handleUpdate(ctx, &update)
+	}
+
+	if len(updates) > 0 {
+		t.saveOffset()
+	}
+
+	return nil
+}
+
+// handleUpdate routes a single update to its handler. Messages that were
+// already processed (redelivered after a restart or handled by another
+// instance) are skipped.
+func (t *TelegramBot) handleUpdate(ctx context.Context, update *TelegramUpdate) {
+	if update.Message != nil {
+		if t.isDuplicate(update.Message) {
+			return
+		}
+
+		// Handle text messages
+		if update.Message.Text != "" {
+			t.handleMessage(ctx, update.Message)
+		}
+		// Handle photo messages
+		if len(update.Message.Photo) > 0 {
+			t.handlePhotoMessage(ctx, update.Message)
 		}
+		// Handle voice notes and audio files
+		if update.Message.Voice != nil || update.Message.Audio != nil {
+			t.handleVoiceMessage(ctx, update.Message)
+		}
+		// Handle file uploads
+		if update.Message.Document != nil {
+			t.handleDocumentMessage(ctx, update.Message)
+		}
+		// Handle shared locations and venues
+		if update.Message.Location != nil || update.Message.Venue != nil {
+			t.handleLocationMessage(update.Message)
+		}
+	}
 
-		if update.CallbackQuery != nil {
-			t.handleCallbackQuery(ctx, update.CallbackQuery)
+	if update.EditedMessage != nil {
+		// Live locations are sent as edits of the original message
+		if update.EditedMessage.Location != nil {
+			t.handleLiveLocation(update.EditedMessage)
+		} else {
+			t.handleEditedMessage(ctx, update.EditedMessage)
 		}
 	}
 
-	return nil
+	if update.MessageReaction != nil {
+		t.handleReaction(update.MessageReaction)
+	}
+
+	if update.CallbackQuery != nil {
+		t.handleCallbackQuery(ctx, update.CallbackQuery)
+	}
 }
 
 // handleMessage processes an incoming message
@@ -277,82 +442,74 @@ func (t *TelegramBot) handleMessage(ctx context.Context, tgMsg *TelegramMessage)
 		return
 	}
 
-	// Get username for allowlist check
-	var username string
-	if tgMsg.From != nil {
-		username = tgMsg.From.Username
-	}
-
-	// Check allowlist before processing
-	if !t.IsUserAllowed(username) {
-		t.log.Warn("⛔ Blocked message from unauthorized user: %s", username)
-		_ = t.SendMessage(tgMsg.Chat.ID, "⛔ You are not authorized to use this bot.", false)
+	if !t.accept(tgMsg, tgMsg.Text, tgMsg.Entities) {
 		return
 	}
 
-	// Convert to our Message type
-	msg := &types.Message{
-		ID:        strconv.FormatInt(tgMsg.MessageID, 10),
-		Text:      tgMsg.Text,
-		Channel:   "telegram",
-		Timestamp: time.Unix(tgMsg.Date, 0),
-		IsBot:     false,
-		Metadata: map[string]any{
-			"chat_id": tgMsg.Chat.ID,
-		},
-	}
-
-	if tgMsg.From != nil {
-		msg.From = tgMsg.From.Username
-		if msg.From == "" {
-			msg.From = tgMsg.From.FirstName
-		}
-		msg.Metadata["user_id"] = tgMsg.From.ID
-	}
+	msg := t.newIncomingMessage(tgMsg, t.stripBotMention(tgMsg.Text))
 
 	t.log.Debug("📨 [%s] %s: %s", msg.Channel, msg.From, msg.Text)
 
-	// Provide an immediate sender so the agentic loop can push text blocks in real-time
-	chatID := tgMsg.Chat.ID
-	msg.Metadata["immediate_sender"] = func(text string) {
-		parts := SplitLongMessage(text, SafeMessageLength)
-		for _, part := range parts {
-			if err := t.SendMessage(chatID, part, true); err != nil {
-				t.log.Error("❌ Failed to send intermediate message: %v", err)
-			}
-		}
+	t.dispatch(replyTargetFor(tgMsg), msg)
+}
+
+// dispatch runs the handler for a converted message and delivers the reply
+func (t *TelegramBot) dispatch(target ReplyTarget, msg *types.Message) {
+	if t.feedback != nil {
+		target.sent = &sentLog{}
 	}
 
-	// Keep typing indicator alive during processing
-	stopTyping := make(chan struct{})
-	go func() {
-		_ = t.SendTypingAction(tgMsg.Chat.ID)
-		ticker := time.NewTicker(4 * time.Second)
-		defer ticker.Stop()
-		for {
-			select {
-			case <-ticker.C:
-				_ = t.SendTypingAction(tgMsg.Chat.ID)
-			case <-stopTyping:
-				return
+	// A config reload may change streaming while messages are handled
+	t.mu.Lock()
+	streaming, interval := t.streaming, t.streamInterval
+	t.mu.Unlock()
+
+	var stream *streamWriter
+	if streaming && !strings.HasPrefix(msg.Text, "/") {
+		// Stream text deltas and tool status into a live-edited message
+		stream = t.newStreamWriter(target, interval)
+		msg.Metadata["stream_delta"] = stream.Write
+		msg.Metadata["immediate_sender"] = stream.EndBlock
+		msg.Metadata["tool_status"] = stream.ToolStatus
+	} else {
+		// Provide an immediate sender so the agentic loop can push text blocks in real-time
+		msg.Metadata["immediate_sender"] = func(text string) {
+			parts := SplitLongMessage(text, SafeMessageLength)
+			for _, part := range parts {
+				if _, err := t.SendMessageTo(target, part, true); err != nil {
+					t.log.Error("❌ Failed to send intermediate message: %v", err)
+				}
 			}
 		}
-	}()
-
-	// Call handler
-	reply, err := t.handler(msg)
-	close(stopTyping)
+	}
 
+	reply, err := t.runHandler(target, msg)
+	if stream != nil && !stream.Finish() && reply != nil && reply.Metadata != nil {
+		// Nothing reached the chat while streaming; send the reply normally
+		delete(reply.Metadata, "realtime_sent")
+	}
 	if err != nil {
 		t.log.Error("❌ Handler error: %v", err)
 		return
 	}
 
-	if reply != nil && reply.Text != "" {
-		// Skip if already sent in real-time during the agentic loop
-		if sent, _ := reply.Metadata["realtime_sent"].(bool); !sent {
-			t.sendReply(tgMsg.Chat.ID, reply)
-		}
+	t.deliver(target, reply)
+	t.recordSent(target, msg, reply)
+}
+
+// deliver sends a handler reply: the text (unless already sent in real-time
+// during the agentic loop) followed by a voice note when TTS is requested
+func (t *TelegramBot) deliver(target ReplyTarget, reply *types.Message) {
+	if reply == nil || reply.Text == "" {
+		return
+	}
+
+	if sent, _ := reply.Metadata["realtime_sent"].(bool); !sent {
+		t.sendReply(target, reply)
+	}
+
+	if speak, _ := reply.Metadata["tts"].(bool); speak {
+		t.sendSpeech(target, reply.Text)
 	}
 }
 
@@ -362,16 +519,7 @@ func (t *TelegramBot) handlePhotoMessage(ctx context.Context, tgMsg *TelegramMes
 		return
 	}
 
-	// Get username for allowlist check
-	var username string
-	if tgMsg.From != nil {
-		username = tgMsg.From.Username
-	}
-
-	// Check allowlist before processing
-	if !t.IsUserAllowed(username) {
-		t.log.Warn("⛔ Blocked photo from unauthorized user: %s", username)
-		_ = t.SendMessage(tgMsg.Chat.ID, "⛔ You are not authorized to use this bot.", false)
+	if !t.accept(tgMsg, tgMsg.Caption, tgMsg.CaptionEntities) {
 		return
 	}
 
@@ -380,11 +528,12 @@ func (t *TelegramBot) handlePhotoMessage(ctx context.Context, tgMsg *TelegramMes
 		return
 	}
 	photo := tgMsg.Photo[len(tgMsg.Photo)-1]
+	target := replyTargetFor(tgMsg)
 
 	// Check file size (max 5MB for safety)
 	if photo.FileSize > 5*1024*1024 {
 		t.log.Warn("⚠️ Photo too large: %d bytes", photo.FileSize)
-		_ = t.SendMessage(tgMsg.Chat.ID, "⚠️ Photo is too large. Please send a smaller image (max 5MB).", false)
+		_, _ = t.SendMessageTo(target, "⚠️ Photo is too large. Please send a smaller image (max 5MB).", false)
 		return
 	}
 
@@ -392,7 +541,7 @@ func (t *TelegramBot) handlePhotoMessage(ctx context.Context, tgMsg *TelegramMes
 	fileInfo, err := t.GetFile(photo.FileID)
 	if err != nil {
 		t.log.Error("❌ Failed to get file info: %v", err)
-		_ = t.SendMessage(tgMsg.Chat.ID, "❌ Failed to process photo.", false)
+		_, _ = t.SendMessageTo(target, "❌ Failed to process photo.", false)
 		return
 	}
 
@@ -400,7 +549,7 @@ func (t *TelegramBot) handlePhotoMessage(ctx context.Context, tgMsg *TelegramMes
 	imageData, err := t.DownloadFile(fileInfo.FilePath)
 	if err != nil {
 		t.log.Error("❌ Failed to download photo: %v", err)
-		_ = t.SendMessage(tgMsg.Chat.ID, "❌ Failed to download photo.", false)
+		_, _ = t.SendMessageTo(target, "❌ Failed to download photo.", false)
 		return
 	}
 
@@ -418,11 +567,55 @@ func (t *TelegramBot) handlePhotoMessage(ctx context.Context, tgMsg *TelegramMes
 	imageBase64 := base64.StdEncoding.EncodeToString(imageData)
 
 	// Create message with image data
-	text := tgMsg.Caption
+	text := t.stripBotMention(tgMsg.Caption)
 	if text == "" {
 		text = "What do you see in this image?"
 	}
 
+	msg := t.newIncomingMessage(tgMsg, text)
+	msg.Metadata["image"] = map[string]string{
+		"data":       imageBase64,
+		"media_type": mediaType,
+	}
+
+	t.log.Debug("📷 [%s] %s: [Photo] %s", msg.Channel, msg.From, text)
+
+	reply, err := t.runHandler(target, msg)
+	if err != nil {
+		t.log.Error("❌ Handler error: %v", err)
+		return
+	}
+
+	t.deliver(target, reply)
+	t.recordSent(target, msg, reply)
+}
+
+// accept decides whether an incoming message should be processed.
+// Group messages not addressed to the bot are ignored silently;
+// senders outside the allowlist are told they are not authorized.
+func (t *TelegramBot) accept(tgMsg *TelegramMessage, text string, entities []MessageEntity) bool {
+	if !t.isAddressed(tgMsg, text, entities) {
+		return false
+	}
+
+	var username string
+	var userID int64
+	if tgMsg.From != nil {
+		username = tgMsg.From.Username
+		userID = tgMsg.From.ID
+	}
+
+	if !t.IsAllowed(username, userID, tgMsg.Chat.ID) {
+		t.log.Warn("⛔ Blocked message from unauthorized user: %s (chat %d)", username, tgMsg.Chat.ID)
+		t.handleUnauthorized(tgMsg, text)
+		return false
+	}
+	return true
+}
+
+// newIncomingMessage converts a Telegram message into our Message type with
+// the routing metadata shared by all message kinds
+func (t *TelegramBot) newIncomingMessage(tgMsg *TelegramMessage, text string) *types.Message {
 	msg := &types.Message{
 		ID:        strconv.FormatInt(tgMsg.MessageID, 10),
 		Text:      text,
@@ -430,11 +623,8 @@ func (t *TelegramBot) handlePhotoMessage(ctx context.Context, tgMsg *TelegramMes
 		Timestamp: time.Unix(tgMsg.Date, 0),
 		IsBot:     false,
 		Metadata: map[string]any{
-			"chat_id": tgMsg.Chat.ID,
-			"image": map[string]string{
-				"data":       imageBase64,
-				"media_type": mediaType,
-			},
+			"chat_id":   tgMsg.Chat.ID,
+			"chat_type": tgMsg.Chat.Type,
 		},
 	}
 
@@ -446,49 +636,62 @@ func (t *TelegramBot) handlePhotoMessage(ctx context.Context, tgMsg *TelegramMes
 		msg.Metadata["user_id"] = tgMsg.From.ID
 	}
 
-	t.log.Debug("📷 [%s] %s: [Photo] %s", msg.Channel, msg.From, text)
+	// Group members share one session per chat (or per forum topic)
+	if sessionID := t.sessionID(tgMsg); sessionID != "" {
+		msg.Metadata["session_id"] = sessionID
+	}
+	if tgMsg.IsTopicMessage && tgMsg.MessageThreadID != 0 {
+		msg.Metadata["thread_id"] = tgMsg.MessageThreadID
+	}
+
+	t.addReplyContext(msg, tgMsg)
+
+	return msg
+}
 
-	// Keep typing indicator alive during processing
+// runHandler calls the message handler while keeping the typing indicator alive
+func (t *TelegramBot) runHandler(target ReplyTarget, msg *types.Message) (*types.Message, error) {
 	stopTyping := make(chan struct{})
 	go func() {
-		_ = t.SendTypingAction(tgMsg.Chat.ID)
+		_ = t.sendChatAction(target, "typing")
 		ticker := time.NewTicker(4 * time.Second)
 		defer ticker.Stop()
 		for {
 			select {
 			case <-ticker.C:
-				_ = t.SendTypingAction(tgMsg.Chat.ID)
+				_ = t.sendChatAction(target, "typing")
 			case <-stopTyping:
 				return
 			}
 		}
 	}()
 
-	// Call handler
 	reply, err := t.handler(msg)
 	close(stopTyping)
-	
-	if err != nil {
-		t.log.Error("❌ Handler error: %v", err)
-		return
-	}
+	return reply, err
+}
 
-	if reply != nil && reply.Text != "" {
-		t.sendReply(tgMsg.Chat.ID, reply)
+// sendReply sends a reply message handling special cases (export, keyboard).
+// Only the first message quotes the original when the target has ReplyToID.
+func (t *TelegramBot) sendReply(target ReplyTarget, reply *types.Message) {
+	first := true
+	send := func(text string, keyboard *InlineKeyboard) error {
+		to := target
+		if !first {
+			to.ReplyToID = 0
+		}
+		first = false
+		_, err := t.sendText(to, text, true, keyboard)
+		return err
 	}
-}
 
-// handleMessageWithStreaming processes a message using streaming with message editing.
-// Uses typing indicator while waiting, then sends/edits a real message once content arrives.
-// sendReply sends a reply message handling special cases (export, keyboard)
-func (t *TelegramBot) sendReply(chatID int64, reply *types.Message) {
 	// If the response has multiple text blocks (from agentic loop iterations), send each separately
 	if reply.Metadata != nil {
 		if blocks, ok := reply.Metadata["text_blocks"].([]string); ok && len(blocks) > 1 {
 			for _, block := range blocks {
 				parts := SplitLongMessage(block, SafeMessageLength)
 				for _, part := range parts {
-					if err := t.SendMessage(chatID, part, true); err != nil {
+					if err := send(part, nil); err != nil {
 						t.log.Error("❌ Failed to send text block: %v", err)
 					}
 				}
@@ -505,9 +708,9 @@ func (t *TelegramBot) sendReply(chatID int64, reply *types.Message) {
 				filename = "export.txt"
 			}
 			content := []byte(reply.Text)
-			if err := t.SendDocument(chatID, filename, content, "📤 Conversation export"); err != nil {
+			if err := t.sendMultipart("sendDocument", target, "document", filename, openBytes(content), "📤 Conversation export"); err != nil {
 				t.log.Error("❌ Failed to send export file: %v", err)
-				_ = t.SendMessage(chatID, "❌ Failed to export conversation.", false)
+				_ = send("❌ Failed to export conversation.", nil)
 			}
 			return
 		}
@@ -521,14 +724,14 @@ func (t *TelegramBot) sendReply(chatID int64, reply *types.Message) {
 				for _, msg := range messages {
 					parts := SplitLongMessage(msg, SafeMessageLength)
 					for _, part := range parts {
-						if err := t.SendMessage(chatID, part, true); err != nil {
+						if err := send(part, nil); err != nil {
 							t.log.Error("❌ Failed to send reply: %v", err)
 						}
 					}
 				}
 			}
 			// Then send screenshot as photo
-			if err := t.SendPhoto(chatID, screenshotPath, caption); err != nil {
+			if err := t.sendPhotoTo(ReplyTarget{ChatID: target.ChatID, ThreadID: target.ThreadID}, screenshotPath, caption); err != nil {
 				t.log.Warn("⚠️ Failed to send screenshot: %v (continuing without photo)", err)
 			} else {
 				t.log.Debug("📸 Screenshot sent: %s", screenshotPath)
@@ -548,11 +751,11 @@ func (t *TelegramBot) sendReply(chatID int64, reply *types.Message) {
 				for partIdx, part := range parts {
 					// Attach keyboard to the very last part of the very last message
 					if isLast && partIdx == len(parts)-1 {
-						if err := t.SendMessageWithKeyboard(chatID, part, keyboard, true); err != nil {
+						if err := send(part, &keyboard); err != nil {
 							t.log.Error("❌ Failed to send reply with keyboard: %v", err)
 						}
 					} else {
-						if err := t.SendMessage(chatID, part, true); err != nil {
+						if err := send(part, nil); err != nil {
 							t.log.Error("❌ Failed to send reply part: %v", err)
 						}
 					}
@@ -561,14 +764,14 @@ func (t *TelegramBot) sendReply(chatID int64, reply *types.Message) {
 			return
 		}
 	}
-	
+
 	// No keyboard - split into multiple messages for better UX
 	messages := SplitIntoMessages(reply.Text, 800)
 	for _, msg := range messages {
 		// Each message might still need Telegram-length splitting
 		parts := SplitLongMessage(msg, SafeMessageLength)
 		for _, part := range parts {
-			if err := t.SendMessage(chatID, part, true); err != nil {
+			if err := send(part, nil); err != nil {
 				t.log.Error("❌ Failed to send reply: %v", err)
 			}
 		}
@@ -583,16 +786,32 @@ func (t *TelegramBot) SendMessage(chatID int64, text string, markdown bool) erro
 
 // SendMessageAndGetID sends a message and returns the message ID
 func (t *TelegramBot) SendMessageAndGetID(chatID int64, text string, markdown bool) (int64, error) {
+	return t.SendMessageTo(ReplyTarget{ChatID: chatID}, text, markdown)
+}
+
+// SendMessageTo sends a message to a reply target (chat, forum topic,
+// quoted message) and returns the message ID
+func (t *TelegramBot) SendMessageTo(target ReplyTarget, text string, markdown bool) (int64, error) {
+	return t.sendText(target, text, markdown, nil)
+}
+
+// sendText sends a text message with an optional inline keyboard
+func (t *TelegramBot) sendText(target ReplyTarget, text string, markdown bool, keyboard *InlineKeyboard) (int64, error) {
 	params := map[string]any{
-		"chat_id": chatID,
-		"text":    text,
+		"text": text,
 	}
+	target.apply(params)
 
 	if markdown {
-		params["parse_mode"] = "Markdown"
+		params["text"] = RenderHTML(text)
+		params["parse_mode"] = "HTML"
 	}
 
-	resp, err := t.call("sendMessage", params)
+	if keyboard != nil {
+		params["reply_markup"] = keyboard
+	}
+
+	resp, err := t.callFormatted("sendMessage", params, text)
 	if err != nil {
 		return 0, err
 	}
@@ -603,14 +822,23 @@ func (t *TelegramBot) SendMessageAndGetID(chatID int64, text string, markdown bo
 		return 0, fmt.Errorf("failed to parse sent message: %w", err)
 	}
 
+	target.sent.add(sentMsg.MessageID)
 	return sentMsg.MessageID, nil
 }
 
 // SendTypingAction sends a "typing" indicator to the chat
 func (t *TelegramBot) SendTypingAction(chatID int64) error {
+	return t.sendChatAction(ReplyTarget{ChatID: chatID}, "typing")
+}
+
+// sendChatAction sends a chat action (typing, upload_photo, ...) to a chat or forum topic
+func (t *TelegramBot) sendChatAction(target ReplyTarget, action string) error {
 	params := map[string]any{
-		"chat_id": chatID,
-		"action":  "typing",
+		"chat_id": target.ChatID,
+		"action":  action,
+	}
+	if target.ThreadID != 0 {
+		params["message_thread_id"] = target.ThreadID
 	}
 
 	_, err := t.call("sendChatAction", params)
@@ -619,89 +847,67 @@ func (t *TelegramBot) SendTypingAction(chatID int64) error {
 
 // SendDocument sends a document/file to a chat
 func (t *TelegramBot) SendDocument(chatID int64, filename string, content []byte, caption string) error {
-	url := t.baseURL + "/sendDocument"
-
-	// Create multipart form
-	var buf bytes.Buffer
-	writer := multipart.NewWriter(&buf)
-
-	// Add chat_id field
-	if err := writer.WriteField("chat_id", strconv.FormatInt(chatID, 10)); err != nil {
-		return fmt.Errorf("failed to write chat_id: %w", err)
-	}
-
-	// Add caption if provided
-	if caption != "" {
-		if err := writer.WriteField("caption", caption); err != nil {
-			return fmt.Errorf("failed to write caption: %w", err)
-		}
-	}
-
-	// Add document file
-	part, err := writer.CreateFormFile("document", filename)
-	if err != nil {
-		return fmt.Errorf("failed to create form file: %w", err)
-	}
-	if _, err := part.Write(content); err != nil {
-		return fmt.Errorf("failed to write document: %w", err)
-	}
-
-	// Close writer
-	if err := writer.Close(); err != nil {
-		return fmt.Errorf("failed to close writer: %w", err)
-	}
-
-	// Create request
-	req, err := http.NewRequest(http.MethodPost, url, &buf)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-	req.Header.Set("Content-Type", writer.FormDataContentType())
-
-	// Send request
-	resp, err := t.client.Do(req)
-	if err != nil {
-		return fmt.Errorf("request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Parse response
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read response: %w", err)
-	}
-
-	var tgResp TelegramResponse
-	if err := json.Unmarshal(respBody, &tgResp); err != nil {
-		return fmt.Errorf("failed to parse response: %w", err)
-	}
-
-	if !tgResp.OK {
-		return fmt.Errorf("telegram API error: %s (code: %d)", tgResp.Description, tgResp.ErrorCode)
-	}
-
-	return nil
+	return t.sendMultipart("sendDocument", ReplyTarget{ChatID: chatID}, "document", filename, openBytes(content), caption)
 }
 
 // SendPhoto sends a photo to a chat
 func (t *TelegramBot) SendPhoto(chatID int64, path string, caption string) error {
-	url := t.baseURL + "/sendPhoto"
+	return t.sendPhotoTo(ReplyTarget{ChatID: chatID}, path, caption)
+}
 
-	// Open the file
-	file, err := os.Open(path)
-	if err != nil {
-		return fmt.Errorf("failed to open photo: %w", err)
-	}
-	defer file.Close()
+// sendPhotoTo sends a photo from disk to a reply target
+func (t *TelegramBot) sendPhotoTo(target ReplyTarget, path string, caption string) error {
+	return t.sendFile("sendPhoto", target, "photo", path, caption)
+}
+
+// sendMultipart uploads a file with a multipart/form-data request. The body
+// is streamed from open, which is called again if the upload is retried
+// after a 429, so large files are never held in memory.
+func (t *TelegramBot) sendMultipart(method string, target ReplyTarget, field, filename string, open func() (io.ReadCloser, error), caption string) error {
+	url := t.baseURL + "/" + method
+
+	return t.queue.do(target.ChatID, func() error {
+		content, err := open()
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", field, err)
+		}
+		defer content.Close()
+
+		pr, pw := io.Pipe()
+		writer := multipart.NewWriter(pw)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			pw.CloseWithError(writeMultipart(writer, target, field, filename, content, caption))
+		}()
+		// Unblock the writer if the request ends before the body is consumed
+		defer func() {
+			pr.Close()
+			<-done
+		}()
+
+		req, err := http.NewRequest(http.MethodPost, url, pr)
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Content-Type", writer.FormDataContentType())
 
-	// Create multipart form
-	var buf bytes.Buffer
-	writer := multipart.NewWriter(&buf)
+		_, err = t.do(req)
+		return err
+	})
+}
 
-	// Add chat_id field
-	if err := writer.WriteField("chat_id", strconv.FormatInt(chatID, 10)); err != nil {
+// writeMultipart writes the form fields and file of an upload
+func writeMultipart(writer *multipart.Writer, target ReplyTarget, field, filename string, content io.Reader, caption string) error {
+	// Add chat_id and topic fields
+	if err := writer.WriteField("chat_id", strconv.FormatInt(target.ChatID, 10)); err != nil {
 		return fmt.Errorf("failed to write chat_id: %w", err)
 	}
+	if target.ThreadID != 0 {
+		if err := writer.WriteField("message_thread_id", strconv.FormatInt(target.ThreadID, 10)); err != nil {
+			return fmt.Errorf("failed to write message_thread_id: %w", err)
+		}
+	}
 
 	// Add caption if provided
 	if caption != "" {
@@ -710,49 +916,19 @@ func (t *TelegramBot) SendPhoto(chatID int64, path string, caption string) error
 		}
 	}
 
-	// Add photo file
-	part, err := writer.CreateFormFile("photo", filepath.Base(path))
+	// Add file
+	part, err := writer.CreateFormFile(field, filename)
 	if err != nil {
 		return fmt.Errorf("failed to create form file: %w", err)
 	}
-	if _, err := io.Copy(part, file); err != nil {
-		return fmt.Errorf("failed to copy photo: %w", err)
+	if _, err := io.Copy(part, content); err != nil {
+		return fmt.Errorf("failed to write %s: %w", field, err)
 	}
 
 	// Close writer
 	if err := writer.Close(); err != nil {
 		return fmt.Errorf("failed to close writer: %w", err)
 	}
-
-	// Create request
-	req, err := http.NewRequest(http.MethodPost, url, &buf)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-	req.Header.Set("Content-Type", writer.FormDataContentType())
-
-	// Send request
-	resp, err := t.client.Do(req)
-	if err != nil {
-		return fmt.Errorf("request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Parse response
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read response: %w", err)
-	}
-
-	var tgResp TelegramResponse
-	if err := json.Unmarshal(respBody, &tgResp); err != nil {
-		return fmt.Errorf("failed to parse response: %w", err)
-	}
-
-	if !tgResp.OK {
-		return fmt.Errorf("telegram API error: %s (code: %d)", tgResp.Description, tgResp.ErrorCode)
-	}
-
 	return nil
 }
 
@@ -777,7 +953,16 @@ func (t *TelegramBot) GetFile(fileID string) (*TelegramFile, error) {
 
 // DownloadFile downloads a file from Telegram and returns its contents
 func (t *TelegramBot) DownloadFile(filePath string) ([]byte, error) {
-	url := fmt.Sprintf("https://api.telegram.org/file/bot%s/%s", t.token, filePath)
+	// A local Bot API server returns the file's absolute path on disk
+	if t.localMode && filepath.IsAbs(filePath) {
+		data, err := os.ReadFile(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file: %w", err)
+		}
+		return data, nil
+	}
+
+	url := t.fileURL + "/" + filePath
 
 	resp, err := t.client.Get(url)
 	if err != nil {
@@ -801,24 +986,50 @@ func (t *TelegramBot) DownloadFile(filePath string) ([]byte, error) {
 func (t *TelegramBot) call(method string, params map[string]any) (*TelegramResponse, error) {
 	url := t.baseURL + "/" + method
 
-	var body io.Reader
+	var data []byte
 	if params != nil {
-		data, err := json.Marshal(params)
+		var err error
+		data, err = json.Marshal(params)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal params: %w", err)
 		}
-		body = bytes.NewReader(data)
 	}
 
-	req, err := http.NewRequest(http.MethodPost, url, body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	send := func() (*TelegramResponse, error) {
+		var body io.Reader
+		if data != nil {
+			body = bytes.NewReader(data)
+		}
+
+		req, err := http.NewRequest(http.MethodPost, url, body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		return t.do(req)
 	}
 
-	if body != nil {
-		req.Header.Set("Content-Type", "application/json")
+	// Messages posted into a chat go through its queue for ordering and flood control
+	chatID, queued := queueChat(method, params)
+	if !queued {
+		return send()
 	}
 
+	var resp *TelegramResponse
+	err := t.queue.do(chatID, func() error {
+		var err error
+		resp, err = send()
+		return err
+	})
+	return resp, err
+}
+
+// do performs an API request and decodes the response
+func (t *TelegramBot) do(req *http.Request) (*TelegramResponse, error) {
 	resp, err := t.client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
@@ -836,6 +1047,12 @@ func (t *TelegramBot) call(method string, params map[string]any) (*TelegramRespo
 	}
 
 	if !tgResp.OK {
+		if tgResp.ErrorCode == http.StatusTooManyRequests && tgResp.Parameters != nil {
+			return nil, &RetryAfterError{
+				Description: tgResp.Description,
+				RetryAfter:  time.Duration(tgResp.Parameters.RetryAfter) * time.Second,
+			}
+		}
 		return nil, fmt.Errorf("telegram API error: %s (code: %d)", tgResp.Description, tgResp.ErrorCode)
 	}
 
@@ -912,17 +1129,7 @@ func (t *TelegramBot) AnswerCallbackQuery(queryID string, text string) error {
 
 // SendMessageWithKeyboard sends a message with an inline keyboard
 func (t *TelegramBot) SendMessageWithKeyboard(chatID int64, text string, keyboard InlineKeyboard, markdown bool) error {
-	params := map[string]any{
-		"chat_id":      chatID,
-		"text":         text,
-		"reply_markup": keyboard,
-	}
-
-	if markdown {
-		params["parse_mode"] = "Markdown"
-	}
-
-	_, err := t.call("sendMessage", params)
+	_, err := t.sendText(ReplyTarget{ChatID: chatID}, text, markdown, &keyboard)
 	return err
 }
 
@@ -931,7 +1138,16 @@ func (t *TelegramBot) EditMessageText(chatID int64, messageID int64, text string
 	return t.EditMessageTextMarkdown(chatID, messageID, text, keyboard, true)
 }
 
-// EditMessageTextMarkdown edits a message with optional markdown parsing.
+// DeleteMessage deletes a message the bot sent
+func (t *TelegramBot) DeleteMessage(chatID int64, messageID int64) error {
+	_, err := t.call("deleteMessage", map[string]any{
+		"chat_id":    chatID,
+		"message_id": messageID,
+	})
+	return err
+}
+
+// EditMessageTextMarkdown edits a message with optional markdown formatting.
 func (t *TelegramBot) EditMessageTextMarkdown(chatID int64, messageID int64, text string, keyboard *InlineKeyboard, markdown bool) error {
 	params := map[string]any{
 		"chat_id":    chatID,
@@ -940,13 +1156,29 @@ func (t *TelegramBot) EditMessageTextMarkdown(chatID int64, messageID int64, tex
 	}
 
 	if markdown {
-		params["parse_mode"] = "Markdown"
+		params["text"] = RenderHTML(text)
+		params["parse_mode"] = "HTML"
 	}
 
 	if keyboard != nil {
 		params["reply_markup"] = keyboard
 	}
 
-	_, err := t.call("editMessageText", params)
+	_, err := t.callFormatted("editMessageText", params, text)
 	return err
 }
+
+// callFormatted calls a method that sends formatted text. If Telegram
+// rejects the formatting, the request is retried once as plain text so the
+// message is not lost.
+func (t *TelegramBot) callFormatted(method string, params map[string]any, plain string) (*TelegramResponse, error) {
+	resp, err := t.call(method, params)
+	if _, formatted := params["parse_mode"]; !formatted || !isParseError(err) {
+		return resp, err
+	}
+
+	t.log.Warn("⚠️ Telegram rejected message formatting, resending as plain text: %v", err)
+	delete(params, "parse_mode")
+	params["text"] = plain
+	return t.call(method, params)
+}
@@ -0,0 +1,56 @@
+package channel
+
+import (
+	"strings"
+)
+
+// AccessControl grants access to users approved at runtime, in addition to
+// the static allowedUsers list
+type AccessControl interface {
+	// IsApproved reports whether a Telegram user ID has been granted access
+	IsApproved(userID int64) bool
+	// RequestAccess records an access request (or redeems an invite code)
+	// and returns the reply for the requester
+	RequestAccess(user *TelegramUser, code string) string
+}
+
+// SetAccessControl enables /request and runtime-approved users
+func (t *TelegramBot) SetAccessControl(access AccessControl) {
+	t.access = access
+}
+
+// handleUnauthorized answers a message from a user who is not allowed to use
+// the bot. In private chats users can ask for access with /request, or
+// redeem an invite with "/request CODE" or a t.me/<bot>?start=CODE link.
+func (t *TelegramBot) handleUnauthorized(tgMsg *TelegramMessage, text string) {
+	target := replyTargetFor(tgMsg)
+
+	if t.access == nil || tgMsg.From == nil || isGroupChat(tgMsg.Chat) {
+		_, _ = t.SendMessageTo(target, "⛔ You are not authorized to use this bot.", false)
+		return
+	}
+
+	cmd, code := parseAccessCommand(text)
+	if cmd != "request" && (cmd != "start" || code == "") {
+		_, _ = t.SendMessageTo(target, "⛔ You are not authorized to use this bot. Send /request to ask for access.", false)
+		return
+	}
+
+	_, _ = t.SendMessageTo(target, t.access.RequestAccess(tgMsg.From, code), false)
+}
+
+// parseAccessCommand splits "/request@Bot CODE" into ("request", "CODE")
+func parseAccessCommand(text string) (cmd, code string) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "/") {
+		return "", ""
+	}
+	cmd = strings.ToLower(strings.TrimPrefix(fields[0], "/"))
+	if at := strings.Index(cmd, "@"); at >= 0 {
+		cmd = cmd[:at]
+	}
+	if len(fields) > 1 {
+		code = fields[1]
+	}
+	return cmd, code
+}
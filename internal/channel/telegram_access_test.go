@@ -0,0 +1,57 @@
+package channel
+
+import "testing"
+
+type fakeAccess struct {
+	approved map[int64]bool
+	requests []string
+}
+
+func (f *fakeAccess) IsApproved(userID int64) bool { return f.approved[userID] }
+
+func (f *fakeAccess) RequestAccess(user *TelegramUser, code string) string {
+	f.requests = append(f.requests, code)
+	return "requested"
+}
+
+func TestIsAllowed_AccessControl(t *testing.T) {
+	bot := NewTelegramBot("test-token", nil)
+	bot.SetAllowedUsers([]string{"alice"})
+
+	if bot.IsAllowed("bob", 7, 7) {
+		t.Fatal("bob should not be allowed without access control")
+	}
+
+	bot.SetAccessControl(&fakeAccess{approved: map[int64]bool{7: true}})
+	if !bot.IsAllowed("bob_renamed", 7, 7) {
+		t.Error("approved user should be allowed regardless of username")
+	}
+	if bot.IsAllowed("carol", 8, 8) {
+		t.Error("unapproved user should not be allowed")
+	}
+	if !bot.IsAllowed("alice", 9, 9) {
+		t.Error("allowlisted user should still be allowed")
+	}
+}
+
+func TestParseAccessCommand(t *testing.T) {
+	tests := []struct {
+		text     string
+		wantCmd  string
+		wantCode string
+	}{
+		{"/request", "request", ""},
+		{"/request abc123", "request", "abc123"},
+		{"/Request@PulseBot abc123", "request", "abc123"},
+		{"/start abc123", "start", "abc123"},
+		{"hello", "", ""},
+		{"", "", ""},
+	}
+
+	for _, tt := range tests {
+		cmd, code := parseAccessCommand(tt.text)
+		if cmd != tt.wantCmd || code != tt.wantCode {
+			t.Errorf("parseAccessCommand(%q) = (%q, %q), want (%q, %q)", tt.text, cmd, code, tt.wantCmd, tt.wantCode)
+		}
+	}
+}
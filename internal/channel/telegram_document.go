@@ -0,0 +1,102 @@
+package channel
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// TelegramDocument represents a general file sent as a document
+type TelegramDocument struct {
+	FileID       string `json:"file_id"`
+	FileUniqueID string `json:"file_unique_id"`
+	FileName     string `json:"file_name,omitempty"`
+	MimeType     string `json:"mime_type,omitempty"`
+	FileSize     int    `json:"file_size,omitempty"`
+}
+
+// DocumentStore saves uploaded documents and describes them for the agent
+type DocumentStore interface {
+	// Check validates an upload before download; the error is shown to the user
+	Check(filename, mimeType string, size int64) error
+	// Ingest stores the file for owner and returns a description for the agent
+	Ingest(owner, filename, mimeType string, data []byte) (string, error)
+}
+
+// SetDocumentStore enables document uploads
+func (t *TelegramBot) SetDocumentStore(store DocumentStore) {
+	t.documents = store
+}
+
+// handleDocumentMessage saves an uploaded file and passes a description of
+// it (path, size, content preview) to the agent
+func (t *TelegramBot) handleDocumentMessage(ctx context.Context, tgMsg *TelegramMessage) {
+	if t.handler == nil {
+		return
+	}
+
+	if !t.accept(tgMsg, tgMsg.Caption, tgMsg.CaptionEntities) {
+		return
+	}
+
+	target := replyTargetFor(tgMsg)
+	doc := tgMsg.Document
+
+	if t.documents == nil {
+		_, _ = t.SendMessageTo(target, "📎 File uploads are not enabled.", false)
+		return
+	}
+
+	if err := t.documents.Check(doc.FileName, doc.MimeType, int64(doc.FileSize)); err != nil {
+		t.log.Warn("⚠️ Rejected document %s: %v", doc.FileName, err)
+		_, _ = t.SendMessageTo(target, "⚠️ Can't accept this file: "+err.Error(), false)
+		return
+	}
+
+	if limit := t.downloadLimit(); int64(doc.FileSize) > limit {
+		t.log.Warn("⚠️ Document %s exceeds the Bot API download limit (%d bytes)", doc.FileName, doc.FileSize)
+		_, _ = t.SendMessageTo(target, fmt.Sprintf("⚠️ Can't accept this file: bots can only download files up to %d MB.", limit>>20), false)
+		return
+	}
+
+	_ = t.sendChatAction(target, "typing")
+
+	fileInfo, err := t.GetFile(doc.FileID)
+	if err != nil {
+		t.log.Error("❌ Failed to get file info: %v", err)
+		_, _ = t.SendMessageTo(target, "❌ Failed to process file.", false)
+		return
+	}
+
+	data, err := t.DownloadFile(fileInfo.FilePath)
+	if err != nil {
+		t.log.Error("❌ Failed to download document: %v", err)
+		_, _ = t.SendMessageTo(target, "❌ Failed to download file.", false)
+		return
+	}
+
+	// Files are stored per sender, even in group chats
+	owner := "unknown"
+	if tgMsg.From != nil {
+		owner = strconv.FormatInt(tgMsg.From.ID, 10)
+	}
+
+	description, err := t.documents.Ingest(owner, doc.FileName, doc.MimeType, data)
+	if err != nil {
+		t.log.Error("❌ Failed to save document: %v", err)
+		_, _ = t.SendMessageTo(target, "❌ Failed to save file: "+err.Error(), false)
+		return
+	}
+
+	text := description
+	if caption := t.stripBotMention(tgMsg.Caption); caption != "" {
+		text = caption + "\n\n" + description
+	}
+
+	msg := t.newIncomingMessage(tgMsg, text)
+	msg.Metadata["document"] = doc.FileName
+
+	t.log.Debug("📎 [%s] %s: [Document] %s (%d bytes)", msg.Channel, msg.From, doc.FileName, len(data))
+
+	t.dispatch(target, msg)
+}
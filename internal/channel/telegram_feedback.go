@@ -0,0 +1,103 @@
+package channel
+
+import (
+	"sync"
+
+	"github.com/FeelPulse/feelpulse/pkg/types"
+)
+
+// MessageReactionUpdated is a change of a user's reaction to a message
+type MessageReactionUpdated struct {
+	Chat        *TelegramChat  `json:"chat"`
+	MessageID   int64          `json:"message_id"`
+	User        *TelegramUser  `json:"user,omitempty"` // Absent for anonymous reactions
+	Date        int64          `json:"date"`
+	OldReaction []ReactionType `json:"old_reaction"`
+	NewReaction []ReactionType `json:"new_reaction"`
+}
+
+// ReactionType is an emoji (or custom emoji) reaction
+type ReactionType struct {
+	Type          string `json:"type"` // emoji, custom_emoji, paid
+	Emoji         string `json:"emoji,omitempty"`
+	CustomEmojiID string `json:"custom_emoji_id,omitempty"`
+}
+
+// FeedbackRecorder links sent replies to the reactions users leave on them
+type FeedbackRecorder interface {
+	// RecordSent stores the IDs of the messages that delivered reply to msg
+	RecordSent(chatID int64, messageIDs []int64, msg, reply *types.Message) error
+	// RecordReaction stores a user's reaction; an empty emoji removes it
+	RecordReaction(chatID, messageID, userID int64, emoji string) error
+}
+
+// SetFeedbackRecorder enables reaction tracking
+func (t *TelegramBot) SetFeedbackRecorder(feedback FeedbackRecorder) {
+	t.feedback = feedback
+}
+
+// sentLog collects the IDs of messages sent for one reply (streamed,
+// intermediate and final messages alike)
+type sentLog struct {
+	mu  sync.Mutex
+	ids []int64
+}
+
+func (l *sentLog) add(id int64) {
+	if l == nil || id == 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, existing := range l.ids {
+		if existing == id {
+			return
+		}
+	}
+	l.ids = append(l.ids, id)
+}
+
+func (l *sentLog) list() []int64 {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]int64(nil), l.ids...)
+}
+
+// recordSent links the messages sent for a reply to it
+func (t *TelegramBot) recordSent(target ReplyTarget, msg, reply *types.Message) {
+	ids := target.sent.list()
+	if t.feedback == nil || reply == nil || len(ids) == 0 {
+		return
+	}
+	if err := t.feedback.RecordSent(target.ChatID, ids, msg, reply); err != nil {
+		t.log.Warn("⚠️ Failed to record sent messages: %v", err)
+	}
+}
+
+// handleReaction records a reaction to one of the bot's messages
+func (t *TelegramBot) handleReaction(reaction *MessageReactionUpdated) {
+	if t.feedback == nil || reaction.User == nil || reaction.Chat == nil {
+		return
+	}
+	if !t.IsAllowed(reaction.User.Username, reaction.User.ID, reaction.Chat.ID) {
+		return
+	}
+
+	// Users can set several reactions; the first emoji counts
+	var emoji string
+	for _, r := range reaction.NewReaction {
+		if r.Type == "emoji" {
+			emoji = r.Emoji
+			break
+		}
+	}
+
+	t.log.Debug("👍 Reaction %q on message %d in chat %d", emoji, reaction.MessageID, reaction.Chat.ID)
+
+	if err := t.feedback.RecordReaction(reaction.Chat.ID, reaction.MessageID, reaction.User.ID, emoji); err != nil {
+		t.log.Warn("⚠️ Failed to record reaction: %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+package channel
+
+import (
+	"testing"
+
+	"github.com/FeelPulse/feelpulse/pkg/types"
+)
+
+type fakeFeedback struct {
+	sent      []int64
+	reactions []string
+}
+
+func (f *fakeFeedback) RecordSent(chatID int64, messageIDs []int64, msg, reply *types.Message) error {
+	f.sent = append(f.sent, messageIDs...)
+	return nil
+}
+
+func (f *fakeFeedback) RecordReaction(chatID, messageID, userID int64, emoji string) error {
+	f.reactions = append(f.reactions, emoji)
+	return nil
+}
+
+func TestSentLog(t *testing.T) {
+	var nilLog *sentLog
+	nilLog.add(1) // Must not panic
+	if ids := nilLog.list(); ids != nil {
+		t.Errorf("nil log list = %v, want nil", ids)
+	}
+
+	log := &sentLog{}
+	log.add(10)
+	log.add(0)
+	log.add(11)
+	log.add(10)
+	if ids := log.list(); len(ids) != 2 || ids[0] != 10 || ids[1] != 11 {
+		t.Errorf("list = %v, want [10 11]", ids)
+	}
+}
+
+func TestRecordSent(t *testing.T) {
+	bot := NewTelegramBot("test-token", nil)
+	fb := &fakeFeedback{}
+	bot.SetFeedbackRecorder(fb)
+
+	target := ReplyTarget{ChatID: 1, sent: &sentLog{}}
+	target.sent.add(5)
+	target.sent.add(6)
+
+	bot.recordSent(target, &types.Message{}, &types.Message{ID: "msg-1"})
+	if len(fb.sent) != 2 {
+		t.Errorf("sent = %v, want 2 message IDs", fb.sent)
+	}
+
+	fb.sent = nil
+	bot.recordSent(ReplyTarget{ChatID: 1, sent: &sentLog{}}, &types.Message{}, &types.Message{})
+	if len(fb.sent) != 0 {
+		t.Errorf("nothing sent should record nothing, got %v", fb.sent)
+	}
+}
+
+func TestHandleReaction(t *testing.T) {
+	bot := NewTelegramBot("test-token", nil)
+	bot.SetAllowedUsers([]string{"alice"})
+	fb := &fakeFeedback{}
+	bot.SetFeedbackRecorder(fb)
+
+	chat := &TelegramChat{ID: 1, Type: "private"}
+	alice := &TelegramUser{ID: 1, Username: "alice"}
+
+	bot.handleReaction(&MessageReactionUpdated{
+		Chat: chat, MessageID: 5, User: alice,
+		NewReaction: []ReactionType{{Type: "custom_emoji", CustomEmojiID: "x"}, {Type: "emoji", Emoji: "👍"}},
+	})
+	bot.handleReaction(&MessageReactionUpdated{Chat: chat, MessageID: 5, User: alice})
+	bot.handleReaction(&MessageReactionUpdated{
+		Chat: chat, MessageID: 5, User: &TelegramUser{ID: 2, Username: "mallory"},
+		NewReaction: []ReactionType{{Type: "emoji", Emoji: "👎"}},
+	})
+	bot.handleReaction(&MessageReactionUpdated{Chat: chat, MessageID: 5}) // Anonymous
+
+	if len(fb.reactions) != 2 || fb.reactions[0] != "👍" || fb.reactions[1] != "" {
+		t.Errorf("reactions = %q, want [👍 \"\"]", fb.reactions)
+	}
+}
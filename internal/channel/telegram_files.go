@@ -0,0 +1,98 @@
+package channel
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultAPIURL is the public Telegram Bot API server
+const DefaultAPIURL = "https://api.telegram.org"
+
+// File size limits of the public Bot API server. A self-hosted server in
+// local mode accepts files up to 2000 MB in both directions.
+const (
+	cloudUploadLimit   = 50 << 20
+	cloudDownloadLimit = 20 << 20
+	localFileLimit     = 2000 << 20
+)
+
+// SetAPIServer points the bot at another Bot API server, such as a
+// self-hosted telegram-bot-api or a fake server in tests. fileURL defaults
+// to apiURL + "/file". In local mode files are exchanged as paths on disk.
+// Must be called before Start.
+func (t *TelegramBot) SetAPIServer(apiURL, fileURL string, local bool) {
+	apiURL = strings.TrimRight(apiURL, "/")
+	if apiURL == "" {
+		apiURL = DefaultAPIURL
+	}
+	fileURL = strings.TrimRight(fileURL, "/")
+	if fileURL == "" {
+		fileURL = apiURL + "/file"
+	}
+
+	t.baseURL = apiURL + "/bot" + t.token
+	t.fileURL = fileURL + "/bot" + t.token
+	t.localMode = local
+}
+
+// uploadLimit returns the largest file the server accepts
+func (t *TelegramBot) uploadLimit() int64 {
+	if t.localMode {
+		return localFileLimit
+	}
+	return cloudUploadLimit
+}
+
+// downloadLimit returns the largest file the bot can download
+func (t *TelegramBot) downloadLimit() int64 {
+	if t.localMode {
+		return localFileLimit
+	}
+	return cloudDownloadLimit
+}
+
+// sendFile sends a file from disk without loading it into memory. A local
+// Bot API server reads it directly; otherwise it is streamed as an upload.
+func (t *TelegramBot) sendFile(method string, target ReplyTarget, field, path, caption string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", field, err)
+	}
+	if limit := t.uploadLimit(); info.Size() > limit {
+		return fmt.Errorf("%s is too large to upload (%d MB, limit %d MB)", filepath.Base(path), info.Size()>>20, limit>>20)
+	}
+
+	if !t.localMode {
+		return t.sendMultipart(method, target, field, filepath.Base(path), func() (io.ReadCloser, error) {
+			return os.Open(path)
+		}, caption)
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", path, err)
+	}
+	params := map[string]any{
+		"chat_id": target.ChatID,
+		field:     "file://" + abs,
+	}
+	if target.ThreadID != 0 {
+		params["message_thread_id"] = target.ThreadID
+	}
+	if caption != "" {
+		params["caption"] = caption
+	}
+	_, err = t.call(method, params)
+	return err
+}
+
+// openBytes adapts in-memory content for sendMultipart
+func openBytes(content []byte) func() (io.ReadCloser, error) {
+	return func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(content)), nil
+	}
+}
@@ -0,0 +1,156 @@
+package channel
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeBotAPI records requests to a fake Bot API server
+type fakeBotAPI struct {
+	mu       sync.Mutex
+	methods  []string
+	uploads  map[string]string // form field -> uploaded content
+	params   map[string]any    // last JSON request
+	rateOnce bool              // answer the first request with 429
+}
+
+func newFakeBotAPI(t *testing.T) (*fakeBotAPI, *TelegramBot) {
+	api := &fakeBotAPI{uploads: make(map[string]string)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/bottest-token/", func(w http.ResponseWriter, r *http.Request) {
+		api.mu.Lock()
+		defer api.mu.Unlock()
+
+		api.methods = append(api.methods, strings.TrimPrefix(r.URL.Path, "/bottest-token/"))
+		if api.rateOnce {
+			api.rateOnce = false
+			io.Copy(io.Discard, r.Body)
+			w.Write([]byte(`{"ok":false,"error_code":429,"description":"Too Many Requests","parameters":{"retry_after":0}}`))
+			return
+		}
+
+		if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/") {
+			reader, err := r.MultipartReader()
+			if err != nil {
+				t.Errorf("bad multipart body: %v", err)
+				return
+			}
+			for {
+				part, err := reader.NextPart()
+				if err != nil {
+					break
+				}
+				data, _ := io.ReadAll(part)
+				api.uploads[part.FormName()] = string(data)
+			}
+		} else {
+			api.params = nil
+			json.NewDecoder(r.Body).Decode(&api.params)
+		}
+		w.Write([]byte(`{"ok":true,"result":{"message_id":1}}`))
+	})
+	mux.HandleFunc("/files/bottest-token/docs/a.txt", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("downloaded"))
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	bot := NewTelegramBot("test-token", nil)
+	bot.queue = newTestQueue()
+	bot.SetAPIServer(srv.URL+"/", srv.URL+"/files", false)
+	return api, bot
+}
+
+func TestSendFile_StreamsUpload(t *testing.T) {
+	api, bot := newFakeBotAPI(t)
+	api.rateOnce = true
+
+	path := filepath.Join(t.TempDir(), "report.txt")
+	if err := os.WriteFile(path, []byte("file contents"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := bot.sendFile("sendDocument", ReplyTarget{ChatID: 42}, "document", path, "Report"); err != nil {
+		t.Fatalf("sendFile() error: %v", err)
+	}
+
+	// The retry after the 429 re-reads the file from disk
+	if len(api.methods) != 2 || api.methods[1] != "sendDocument" {
+		t.Errorf("expected a retried sendDocument, got %v", api.methods)
+	}
+	if api.uploads["document"] != "file contents" {
+		t.Errorf("uploaded document = %q", api.uploads["document"])
+	}
+	if api.uploads["chat_id"] != "42" || api.uploads["caption"] != "Report" {
+		t.Errorf("unexpected form fields: %v", api.uploads)
+	}
+}
+
+func TestSendFile_LocalMode(t *testing.T) {
+	api, bot := newFakeBotAPI(t)
+	bot.localMode = true
+
+	path := filepath.Join(t.TempDir(), "shot.png")
+	if err := os.WriteFile(path, []byte("png"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := bot.sendPhotoTo(ReplyTarget{ChatID: 42, ThreadID: 5}, path, ""); err != nil {
+		t.Fatalf("sendPhotoTo() error: %v", err)
+	}
+	if got := api.params["photo"]; got != "file://"+path {
+		t.Errorf("photo = %v, want local file URI", got)
+	}
+	if len(api.uploads) != 0 {
+		t.Error("local mode should not upload the file")
+	}
+}
+
+func TestSendFile_TooLarge(t *testing.T) {
+	_, bot := newFakeBotAPI(t)
+
+	path := filepath.Join(t.TempDir(), "big.bin")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Truncate(cloudUploadLimit + 1)
+	f.Close()
+
+	if err := bot.sendPhotoTo(ReplyTarget{ChatID: 42}, path, ""); err == nil || !strings.Contains(err.Error(), "too large") {
+		t.Errorf("expected size error, got %v", err)
+	}
+
+	// A local server accepts it
+	bot.localMode = true
+	if err := bot.sendPhotoTo(ReplyTarget{ChatID: 42}, path, ""); err != nil {
+		t.Errorf("local mode should accept large files, got %v", err)
+	}
+}
+
+func TestDownloadFile_CustomServer(t *testing.T) {
+	_, bot := newFakeBotAPI(t)
+
+	data, err := bot.DownloadFile("docs/a.txt")
+	if err != nil || string(data) != "downloaded" {
+		t.Errorf("DownloadFile() = %q, %v", data, err)
+	}
+
+	// Local mode reads absolute paths from disk
+	bot.localMode = true
+	path := filepath.Join(t.TempDir(), "voice.ogg")
+	os.WriteFile(path, []byte("ogg"), 0644)
+	data, err = bot.DownloadFile(path)
+	if err != nil || string(data) != "ogg" {
+		t.Errorf("DownloadFile(local) = %q, %v", data, err)
+	}
+}
@@ -0,0 +1,186 @@
+package channel
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// GroupPolicy controls how the bot behaves in group and supergroup chats
+type GroupPolicy struct {
+	RequireMention bool           // Only respond when mentioned, replied to, or sent a command
+	TopicSessions  bool           // Use a separate session per forum topic
+	Chats          map[int64]bool // Per-chat requireMention overrides keyed by chat ID
+}
+
+// DefaultGroupPolicy returns the default group behavior: respond only when
+// addressed, with one session per forum topic
+func DefaultGroupPolicy() GroupPolicy {
+	return GroupPolicy{
+		RequireMention: true,
+		TopicSessions:  true,
+	}
+}
+
+// requiresMention reports whether the bot must be addressed in the given chat
+func (p GroupPolicy) requiresMention(chatID int64) bool {
+	if v, ok := p.Chats[chatID]; ok {
+		return v
+	}
+	return p.RequireMention
+}
+
+// ReplyTarget describes where a reply should be delivered
+type ReplyTarget struct {
+	ChatID    int64
+	ThreadID  int64 // Forum topic (message_thread_id), 0 = none
+	ReplyToID int64 // Message to reply to, 0 = none
+
+	sent *sentLog // Collects IDs of messages sent for a reply, nil = not tracked
+}
+
+// SetGroupPolicy sets the group chat behavior
+func (t *TelegramBot) SetGroupPolicy(policy GroupPolicy) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.groupPolicy = policy
+}
+
+// isGroupChat reports whether a chat is a group or supergroup
+func isGroupChat(chat *TelegramChat) bool {
+	return chat != nil && (chat.Type == "group" || chat.Type == "supergroup")
+}
+
+// isAddressed reports whether a group message is meant for the bot: an
+// @mention, a reply to one of the bot's messages, or a command. Private
+// chats are always addressed.
+func (t *TelegramBot) isAddressed(tgMsg *TelegramMessage, text string, entities []MessageEntity) bool {
+	if !isGroupChat(tgMsg.Chat) {
+		return true
+	}
+
+	t.mu.Lock()
+	policy := t.groupPolicy
+	me, mention := t.me, t.mention
+	t.mu.Unlock()
+
+	if !policy.requiresMention(tgMsg.Chat.ID) {
+		return true
+	}
+
+	// Without bot identity we cannot detect mentions; stay quiet
+	if me == nil {
+		return false
+	}
+
+	// Reply to one of our messages
+	if reply := tgMsg.ReplyToMessage; reply != nil && reply.From != nil && reply.From.ID == me.ID {
+		return true
+	}
+
+	// Commands without a target go to every bot; "/cmd@name" only to that bot
+	if strings.HasPrefix(text, "/") {
+		cmd := strings.Fields(text)[0]
+		if at := strings.Index(cmd, "@"); at >= 0 {
+			return strings.EqualFold(cmd[at+1:], me.Username)
+		}
+		return true
+	}
+
+	// Mention by user (users without a username)
+	for _, e := range entities {
+		if e.Type == "text_mention" && e.User != nil && e.User.ID == me.ID {
+			return true
+		}
+	}
+
+	return mention != nil && mention.MatchString(text)
+}
+
+// stripBotMention removes @botname mentions so the agent and command parser
+// see a clean message ("/new@MyBot" becomes "/new")
+func (t *TelegramBot) stripBotMention(text string) string {
+	t.mu.Lock()
+	mention := t.mention
+	t.mu.Unlock()
+
+	if mention == nil {
+		return text
+	}
+	stripped := strings.TrimSpace(mention.ReplaceAllString(text, ""))
+	if stripped == "" {
+		return text
+	}
+	return stripped
+}
+
+// mentionPattern returns a pattern matching "@username" case-insensitively
+// as a whole word. It is compiled once, when the bot learns its username.
+func mentionPattern(username string) *regexp.Regexp {
+	return regexp.MustCompile(`(?i)@` + regexp.QuoteMeta(username) + `\b`)
+}
+
+// sessionID returns the shared session identifier for group messages:
+// the chat ID, or "chatID/threadID" for forum topics when topic sessions
+// are enabled. Private chats return "" (the session is keyed by user).
+func (t *TelegramBot) sessionID(tgMsg *TelegramMessage) string {
+	if !isGroupChat(tgMsg.Chat) {
+		return ""
+	}
+
+	t.mu.Lock()
+	topicSessions := t.groupPolicy.TopicSessions
+	t.mu.Unlock()
+
+	if topicSessions && tgMsg.Chat.IsForum && tgMsg.IsTopicMessage && tgMsg.MessageThreadID != 0 {
+		return fmt.Sprintf("%d/%d", tgMsg.Chat.ID, tgMsg.MessageThreadID)
+	}
+	return strconv.FormatInt(tgMsg.Chat.ID, 10)
+}
+
+// ParseTarget converts a session user ID back into a chat target: a user or
+// chat ID ("123", "-100123") or a forum topic ("-100123/45")
+func ParseTarget(id string) (ReplyTarget, bool) {
+	chat, thread, hasThread := strings.Cut(id, "/")
+	chatID, err := strconv.ParseInt(chat, 10, 64)
+	if err != nil || chatID == 0 {
+		return ReplyTarget{}, false
+	}
+	target := ReplyTarget{ChatID: chatID}
+	if hasThread {
+		threadID, err := strconv.ParseInt(thread, 10, 64)
+		if err != nil {
+			return ReplyTarget{}, false
+		}
+		target.ThreadID = threadID
+	}
+	return target, true
+}
+
+// replyTargetFor returns where replies to a message should go. Group replies
+// quote the triggering message; forum replies stay in their topic.
+func replyTargetFor(tgMsg *TelegramMessage) ReplyTarget {
+	target := ReplyTarget{ChatID: tgMsg.Chat.ID}
+	if tgMsg.IsTopicMessage {
+		target.ThreadID = tgMsg.MessageThreadID
+	}
+	if isGroupChat(tgMsg.Chat) {
+		target.ReplyToID = tgMsg.MessageID
+	}
+	return target
+}
+
+// apply adds the target's routing fields to Bot API parameters
+func (rt ReplyTarget) apply(params map[string]any) {
+	params["chat_id"] = rt.ChatID
+	if rt.ThreadID != 0 {
+		params["message_thread_id"] = rt.ThreadID
+	}
+	if rt.ReplyToID != 0 {
+		params["reply_parameters"] = map[string]any{
+			"message_id":                  rt.ReplyToID,
+			"allow_sending_without_reply": true,
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package channel
+
+import (
+	"testing"
+)
+
+func newGroupTestBot() *TelegramBot {
+	bot := NewTelegramBot("test-token", nil)
+	bot.me = &TelegramUser{ID: 42, IsBot: true, Username: "PulseBot"}
+	bot.mention = mentionPattern("PulseBot")
+	return bot
+}
+
+func groupMessage(text string) *TelegramMessage {
+	return &TelegramMessage{
+		MessageID: 7,
+		From:      &TelegramUser{ID: 1, Username: "alice"},
+		Chat:      &TelegramChat{ID: -100123, Type: "supergroup"},
+		Text:      text,
+	}
+}
+
+func TestIsAddressed(t *testing.T) {
+	tests := []struct {
+		name     string
+		msg      *TelegramMessage
+		expected bool
+	}{
+		{
+			name:     "private chat always addressed",
+			msg:      &TelegramMessage{Chat: &TelegramChat{ID: 1, Type: "private"}, Text: "hello"},
+			expected: true,
+		},
+		{
+			name:     "plain group message ignored",
+			msg:      groupMessage("hello everyone"),
+			expected: false,
+		},
+		{
+			name:     "mention is addressed",
+			msg:      groupMessage("hey @pulsebot what's up"),
+			expected: true,
+		},
+		{
+			name:     "mention of another bot ignored",
+			msg:      groupMessage("hey @PulseBotter"),
+			expected: false,
+		},
+		{
+			name:     "bare command addressed",
+			msg:      groupMessage("/new"),
+			expected: true,
+		},
+		{
+			name:     "command for this bot addressed",
+			msg:      groupMessage("/help@PulseBot"),
+			expected: true,
+		},
+		{
+			name:     "command for another bot ignored",
+			msg:      groupMessage("/help@OtherBot"),
+			expected: false,
+		},
+		{
+			name: "reply to bot addressed",
+			msg: func() *TelegramMessage {
+				m := groupMessage("and then?")
+				m.ReplyToMessage = &TelegramMessage{From: &TelegramUser{ID: 42, IsBot: true}}
+				return m
+			}(),
+			expected: true,
+		},
+		{
+			name: "reply to someone else ignored",
+			msg: func() *TelegramMessage {
+				m := groupMessage("and then?")
+				m.ReplyToMessage = &TelegramMessage{From: &TelegramUser{ID: 2}}
+				return m
+			}(),
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot := newGroupTestBot()
+			if got := bot.isAddressed(tt.msg, tt.msg.Text, tt.msg.Entities); got != tt.expected {
+				t.Errorf("isAddressed(%q) = %v, want %v", tt.msg.Text, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIsAddressed_TextMention(t *testing.T) {
+	bot := newGroupTestBot()
+	msg := groupMessage("Pulse, summarize this")
+	entities := []MessageEntity{{Type: "text_mention", Offset: 0, Length: 5, User: &TelegramUser{ID: 42}}}
+	if !bot.isAddressed(msg, msg.Text, entities) {
+		t.Error("text_mention of the bot should be addressed")
+	}
+}
+
+func TestIsAddressed_PolicyOverrides(t *testing.T) {
+	bot := newGroupTestBot()
+	msg := groupMessage("hello everyone")
+
+	bot.SetGroupPolicy(GroupPolicy{RequireMention: false})
+	if !bot.isAddressed(msg, msg.Text, nil) {
+		t.Error("expected all messages addressed when mention not required")
+	}
+
+	bot.SetGroupPolicy(GroupPolicy{RequireMention: false, Chats: map[int64]bool{-100123: true}})
+	if bot.isAddressed(msg, msg.Text, nil) {
+		t.Error("per-chat override should require a mention")
+	}
+
+	bot.SetGroupPolicy(GroupPolicy{RequireMention: true, Chats: map[int64]bool{-100123: false}})
+	if !bot.isAddressed(msg, msg.Text, nil) {
+		t.Error("per-chat override should disable the mention requirement")
+	}
+}
+
+func TestStripBotMention(t *testing.T) {
+	bot := newGroupTestBot()
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"/new@PulseBot", "/new"},
+		{"/model@pulsebot sonnet", "/model sonnet"},
+		{"@PulseBot what time is it?", "what time is it?"},
+		{"hello @OtherBot", "hello @OtherBot"},
+		{"@PulseBot", "@PulseBot"},
+	}
+
+	for _, tt := range tests {
+		if got := bot.stripBotMention(tt.input); got != tt.expected {
+			t.Errorf("stripBotMention(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestSessionID(t *testing.T) {
+	bot := newGroupTestBot()
+
+	private := &TelegramMessage{Chat: &TelegramChat{ID: 5, Type: "private"}}
+	if got := bot.sessionID(private); got != "" {
+		t.Errorf("private chat sessionID = %q, want empty", got)
+	}
+
+	group := groupMessage("hi")
+	if got := bot.sessionID(group); got != "-100123" {
+		t.Errorf("group sessionID = %q, want -100123", got)
+	}
+
+	topic := groupMessage("hi")
+	topic.Chat.IsForum = true
+	topic.IsTopicMessage = true
+	topic.MessageThreadID = 9
+	if got := bot.sessionID(topic); got != "-100123/9" {
+		t.Errorf("topic sessionID = %q, want -100123/9", got)
+	}
+
+	bot.SetGroupPolicy(GroupPolicy{RequireMention: true, TopicSessions: false})
+	if got := bot.sessionID(topic); got != "-100123" {
+		t.Errorf("topic sessionID without topic sessions = %q, want -100123", got)
+	}
+}
+
+func TestReplyTargetFor(t *testing.T) {
+	private := replyTargetFor(&TelegramMessage{MessageID: 3, Chat: &TelegramChat{ID: 5, Type: "private"}})
+	if private != (ReplyTarget{ChatID: 5}) {
+		t.Errorf("private target = %+v", private)
+	}
+
+	msg := groupMessage("hi")
+	msg.IsTopicMessage = true
+	msg.MessageThreadID = 9
+	target := replyTargetFor(msg)
+	expected := ReplyTarget{ChatID: -100123, ThreadID: 9, ReplyToID: 7}
+	if target != expected {
+		t.Errorf("topic target = %+v, want %+v", target, expected)
+	}
+
+	params := map[string]any{}
+	target.apply(params)
+	if params["message_thread_id"] != int64(9) {
+		t.Errorf("expected message_thread_id 9, got %v", params["message_thread_id"])
+	}
+	if _, ok := params["reply_parameters"]; !ok {
+		t.Error("expected reply_parameters to be set")
+	}
+}
+
+func TestParseTarget(t *testing.T) {
+	tests := []struct {
+		id       string
+		expected ReplyTarget
+		ok       bool
+	}{
+		{"12345", ReplyTarget{ChatID: 12345}, true},
+		{"-100123", ReplyTarget{ChatID: -100123}, true},
+		{"-100123/9", ReplyTarget{ChatID: -100123, ThreadID: 9}, true},
+		{"alice", ReplyTarget{}, false},
+		{"-100123/x", ReplyTarget{}, false},
+		{"", ReplyTarget{}, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParseTarget(tt.id)
+		if ok != tt.ok || got != tt.expected {
+			t.Errorf("ParseTarget(%q) = %+v, %v; want %+v, %v", tt.id, got, ok, tt.expected, tt.ok)
+		}
+	}
+}
+
+func TestIsAllowed(t *testing.T) {
+	bot := NewTelegramBot("test-token", nil)
+	bot.SetAllowedUsers([]string{"alice", "12345", "-100999"})
+
+	tests := []struct {
+		name     string
+		username string
+		userID   int64
+		chatID   int64
+		expected bool
+	}{
+		{"username match", "alice", 1, 1, true},
+		{"user ID match", "", 12345, 12345, true},
+		{"chat ID match", "bob", 2, -100999, true},
+		{"no match", "bob", 2, -100123, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := bot.IsAllowed(tt.username, tt.userID, tt.chatID); got != tt.expected {
+				t.Errorf("IsAllowed(%q, %d, %d) = %v, want %v", tt.username, tt.userID, tt.chatID, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package channel
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/FeelPulse/feelpulse/pkg/types"
+)
+
+// TelegramLocation represents a point on the map
+type TelegramLocation struct {
+	Latitude           float64 `json:"latitude"`
+	Longitude          float64 `json:"longitude"`
+	HorizontalAccuracy float64 `json:"horizontal_accuracy,omitempty"` // Meters
+	LivePeriod         int     `json:"live_period,omitempty"`         // Seconds; set for live locations
+	Heading            int     `json:"heading,omitempty"`
+}
+
+// TelegramVenue represents a named place
+type TelegramVenue struct {
+	Location     TelegramLocation `json:"location"`
+	Title        string           `json:"title"`
+	Address      string           `json:"address"`
+	FoursquareID string           `json:"foursquare_id,omitempty"`
+}
+
+// LocationUpdater stores locations refreshed by live-location updates
+type LocationUpdater interface {
+	// UpdateLocation receives a message carrying location metadata; it is
+	// not passed to the agent
+	UpdateLocation(msg *types.Message)
+}
+
+// SetLiveLocation enables refreshing the stored location from live-location
+// updates (nil = updates are ignored)
+func (t *TelegramBot) SetLiveLocation(updater LocationUpdater) {
+	t.locations = updater
+}
+
+// handleLocationMessage passes a shared location or venue to the agent as a
+// readable description with the coordinates in metadata
+func (t *TelegramBot) handleLocationMessage(tgMsg *TelegramMessage) {
+	if t.handler == nil {
+		return
+	}
+
+	if !t.accept(tgMsg, "", nil) {
+		return
+	}
+
+	msg := t.newIncomingMessage(tgMsg, describeLocation(tgMsg))
+	addLocationMetadata(msg, tgMsg)
+
+	t.log.Debug("📍 [%s] %s: %s", msg.Channel, msg.From, msg.Text)
+
+	t.dispatch(replyTargetFor(tgMsg), msg)
+}
+
+// handleLiveLocation refreshes the stored location when a live location
+// moves. Only private chats are tracked, since groups share one session.
+func (t *TelegramBot) handleLiveLocation(tgMsg *TelegramMessage) {
+	if t.locations == nil || tgMsg.From == nil || isGroupChat(tgMsg.Chat) {
+		return
+	}
+	if !t.IsAllowed(tgMsg.From.Username, tgMsg.From.ID, tgMsg.Chat.ID) {
+		return
+	}
+
+	msg := t.newIncomingMessage(tgMsg, describeLocation(tgMsg))
+	addLocationMetadata(msg, tgMsg)
+
+	t.log.Debug("📍 [%s] %s moved: %.5f, %.5f", msg.Channel, msg.From, tgMsg.Location.Latitude, tgMsg.Location.Longitude)
+
+	t.locations.UpdateLocation(msg)
+}
+
+// addLocationMetadata records the coordinates (and venue) of a message
+func addLocationMetadata(msg *types.Message, tgMsg *TelegramMessage) {
+	loc := tgMsg.Location
+	if tgMsg.Venue != nil {
+		loc = &tgMsg.Venue.Location
+		msg.Metadata["venue_title"] = tgMsg.Venue.Title
+		msg.Metadata["venue_address"] = tgMsg.Venue.Address
+	}
+
+	msg.Metadata["latitude"] = loc.Latitude
+	msg.Metadata["longitude"] = loc.Longitude
+	if loc.HorizontalAccuracy > 0 {
+		msg.Metadata["location_accuracy"] = loc.HorizontalAccuracy
+	}
+	if tgMsg.Location != nil && tgMsg.Location.LivePeriod > 0 {
+		msg.Metadata["live_period"] = tgMsg.Location.LivePeriod
+	}
+}
+
+// describeLocation renders a location or venue as text for the agent
+func describeLocation(tgMsg *TelegramMessage) string {
+	if v := tgMsg.Venue; v != nil {
+		name := v.Title
+		if v.Address != "" {
+			name += ", " + v.Address
+		}
+		return fmt.Sprintf("📍 Shared venue: %s (%.6f, %.6f)", name, v.Location.Latitude, v.Location.Longitude)
+	}
+
+	loc := tgMsg.Location
+	var sb strings.Builder
+	sb.WriteString("📍 Shared ")
+	if loc.LivePeriod > 0 {
+		sb.WriteString("live ")
+	}
+	sb.WriteString(fmt.Sprintf("location: %.6f, %.6f", loc.Latitude, loc.Longitude))
+	if loc.HorizontalAccuracy > 0 {
+		sb.WriteString(fmt.Sprintf(" (±%.0f m)", loc.HorizontalAccuracy))
+	}
+	return sb.String()
+}
@@ -0,0 +1,103 @@
+package channel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FeelPulse/feelpulse/pkg/types"
+)
+
+type fakeLocations struct {
+	updates []*types.Message
+}
+
+func (f *fakeLocations) UpdateLocation(msg *types.Message) {
+	f.updates = append(f.updates, msg)
+}
+
+func TestDescribeLocation(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  *TelegramMessage
+		want string
+	}{
+		{
+			name: "location",
+			msg:  &TelegramMessage{Location: &TelegramLocation{Latitude: 52.52, Longitude: 13.405}},
+			want: "📍 Shared location: 52.520000, 13.405000",
+		},
+		{
+			name: "live location with accuracy",
+			msg:  &TelegramMessage{Location: &TelegramLocation{Latitude: 52.52, Longitude: 13.405, HorizontalAccuracy: 15, LivePeriod: 900}},
+			want: "📍 Shared live location: 52.520000, 13.405000 (±15 m)",
+		},
+		{
+			name: "venue",
+			msg: &TelegramMessage{
+				Location: &TelegramLocation{Latitude: 52.516275, Longitude: 13.377704},
+				Venue: &TelegramVenue{
+					Location: TelegramLocation{Latitude: 52.516275, Longitude: 13.377704},
+					Title:    "Brandenburg Gate",
+					Address:  "Pariser Platz, Berlin",
+				},
+			},
+			want: "📍 Shared venue: Brandenburg Gate, Pariser Platz, Berlin (52.516275, 13.377704)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := describeLocation(tt.msg); got != tt.want {
+				t.Errorf("describeLocation() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddLocationMetadata(t *testing.T) {
+	msg := &types.Message{Metadata: map[string]any{}}
+	addLocationMetadata(msg, &TelegramMessage{
+		Location: &TelegramLocation{Latitude: 1, Longitude: 2, LivePeriod: 60},
+		Venue:    &TelegramVenue{Location: TelegramLocation{Latitude: 1.5, Longitude: 2.5, HorizontalAccuracy: 10}, Title: "Cafe"},
+	})
+
+	if msg.Metadata["latitude"] != 1.5 || msg.Metadata["longitude"] != 2.5 {
+		t.Errorf("expected venue coordinates, got %v", msg.Metadata)
+	}
+	if msg.Metadata["venue_title"] != "Cafe" || msg.Metadata["location_accuracy"] != 10.0 {
+		t.Errorf("unexpected venue metadata: %v", msg.Metadata)
+	}
+	if msg.Metadata["live_period"] != 60 {
+		t.Errorf("expected live_period, got %v", msg.Metadata["live_period"])
+	}
+}
+
+func TestHandleLiveLocation(t *testing.T) {
+	bot := NewTelegramBot("test-token", nil)
+	bot.SetAllowedUsers([]string{"alice"})
+
+	edit := func(chatType, username string) *TelegramUpdate {
+		return &TelegramUpdate{EditedMessage: &TelegramMessage{
+			MessageID: 5,
+			From:      &TelegramUser{ID: 1, Username: username},
+			Chat:      &TelegramChat{ID: 1, Type: chatType},
+			Location:  &TelegramLocation{Latitude: 52.5, Longitude: 13.4, LivePeriod: 900},
+		}}
+	}
+
+	// Ignored unless live locations are enabled
+	bot.handleUpdate(context.Background(), edit("private", "alice"))
+
+	locations := &fakeLocations{}
+	bot.SetLiveLocation(locations)
+	bot.handleUpdate(context.Background(), edit("private", "alice"))
+	bot.handleUpdate(context.Background(), edit("group", "alice"))
+	bot.handleUpdate(context.Background(), edit("private", "mallory"))
+
+	if len(locations.updates) != 1 {
+		t.Fatalf("expected 1 update, got %d", len(locations.updates))
+	}
+	if lat := locations.updates[0].Metadata["latitude"]; lat != 52.5 {
+		t.Errorf("latitude = %v, want 52.5", lat)
+	}
+}
@@ -0,0 +1,204 @@
+package channel
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// chatSendInterval is the minimum gap between messages to one private chat
+	chatSendInterval = time.Second
+	// groupSendInterval keeps group chats under Telegram's 20 messages/minute limit
+	groupSendInterval = 3 * time.Second
+	// globalSendInterval keeps the bot under Telegram's ~30 messages/second limit
+	globalSendInterval = time.Second / 30
+	// maxSendRetries is how often a request is retried after a 429
+	maxSendRetries = 3
+	// chatQueueIdle is how long an idle chat worker is kept around
+	chatQueueIdle = time.Minute
+)
+
+// RetryAfterError is returned when Telegram rejects a request with 429 Too
+// Many Requests
+type RetryAfterError struct {
+	Description string
+	RetryAfter  time.Duration
+}
+
+func (e *RetryAfterError) Error() string {
+	return fmt.Sprintf("telegram API error: %s (code: 429, retry after %s)", e.Description, e.RetryAfter)
+}
+
+// SendMetrics records outbound delivery problems
+type SendMetrics interface {
+	IncrementSendRetry(channel string)
+	IncrementSendFailure(channel string)
+}
+
+// SetMetrics reports queue retries and send failures to m
+func (t *TelegramBot) SetMetrics(m SendMetrics) {
+	t.queue.mu.Lock()
+	defer t.queue.mu.Unlock()
+	t.queue.metrics = m
+}
+
+// sendQueue serializes outbound requests per chat. Each chat has a worker
+// that runs requests in arrival order, spaced to respect Telegram's per-chat
+// and global rate limits, and retried after the delay Telegram advertises
+// on 429 responses.
+type sendQueue struct {
+	chatInterval   time.Duration
+	groupInterval  time.Duration
+	globalInterval time.Duration
+	idle           time.Duration
+	log            func(format string, args ...any)
+
+	mu         sync.Mutex
+	chats      map[int64]*chatQueue
+	nextGlobal time.Time // Next free slot across all chats
+	metrics    SendMetrics
+}
+
+// chatQueue holds the pending requests of one chat
+type chatQueue struct {
+	jobs    chan sendJob
+	pending int       // Jobs submitted but not finished, guarded by sendQueue.mu
+	last    time.Time // Owned by the worker
+}
+
+// sendJob is a request waiting for its turn
+type sendJob struct {
+	fn   func() error
+	done chan error
+}
+
+// newSendQueue creates a queue with Telegram's documented limits
+func newSendQueue(log func(format string, args ...any)) *sendQueue {
+	return &sendQueue{
+		chatInterval:   chatSendInterval,
+		groupInterval:  groupSendInterval,
+		globalInterval: globalSendInterval,
+		idle:           chatQueueIdle,
+		log:            log,
+		chats:          make(map[int64]*chatQueue),
+	}
+}
+
+// do runs fn in the chat's queue and waits for its result
+func (q *sendQueue) do(chatID int64, fn func() error) error {
+	if q == nil {
+		return fn()
+	}
+
+	job := sendJob{fn: fn, done: make(chan error, 1)}
+
+	q.mu.Lock()
+	cq, ok := q.chats[chatID]
+	if !ok {
+		cq = &chatQueue{jobs: make(chan sendJob, 64)}
+		q.chats[chatID] = cq
+		go q.work(chatID, cq)
+	}
+	cq.pending++
+	q.mu.Unlock()
+
+	cq.jobs <- job
+	return <-job.done
+}
+
+// work processes a chat's jobs until it has been idle for a while
+func (q *sendQueue) work(chatID int64, cq *chatQueue) {
+	for {
+		select {
+		case job := <-cq.jobs:
+			job.done <- q.run(chatID, cq, job.fn)
+			q.mu.Lock()
+			cq.pending--
+			q.mu.Unlock()
+		case <-time.After(q.idle):
+			q.mu.Lock()
+			if cq.pending == 0 {
+				delete(q.chats, chatID)
+				q.mu.Unlock()
+				return
+			}
+			q.mu.Unlock()
+		}
+	}
+}
+
+// run executes a job, retrying after 429 responses
+func (q *sendQueue) run(chatID int64, cq *chatQueue, fn func() error) error {
+	for attempt := 0; ; attempt++ {
+		q.wait(chatID, cq)
+		err := fn()
+
+		var retry *RetryAfterError
+		if errors.As(err, &retry) && attempt < maxSendRetries {
+			q.record(func(m SendMetrics) { m.IncrementSendRetry("telegram") })
+			if q.log != nil {
+				q.log("⏳ Rate limited in chat %d, retrying in %s", chatID, retry.RetryAfter)
+			}
+			time.Sleep(retry.RetryAfter)
+			continue
+		}
+		if err != nil {
+			q.record(func(m SendMetrics) { m.IncrementSendFailure("telegram") })
+		}
+		return err
+	}
+}
+
+// wait blocks until both the chat and the bot as a whole may send again
+func (q *sendQueue) wait(chatID int64, cq *chatQueue) {
+	interval := q.chatInterval
+	if chatID < 0 {
+		interval = q.groupInterval
+	}
+	if !cq.last.IsZero() {
+		time.Sleep(time.Until(cq.last.Add(interval)))
+	}
+
+	q.mu.Lock()
+	slot := time.Now()
+	if q.nextGlobal.After(slot) {
+		slot = q.nextGlobal
+	}
+	q.nextGlobal = slot.Add(q.globalInterval)
+	q.mu.Unlock()
+
+	time.Sleep(time.Until(slot))
+	cq.last = time.Now()
+}
+
+// record reports to the metrics sink, if one is set
+func (q *sendQueue) record(fn func(SendMetrics)) {
+	q.mu.Lock()
+	m := q.metrics
+	q.mu.Unlock()
+	if m != nil {
+		fn(m)
+	}
+}
+
+// queuedMethods are the API methods that post into a chat and count
+// against Telegram's flood limits
+var queuedMethods = map[string]bool{
+	"sendMessage":     true,
+	"editMessageText": true,
+	"deleteMessage":   true,
+	"sendPhoto":       true,
+	"sendDocument":    true,
+	"sendVoice":       true,
+}
+
+// queueChat returns the chat a request should be queued under
+func queueChat(method string, params map[string]any) (int64, bool) {
+	if !queuedMethods[method] {
+		return 0, false
+	}
+	chatID, ok := params["chat_id"].(int64)
+	return chatID, ok
+}
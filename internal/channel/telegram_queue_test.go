@@ -0,0 +1,157 @@
+package channel
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeSendMetrics struct {
+	mu       sync.Mutex
+	retries  int
+	failures int
+}
+
+func (m *fakeSendMetrics) IncrementSendRetry(string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.retries++
+}
+
+func (m *fakeSendMetrics) IncrementSendFailure(string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.failures++
+}
+
+func newTestQueue() *sendQueue {
+	q := newSendQueue(nil)
+	q.chatInterval = time.Millisecond
+	q.groupInterval = time.Millisecond
+	q.globalInterval = 0
+	return q
+}
+
+func TestSendQueue_PreservesOrder(t *testing.T) {
+	q := newTestQueue()
+
+	var mu sync.Mutex
+	var got []int
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = q.do(42, func() error {
+				mu.Lock()
+				got = append(got, i)
+				mu.Unlock()
+				return nil
+			})
+		}()
+		// Stagger submissions so arrival order is well defined
+		time.Sleep(2 * time.Millisecond)
+	}
+	wg.Wait()
+
+	for i, v := range got {
+		if v != i {
+			t.Fatalf("messages sent out of order: %v", got)
+		}
+	}
+}
+
+func TestSendQueue_SpacesMessages(t *testing.T) {
+	q := newTestQueue()
+	q.chatInterval = 30 * time.Millisecond
+
+	var times []time.Time
+	for i := 0; i < 3; i++ {
+		_ = q.do(1, func() error {
+			times = append(times, time.Now())
+			return nil
+		})
+	}
+
+	for i := 1; i < len(times); i++ {
+		if gap := times[i].Sub(times[i-1]); gap < 25*time.Millisecond {
+			t.Errorf("gap between messages %d and %d = %s, want >= 30ms", i-1, i, gap)
+		}
+	}
+}
+
+func TestSendQueue_RetriesAfter429(t *testing.T) {
+	q := newTestQueue()
+	m := &fakeSendMetrics{}
+	q.metrics = m
+
+	attempts := 0
+	err := q.do(1, func() error {
+		attempts++
+		if attempts < 3 {
+			return &RetryAfterError{Description: "Too Many Requests", RetryAfter: 5 * time.Millisecond}
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("expected success after retries, got %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("attempts = %d, want 3", attempts)
+	}
+	if m.retries != 2 || m.failures != 0 {
+		t.Errorf("metrics retries=%d failures=%d, want 2/0", m.retries, m.failures)
+	}
+}
+
+func TestSendQueue_GivesUp(t *testing.T) {
+	q := newTestQueue()
+	m := &fakeSendMetrics{}
+	q.metrics = m
+
+	attempts := 0
+	err := q.do(1, func() error {
+		attempts++
+		return &RetryAfterError{Description: "Too Many Requests", RetryAfter: time.Millisecond}
+	})
+
+	var retry *RetryAfterError
+	if !errors.As(err, &retry) {
+		t.Fatalf("expected RetryAfterError, got %v", err)
+	}
+	if attempts != maxSendRetries+1 {
+		t.Errorf("attempts = %d, want %d", attempts, maxSendRetries+1)
+	}
+	if m.failures != 1 {
+		t.Errorf("failures = %d, want 1", m.failures)
+	}
+}
+
+func TestSendQueue_IdleWorkerExits(t *testing.T) {
+	q := newTestQueue()
+	q.idle = 10 * time.Millisecond
+
+	_ = q.do(7, func() error { return nil })
+	time.Sleep(50 * time.Millisecond)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, ok := q.chats[7]; ok {
+		t.Error("idle chat queue should have been removed")
+	}
+}
+
+func TestQueueChat(t *testing.T) {
+	if id, ok := queueChat("sendMessage", map[string]any{"chat_id": int64(5)}); !ok || id != 5 {
+		t.Errorf("sendMessage should be queued for chat 5, got %d %v", id, ok)
+	}
+	if _, ok := queueChat("getUpdates", map[string]any{}); ok {
+		t.Error("getUpdates should not be queued")
+	}
+	if _, ok := queueChat("sendChatAction", map[string]any{"chat_id": int64(5)}); ok {
+		t.Error("chat actions should not be queued")
+	}
+}
@@ -0,0 +1,81 @@
+package channel
+
+import (
+	"context"
+	"strings"
+
+	"github.com/FeelPulse/feelpulse/pkg/types"
+)
+
+// maxReplyContext caps the replied-to text injected into the prompt
+const maxReplyContext = 1000
+
+// addReplyContext records the message the user replied to (or the part of it
+// they quoted) so the agent keeps the reference
+func (t *TelegramBot) addReplyContext(msg *types.Message, tgMsg *TelegramMessage) {
+	reply := tgMsg.ReplyToMessage
+	if reply == nil {
+		return
+	}
+	// In forum topics every message "replies" to the topic's first message
+	if tgMsg.IsTopicMessage && reply.MessageID == tgMsg.MessageThreadID {
+		return
+	}
+
+	text := reply.Text
+	if text == "" {
+		text = reply.Caption
+	}
+	if tgMsg.Quote != nil && tgMsg.Quote.Text != "" {
+		text = tgMsg.Quote.Text
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return
+	}
+	if runes := []rune(text); len(runes) > maxReplyContext {
+		text = string(runes[:maxReplyContext]) + "…"
+	}
+
+	msg.Metadata["reply_to_text"] = text
+	msg.Metadata["reply_to_author"] = t.authorName(reply.From)
+}
+
+// authorName names the sender of a replied-to message as the agent sees it
+func (t *TelegramBot) authorName(from *TelegramUser) string {
+	if from == nil {
+		return "unknown"
+	}
+
+	t.mu.Lock()
+	me := t.me
+	t.mu.Unlock()
+
+	if me != nil && from.ID == me.ID {
+		return "assistant"
+	}
+	if from.Username != "" {
+		return from.Username
+	}
+	return from.FirstName
+}
+
+// handleEditedMessage re-runs an edited text message. The gateway replaces
+// the last turn when the edit targets the latest message and ignores it
+// otherwise; edited commands are never re-executed.
+func (t *TelegramBot) handleEditedMessage(ctx context.Context, tgMsg *TelegramMessage) {
+	if t.handler == nil || tgMsg.Text == "" || strings.HasPrefix(tgMsg.Text, "/") {
+		return
+	}
+
+	if !t.accept(tgMsg, tgMsg.Text, tgMsg.Entities) {
+		return
+	}
+
+	msg := t.newIncomingMessage(tgMsg, t.stripBotMention(tgMsg.Text))
+	msg.Metadata["edited"] = true
+
+	t.log.Debug("✏️ [%s] %s edited message %d: %s", msg.Channel, msg.From, tgMsg.MessageID, msg.Text)
+
+	t.dispatch(replyTargetFor(tgMsg), msg)
+}
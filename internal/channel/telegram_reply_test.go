@@ -0,0 +1,92 @@
+package channel
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAddReplyContext(t *testing.T) {
+	bot := newGroupTestBot()
+	botUser := &TelegramUser{ID: 42, IsBot: true, Username: "PulseBot"}
+
+	tests := []struct {
+		name       string
+		msg        *TelegramMessage
+		wantText   string
+		wantAuthor string
+	}{
+		{
+			name:     "no reply",
+			msg:      groupMessage("hello"),
+			wantText: "",
+		},
+		{
+			name: "reply to bot",
+			msg: &TelegramMessage{
+				Chat:           &TelegramChat{ID: 1, Type: "private"},
+				Text:           "why?",
+				ReplyToMessage: &TelegramMessage{MessageID: 3, From: botUser, Text: "Use a mutex."},
+			},
+			wantText:   "Use a mutex.",
+			wantAuthor: "assistant",
+		},
+		{
+			name: "quote takes precedence",
+			msg: &TelegramMessage{
+				Chat:           &TelegramChat{ID: 1, Type: "private"},
+				Text:           "this part",
+				ReplyToMessage: &TelegramMessage{MessageID: 3, From: botUser, Text: "First. Second."},
+				Quote:          &TextQuote{Text: "Second.", Position: 7},
+			},
+			wantText:   "Second.",
+			wantAuthor: "assistant",
+		},
+		{
+			name: "reply to user caption",
+			msg: &TelegramMessage{
+				Chat:           &TelegramChat{ID: -100, Type: "group"},
+				Text:           "@PulseBot what is this?",
+				ReplyToMessage: &TelegramMessage{MessageID: 3, From: &TelegramUser{ID: 9, FirstName: "Bob"}, Caption: "my cat"},
+			},
+			wantText:   "my cat",
+			wantAuthor: "Bob",
+		},
+		{
+			name: "forum topic root ignored",
+			msg: &TelegramMessage{
+				Chat:            &TelegramChat{ID: -100, Type: "supergroup", IsForum: true},
+				Text:            "hello",
+				IsTopicMessage:  true,
+				MessageThreadID: 5,
+				ReplyToMessage:  &TelegramMessage{MessageID: 5, Text: "Topic"},
+			},
+			wantText: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := bot.newIncomingMessage(tt.msg, tt.msg.Text)
+			text, _ := msg.Metadata["reply_to_text"].(string)
+			author, _ := msg.Metadata["reply_to_author"].(string)
+			if text != tt.wantText || author != tt.wantAuthor {
+				t.Errorf("reply context = (%q, %q), want (%q, %q)", text, author, tt.wantText, tt.wantAuthor)
+			}
+		})
+	}
+}
+
+func TestAddReplyContext_Truncates(t *testing.T) {
+	bot := newGroupTestBot()
+	long := strings.Repeat("é", maxReplyContext+50)
+	msg := bot.newIncomingMessage(&TelegramMessage{
+		Chat:           &TelegramChat{ID: 1, Type: "private"},
+		Text:           "tl;dr?",
+		ReplyToMessage: &TelegramMessage{MessageID: 2, Text: long},
+	}, "tl;dr?")
+
+	text, _ := msg.Metadata["reply_to_text"].(string)
+	if n := len([]rune(text)); n != maxReplyContext+1 {
+		t.Errorf("truncated length = %d runes, want %d", n, maxReplyContext+1)
+	}
+}
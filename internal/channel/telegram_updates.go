@@ -0,0 +1,81 @@
+package channel
+
+import (
+	"time"
+)
+
+const (
+	// ProcessedUpdateTTL is how long processed messages are remembered.
+	// Telegram keeps undelivered updates for 24 hours.
+	ProcessedUpdateTTL = 48 * time.Hour
+	// processedCleanupInterval is how often expired records are removed
+	processedCleanupInterval = time.Hour
+)
+
+// UpdateStore persists the polling offset and the messages already
+// processed, so a restart or a second instance does not answer twice
+type UpdateStore interface {
+	LoadOffset() (int64, error)
+	SaveOffset(offset int64) error
+	// MarkProcessed returns false if the message was already processed
+	MarkProcessed(chatID, messageID int64) (bool, error)
+	CleanProcessed(maxAge time.Duration) (int64, error)
+}
+
+// SetUpdateStore enables offset persistence and duplicate detection
+func (t *TelegramBot) SetUpdateStore(store UpdateStore) {
+	t.updates = store
+}
+
+// restoreOffset resumes polling from the last persisted offset
+func (t *TelegramBot) restoreOffset() {
+	if t.updates == nil || t.offset != 0 {
+		return
+	}
+	offset, err := t.updates.LoadOffset()
+	if err != nil {
+		t.log.Warn("⚠️ Failed to load update offset: %v", err)
+		return
+	}
+	if offset > 0 {
+		t.offset = offset
+		t.log.Info("📱 Resuming Telegram updates from offset %d", offset)
+	}
+}
+
+// saveOffset persists the offset after a batch of updates
+func (t *TelegramBot) saveOffset() {
+	if t.updates == nil {
+		return
+	}
+	if err := t.updates.SaveOffset(t.offset); err != nil {
+		t.log.Warn("⚠️ Failed to save update offset: %v", err)
+	}
+
+	if time.Since(t.lastCleanup) < processedCleanupInterval {
+		return
+	}
+	t.lastCleanup = time.Now()
+	if n, err := t.updates.CleanProcessed(ProcessedUpdateTTL); err != nil {
+		t.log.Warn("⚠️ Failed to clean processed updates: %v", err)
+	} else if n > 0 {
+		t.log.Debug("🧹 Removed %d expired processed-update records", n)
+	}
+}
+
+// isDuplicate reports whether a message was already processed. Errors are
+// logged and treated as new so messages are never dropped because of them.
+func (t *TelegramBot) isDuplicate(tgMsg *TelegramMessage) bool {
+	if t.updates == nil || tgMsg.Chat == nil {
+		return false
+	}
+	fresh, err := t.updates.MarkProcessed(tgMsg.Chat.ID, tgMsg.MessageID)
+	if err != nil {
+		t.log.Warn("⚠️ Failed to record processed message: %v", err)
+		return false
+	}
+	if !fresh {
+		t.log.Debug("🔁 Skipping duplicate message %d in chat %d", tgMsg.MessageID, tgMsg.Chat.ID)
+	}
+	return !fresh
+}
@@ -0,0 +1,91 @@
+package channel
+
+import (
+	"testing"
+	"time"
+)
+
+// memoryUpdateStore is an in-memory UpdateStore for tests
+type memoryUpdateStore struct {
+	offset    int64
+	processed map[[2]int64]bool
+	cleaned   int
+}
+
+func newMemoryUpdateStore() *memoryUpdateStore {
+	return &memoryUpdateStore{processed: make(map[[2]int64]bool)}
+}
+
+func (m *memoryUpdateStore) LoadOffset() (int64, error) { return m.offset, nil }
+
+func (m *memoryUpdateStore) SaveOffset(offset int64) error {
+	m.offset = offset
+	return nil
+}
+
+func (m *memoryUpdateStore) MarkProcessed(chatID, messageID int64) (bool, error) {
+	key := [2]int64{chatID, messageID}
+	if m.processed[key] {
+		return false, nil
+	}
+	m.processed[key] = true
+	return true, nil
+}
+
+func (m *memoryUpdateStore) CleanProcessed(time.Duration) (int64, error) {
+	m.cleaned++
+	return 0, nil
+}
+
+func TestRestoreOffset(t *testing.T) {
+	store := newMemoryUpdateStore()
+	store.offset = 501
+
+	bot := NewTelegramBot("test-token", nil)
+	bot.SetUpdateStore(store)
+	bot.restoreOffset()
+	if bot.offset != 501 {
+		t.Errorf("offset = %d, want 501", bot.offset)
+	}
+
+	bot.offset = 510
+	bot.saveOffset()
+	if store.offset != 510 {
+		t.Errorf("stored offset = %d, want 510", store.offset)
+	}
+	if store.cleaned != 1 {
+		t.Errorf("expected expired records to be cleaned once, got %d", store.cleaned)
+	}
+
+	// Cleanup is rate limited
+	bot.saveOffset()
+	if store.cleaned != 1 {
+		t.Errorf("cleanup should not run on every batch, ran %d times", store.cleaned)
+	}
+}
+
+func TestIsDuplicate(t *testing.T) {
+	bot := newGroupTestBot()
+	bot.SetUpdateStore(newMemoryUpdateStore())
+
+	msg := groupMessage("hello")
+	if bot.isDuplicate(msg) {
+		t.Fatal("first delivery reported as duplicate")
+	}
+	if !bot.isDuplicate(msg) {
+		t.Error("redelivered message not detected")
+	}
+
+	other := groupMessage("hello")
+	other.MessageID = 8
+	if bot.isDuplicate(other) {
+		t.Error("different message reported as duplicate")
+	}
+
+	// Without a store nothing is filtered
+	plain := newGroupTestBot()
+	plain.isDuplicate(msg)
+	if plain.isDuplicate(msg) {
+		t.Error("bot without update store should not filter messages")
+	}
+}
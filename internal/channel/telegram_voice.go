@@ -0,0 +1,139 @@
+package channel
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// maxVoiceFileSize is the largest file the Bot API lets us download (20MB)
+const maxVoiceFileSize = 20 * 1024 * 1024
+
+// Transcriber converts recorded audio to text
+type Transcriber interface {
+	Transcribe(ctx context.Context, audio []byte, ext string) (string, error)
+}
+
+// SpeechSynthesizer renders text to an OGG/Opus voice note
+type SpeechSynthesizer interface {
+	Synthesize(ctx context.Context, text string) ([]byte, error)
+}
+
+// SetSpeechSynthesizer enables voice-note replies for messages whose reply
+// carries the "tts" metadata flag
+func (t *TelegramBot) SetSpeechSynthesizer(synthesizer SpeechSynthesizer) {
+	t.synthesizer = synthesizer
+}
+
+// sendSpeech synthesizes text and sends it as a voice note. Failures are
+// logged only; the text reply has already been delivered.
+func (t *TelegramBot) sendSpeech(target ReplyTarget, text string) {
+	if t.synthesizer == nil {
+		return
+	}
+
+	_ = t.sendChatAction(target, "record_voice")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	audio, err := t.synthesizer.Synthesize(ctx, text)
+	if err != nil {
+		t.log.Warn("⚠️ Failed to synthesize voice reply: %v", err)
+		return
+	}
+	if len(audio) == 0 {
+		return
+	}
+
+	target.ReplyToID = 0
+	if err := t.sendMultipart("sendVoice", target, "voice", "reply.ogg", openBytes(audio), ""); err != nil {
+		t.log.Warn("⚠️ Failed to send voice reply: %v", err)
+		return
+	}
+	t.log.Debug("🔊 Voice reply sent (%d bytes)", len(audio))
+}
+
+// handleVoiceMessage transcribes a voice note or audio file and feeds the
+// transcript into the normal message flow
+func (t *TelegramBot) handleVoiceMessage(ctx context.Context, tgMsg *TelegramMessage) {
+	if t.handler == nil {
+		return
+	}
+
+	if !t.accept(tgMsg, tgMsg.Caption, tgMsg.CaptionEntities) {
+		return
+	}
+
+	target := replyTargetFor(tgMsg)
+
+	if t.transcriber == nil {
+		_, _ = t.SendMessageTo(target, "🎙️ Voice messages are not enabled. Please send text instead.", false)
+		return
+	}
+
+	var fileID string
+	var duration, fileSize int
+	if tgMsg.Voice != nil {
+		fileID, duration, fileSize = tgMsg.Voice.FileID, tgMsg.Voice.Duration, tgMsg.Voice.FileSize
+	} else {
+		fileID, duration, fileSize = tgMsg.Audio.FileID, tgMsg.Audio.Duration, tgMsg.Audio.FileSize
+	}
+
+	if t.maxVoiceSeconds > 0 && duration > t.maxVoiceSeconds {
+		t.log.Warn("⚠️ Voice message too long: %ds", duration)
+		_, _ = t.SendMessageTo(target, fmt.Sprintf("⚠️ Voice message is too long. Please keep it under %d seconds.", t.maxVoiceSeconds), false)
+		return
+	}
+	if fileSize > maxVoiceFileSize {
+		t.log.Warn("⚠️ Voice file too large: %d bytes", fileSize)
+		_, _ = t.SendMessageTo(target, "⚠️ Audio file is too large (max 20MB).", false)
+		return
+	}
+
+	_ = t.sendChatAction(target, "typing")
+
+	fileInfo, err := t.GetFile(fileID)
+	if err != nil {
+		t.log.Error("❌ Failed to get file info: %v", err)
+		_, _ = t.SendMessageTo(target, "❌ Failed to process voice message.", false)
+		return
+	}
+
+	audio, err := t.DownloadFile(fileInfo.FilePath)
+	if err != nil {
+		t.log.Error("❌ Failed to download voice message: %v", err)
+		_, _ = t.SendMessageTo(target, "❌ Failed to download voice message.", false)
+		return
+	}
+
+	transcript, err := t.transcriber.Transcribe(ctx, audio, filepath.Ext(fileInfo.FilePath))
+	if err != nil {
+		t.log.Error("❌ Transcription failed: %v", err)
+		_, _ = t.SendMessageTo(target, "❌ Sorry, I couldn't transcribe that voice message.", false)
+		return
+	}
+	if strings.TrimSpace(transcript) == "" {
+		_, _ = t.SendMessageTo(target, "🎙️ I couldn't make out any speech in that message.", false)
+		return
+	}
+
+	// Echo the recognized text so the user can spot misrecognitions
+	if _, err := t.SendMessageTo(target, "🎙️ "+transcript, false); err != nil {
+		t.log.Warn("⚠️ Failed to echo transcript: %v", err)
+	}
+
+	text := transcript
+	if caption := t.stripBotMention(tgMsg.Caption); caption != "" {
+		text = caption + "\n\n" + transcript
+	}
+
+	msg := t.newIncomingMessage(tgMsg, text)
+	msg.Metadata["transcribed"] = true
+
+	t.log.Debug("🎙️ [%s] %s: [Voice %ds] %s", msg.Channel, msg.From, duration, transcript)
+
+	t.dispatch(target, msg)
+}
@@ -37,6 +37,41 @@ type AdminProvider interface {
 	ResetAllSessions() error
 }
 
+// UserInfo holds info about a user who requested or was granted access
+type UserInfo struct {
+	UserID    int64
+	Username  string
+	Name      string
+	Status    string // pending, approved, denied, revoked
+	Role      string // user, admin
+	UpdatedAt time.Time
+}
+
+// AccessProvider interface for access requests and /admin users
+type AccessProvider interface {
+	IsAdmin(userID int64) bool
+	ListUsers() ([]UserInfo, error)
+	Approve(userID int64) (*UserInfo, error)
+	Deny(userID int64) (*UserInfo, error)
+	Revoke(userID int64) (*UserInfo, error)
+	Promote(userID int64) (*UserInfo, error)
+	CreateInvite(createdBy int64) (string, error)
+}
+
+// FeedbackStat holds reaction counts for one model/profile combination
+type FeedbackStat struct {
+	Model    string
+	Profile  string // Empty for the default persona
+	Positive int
+	Negative int
+	Total    int // Includes neutral reactions
+}
+
+// FeedbackProvider interface for /feedback command
+type FeedbackProvider interface {
+	FeedbackStats() ([]FeedbackStat, error)
+}
+
 // SubAgentInfo holds info about a sub-agent
 type SubAgentInfo struct {
 	ID     string
@@ -69,6 +104,30 @@ type PinProvider interface {
 	GetPins(sessionKey string) string // Returns combined pins text for system prompt
 }
 
+// WebhookDeliveryInfo holds info about an outbound webhook delivery
+type WebhookDeliveryInfo struct {
+	ID           string
+	Subscription string
+	EventType    string
+	Status       string // pending, delivered, failed
+	Attempts     int
+	LastError    string
+	NextAttempt  time.Time
+	CreatedAt    time.Time
+}
+
+// WebhookProvider interface for /admin webhooks
+type WebhookProvider interface {
+	ListWebhookDeliveries(status string, limit int) ([]WebhookDeliveryInfo, error) // Newest first
+	ReplayWebhookDelivery(id string) error
+	ReplayFailedWebhooks() (int, error)
+}
+
+// DashboardProvider interface for /admin login
+type DashboardProvider interface {
+	DashboardLoginLink() (link string, expires time.Time, err error) // Single-use admin login URL
+}
+
 // Handler processes slash commands
 type Handler struct {
 	sessions      *session.Store
@@ -80,8 +139,12 @@ type Handler struct {
 	browser       BrowserNavigator
 	compactor     ContextCompactor
 	admin         AdminProvider
+	access        AccessProvider
+	feedback      FeedbackProvider
 	subagents     SubAgentProvider
 	pins          PinProvider
+	webhooks      WebhookProvider
+	dashboard     DashboardProvider
 	activeSession map[string]string // userKey -> active session key
 }
 
@@ -129,6 +192,16 @@ func (h *Handler) SetAdmin(a AdminProvider) {
 	h.admin = a
 }
 
+// SetAccess sets the access provider for /admin users and approval buttons
+func (h *Handler) SetAccess(a AccessProvider) {
+	h.access = a
+}
+
+// SetFeedback sets the feedback provider for /feedback command
+func (h *Handler) SetFeedback(f FeedbackProvider) {
+	h.feedback = f
+}
+
 // SetSubAgents sets the sub-agent provider for /agents command
 func (h *Handler) SetSubAgents(s SubAgentProvider) {
 	h.subagents = s
@@ -139,6 +212,16 @@ func (h *Handler) SetPins(p PinProvider) {
 	h.pins = p
 }
 
+// SetWebhooks sets the webhook provider for /admin webhooks
+func (h *Handler) SetWebhooks(w WebhookProvider) {
+	h.webhooks = w
+}
+
+// SetDashboard sets the dashboard provider for /admin login
+func (h *Handler) SetDashboard(d DashboardProvider) {
+	h.dashboard = d
+}
+
 // IsCommand checks if a message is a slash command
 func IsCommand(text string) bool {
 	text = strings.TrimSpace(text)
@@ -199,6 +282,8 @@ func (h *Handler) Handle(msg *types.Message) (*types.Message, error) {
 		response = h.handleCancel(msg.Channel, userID, args)
 	case "usage", "stats":
 		response = h.handleUsage(msg.Channel, userID)
+	case "feedback":
+		response = h.handleFeedback()
 	case "model":
 		response, keyboard = h.handleModel(msg.Channel, userID, args)
 	case "models":
@@ -224,7 +309,7 @@ func (h *Handler) Handle(msg *types.Message) (*types.Message, error) {
 	case "switch":
 		response = h.handleSwitch(msg.Channel, userID, args)
 	case "admin":
-		response = h.handleAdmin(msg.Channel, userID, msg.From, args)
+		response = h.handleAdmin(msg.Channel, userID, msg.From, senderID(msg), isGroupMessage(msg), args)
 	case "agents":
 		response = h.handleAgents()
 	case "agent":
@@ -235,6 +320,9 @@ func (h *Handler) Handle(msg *types.Message) (*types.Message, error) {
 		response = h.handlePins(msg.Channel, userID)
 	case "unpin":
 		response = h.handleUnpin(msg.Channel, userID, args)
+	case "request":
+		// Users without access are answered by the channel before reaching here
+		response = "✅ You already have access."
 	case "help", "start":
 		response = h.handleHelp()
 	default:
@@ -249,9 +337,32 @@ func (h *Handler) Handle(msg *types.Message) (*types.Message, error) {
 	}, nil
 }
 
+// senderID returns the numeric ID of the user who sent a message (0 if unknown)
+func senderID(msg *types.Message) int64 {
+	switch v := msg.Metadata["user_id"].(type) {
+	case int64:
+		return v
+	case int:
+		return int64(v)
+	case float64:
+		return int64(v)
+	}
+	return 0
+}
+
+// isGroupMessage reports whether a message was sent in a group chat
+func isGroupMessage(msg *types.Message) bool {
+	_, ok := msg.Metadata["session_id"]
+	return ok
+}
+
 // getUserID extracts user ID from message metadata
+// (the shared session_id for group chats, if present)
 func getUserID(msg *types.Message) string {
 	if msg.Metadata != nil {
+		if sessionID, ok := msg.Metadata["session_id"].(string); ok && sessionID != "" {
+			return sessionID
+		}
 		if userID, ok := msg.Metadata["user_id"]; ok {
 			switch v := userID.(type) {
 			case string:
@@ -3,18 +3,18 @@ package command
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 )
 
 // handleAdmin handles admin commands
-func (h *Handler) handleAdmin(ch, userID, username, args string) string {
+func (h *Handler) handleAdmin(ch, userID, username string, sender int64, group bool, args string) string {
 	if h.admin == nil {
 		return "❌ Admin commands are not available."
 	}
 
 	// Check if user is admin
-	adminUsername := h.admin.GetAdminUsername()
-	if adminUsername != "" && username != adminUsername {
+	if !h.isAdmin(username, sender) {
 		return "❌ Access denied. Admin only."
 	}
 
@@ -29,6 +29,31 @@ func (h *Handler) handleAdmin(ch, userID, username, args string) string {
 		return h.handleAdminSessions()
 	case "reload":
 		return h.handleAdminReload()
+	case "users":
+		// Granting access must not be open to everyone on an open bot
+		if !h.isConfiguredAdmin(username, sender) {
+			return "❌ User management needs a configured admin: set admin.username or promote a user."
+		}
+		var rest string
+		if len(parts) > 1 {
+			rest = parts[1]
+		}
+		return h.handleAdminUsers(sender, rest)
+	case "webhooks":
+		var rest string
+		if len(parts) > 1 {
+			rest = parts[1]
+		}
+		return h.handleAdminWebhooks(rest)
+	case "login":
+		// The link makes whoever opens it first a dashboard admin
+		if group {
+			return "❌ Send /admin login in a private chat with the bot."
+		}
+		if !h.isConfiguredAdmin(username, sender) {
+			return "❌ Login links need a configured admin: set admin.username or promote a user."
+		}
+		return h.handleAdminLogin()
 	case "reset":
 		// Handle confirmation
 		if len(parts) > 1 && strings.ToLower(parts[1]) == "confirm" {
@@ -42,6 +67,255 @@ func (h *Handler) handleAdmin(ch, userID, username, args string) string {
 	}
 }
 
+// isAdmin reports whether a sender may use /admin: the configured admin
+// username, or a user promoted to admin
+func (h *Handler) isAdmin(username string, sender int64) bool {
+	if h.access != nil && sender != 0 && h.access.IsAdmin(sender) {
+		return true
+	}
+	adminUsername := h.admin.GetAdminUsername()
+	return adminUsername == "" || username == adminUsername
+}
+
+// isConfiguredAdmin is like isAdmin without the open-bot fallback: the
+// sender must be the configured admin username or a promoted admin
+func (h *Handler) isConfiguredAdmin(username string, sender int64) bool {
+	if h.access != nil && sender != 0 && h.access.IsAdmin(sender) {
+		return true
+	}
+	adminUsername := h.admin.GetAdminUsername()
+	return adminUsername != "" && username == adminUsername
+}
+
+// handleAdminUsers manages users granted access through /request or invites
+func (h *Handler) handleAdminUsers(sender int64, args string) string {
+	if h.access == nil {
+		return "❌ User management is not available."
+	}
+
+	fields := strings.Fields(args)
+	subcmd := "list"
+	if len(fields) > 0 {
+		subcmd = strings.ToLower(fields[0])
+	}
+
+	switch subcmd {
+	case "list":
+		return h.handleAdminUsersList()
+	case "invite":
+		code, err := h.access.CreateInvite(sender)
+		if err != nil {
+			return fmt.Sprintf("❌ Failed to create invite: %v", err)
+		}
+		return fmt.Sprintf("🎟 *Invite created* (single use, expires in 7 days)\n\nAsk the user to send:\n`/request %s`", code)
+	case "approve", "deny", "revoke", "promote":
+		if len(fields) < 2 {
+			return fmt.Sprintf("Usage: /admin users %s <user_id>", subcmd)
+		}
+		id, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return fmt.Sprintf("❌ Invalid user ID: %s", fields[1])
+		}
+		text, err := h.applyAccessDecision(subcmd, id)
+		if err != nil {
+			return fmt.Sprintf("❌ %v", err)
+		}
+		return text
+	default:
+		return fmt.Sprintf("❓ Unknown users command: %s\n\nUsage: /admin users [list|approve|deny|revoke|promote <user_id>|invite]", subcmd)
+	}
+}
+
+// handleAdminUsersList lists users who requested or were granted access
+func (h *Handler) handleAdminUsersList() string {
+	users, err := h.access.ListUsers()
+	if err != nil {
+		return fmt.Sprintf("❌ Failed to list users: %v", err)
+	}
+	if len(users) == 0 {
+		return "📭 No access requests yet."
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("👥 *Users* (%d)\n\n", len(users)))
+	for _, u := range users {
+		role := ""
+		if u.Role == "admin" {
+			role = " 🔐"
+		}
+		sb.WriteString(fmt.Sprintf("%s %s `%d`%s — %s\n",
+			formatAccessStatus(u.Status), formatUserName(&u), u.UserID, role, formatTimeAgo(u.UpdatedAt)))
+	}
+	return sb.String()
+}
+
+// applyAccessDecision runs an approve/deny/revoke/promote action and returns
+// the confirmation text
+func (h *Handler) applyAccessDecision(action string, id int64) (string, error) {
+	var u *UserInfo
+	var err error
+	switch action {
+	case "approve":
+		u, err = h.access.Approve(id)
+	case "deny":
+		u, err = h.access.Deny(id)
+	case "revoke":
+		u, err = h.access.Revoke(id)
+	case "promote":
+		u, err = h.access.Promote(id)
+	default:
+		return "", fmt.Errorf("unknown action: %s", action)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	switch action {
+	case "approve":
+		return fmt.Sprintf("✅ Approved %s (`%d`)", formatUserName(u), u.UserID), nil
+	case "deny":
+		return fmt.Sprintf("🚫 Denied %s (`%d`)", formatUserName(u), u.UserID), nil
+	case "revoke":
+		return fmt.Sprintf("🚫 Revoked access for %s (`%d`)", formatUserName(u), u.UserID), nil
+	default:
+		return fmt.Sprintf("🔐 %s (`%d`) is now an admin", formatUserName(u), u.UserID), nil
+	}
+}
+
+// formatUserName returns "@username", falling back to the display name
+func formatUserName(u *UserInfo) string {
+	if u.Username != "" {
+		return "@" + u.Username
+	}
+	if u.Name != "" {
+		return u.Name
+	}
+	return "user"
+}
+
+// formatAccessStatus returns an emoji for an access status
+func formatAccessStatus(status string) string {
+	switch status {
+	case "approved":
+		return "✅"
+	case "pending":
+		return "⏳"
+	default:
+		return "🚫"
+	}
+}
+
+// handleAdminWebhooks lists outbound webhook deliveries and replays failures
+func (h *Handler) handleAdminWebhooks(args string) string {
+	if h.webhooks == nil {
+		return "❌ Webhooks are not configured."
+	}
+
+	fields := strings.Fields(args)
+	subcmd := "list"
+	if len(fields) > 0 {
+		subcmd = strings.ToLower(fields[0])
+	}
+
+	switch subcmd {
+	case "list", "pending", "failed", "delivered":
+		status := subcmd
+		if status == "list" {
+			status = ""
+		}
+		return h.handleAdminWebhooksList(status)
+	case "replay":
+		if len(fields) < 2 {
+			return "Usage: /admin webhooks replay <id>|failed"
+		}
+		if strings.ToLower(fields[1]) == "failed" {
+			n, err := h.webhooks.ReplayFailedWebhooks()
+			if err != nil {
+				return fmt.Sprintf("❌ Replay failed: %v", err)
+			}
+			if n == 0 {
+				return "📭 No failed deliveries."
+			}
+			return fmt.Sprintf("🔁 Queued %d failed deliveries again.", n)
+		}
+		return h.handleAdminWebhooksReplay(fields[1])
+	default:
+		return fmt.Sprintf("❓ Unknown webhooks command: %s\n\nUsage: /admin webhooks [pending|failed|delivered|replay <id>|replay failed]", subcmd)
+	}
+}
+
+// handleAdminWebhooksList lists recent deliveries, optionally by status
+func (h *Handler) handleAdminWebhooksList(status string) string {
+	deliveries, err := h.webhooks.ListWebhookDeliveries(status, 20)
+	if err != nil {
+		return fmt.Sprintf("❌ Failed to list deliveries: %v", err)
+	}
+	if len(deliveries) == 0 {
+		return "📭 No webhook deliveries."
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("📤 *Webhook Deliveries* (%d)\n\n", len(deliveries)))
+	for _, d := range deliveries {
+		sb.WriteString(fmt.Sprintf("%s `%s` %s → %s — %s\n",
+			formatWebhookStatus(d.Status), shortDeliveryID(d.ID), d.EventType, d.Subscription, formatTimeAgo(d.CreatedAt)))
+		switch {
+		case d.Status == "failed":
+			sb.WriteString(fmt.Sprintf("  %d attempts: %s\n", d.Attempts, d.LastError))
+		case d.Status == "pending" && d.Attempts > 0:
+			sb.WriteString(fmt.Sprintf("  Retry %d at %s: %s\n", d.Attempts+1, d.NextAttempt.Format("15:04:05"), d.LastError))
+		}
+	}
+	sb.WriteString("\nReplay with `/admin webhooks replay <id>` or `/admin webhooks replay failed`")
+	return sb.String()
+}
+
+// handleAdminWebhooksReplay queues one delivery again, matched by ID prefix
+func (h *Handler) handleAdminWebhooksReplay(prefix string) string {
+	deliveries, err := h.webhooks.ListWebhookDeliveries("", 500)
+	if err != nil {
+		return fmt.Sprintf("❌ Failed to list deliveries: %v", err)
+	}
+
+	var match *WebhookDeliveryInfo
+	for i := range deliveries {
+		if strings.HasPrefix(deliveries[i].ID, prefix) {
+			if match != nil {
+				return fmt.Sprintf("❌ Ambiguous delivery ID: %s", prefix)
+			}
+			match = &deliveries[i]
+		}
+	}
+	if match == nil {
+		return fmt.Sprintf("❌ Delivery not found: %s", prefix)
+	}
+
+	if err := h.webhooks.ReplayWebhookDelivery(match.ID); err != nil {
+		return fmt.Sprintf("❌ Replay failed: %v", err)
+	}
+	return fmt.Sprintf("🔁 Queued `%s` (%s → %s) again.", shortDeliveryID(match.ID), match.EventType, match.Subscription)
+}
+
+// shortDeliveryID returns the first 8 characters of a delivery ID
+func shortDeliveryID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
+
+// formatWebhookStatus returns an emoji for a delivery status
+func formatWebhookStatus(status string) string {
+	switch status {
+	case "delivered":
+		return "✅"
+	case "pending":
+		return "⏳"
+	default:
+		return "❌"
+	}
+}
+
 // handleAdminStats returns system statistics
 func (h *Handler) handleAdminStats() string {
 	stats := h.admin.GetSystemStats()
@@ -136,6 +410,18 @@ func (h *Handler) handleAdminReset() string {
 	return "✅ Reset complete!\n\nCleared:\n- All sessions and conversation history\n- All reminders, sub-agents, and pins\n- IDENTITY.md, MEMORY.md, memory/ directory\n\nYour next message will trigger the bootstrap process."
 }
 
+// handleAdminLogin creates a single-use dashboard login link
+func (h *Handler) handleAdminLogin() string {
+	if h.dashboard == nil {
+		return "❌ Dashboard login is not available."
+	}
+	link, expires, err := h.dashboard.DashboardLoginLink()
+	if err != nil {
+		return fmt.Sprintf("❌ Failed to create login link: %v", err)
+	}
+	return fmt.Sprintf("🔐 *Dashboard Login*\n\n%s\n\nSingle use, valid until %s. Don't share it.", link, expires.Format("15:04"))
+}
+
 // handleAdminHelp shows admin commands
 func (h *Handler) handleAdminHelp() string {
 	return `🔐 *Admin Commands*
@@ -143,5 +429,11 @@ func (h *Handler) handleAdminHelp() string {
   /admin stats — System statistics
   /admin sessions — All active sessions  
   /admin reload — Reload config + workspace
+  /admin users — List users who requested access
+  /admin users approve|deny|revoke|promote <id> — Manage a user
+  /admin users invite — Create a single-use invite code
+  /admin webhooks — Recent outbound webhook deliveries
+  /admin webhooks replay <id>|failed — Send deliveries again
+  /admin login — Single-use dashboard login link
   /admin reset — Clear all memory & sessions (requires confirmation)`
 }
@@ -0,0 +1,265 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FeelPulse/feelpulse/internal/config"
+	"github.com/FeelPulse/feelpulse/internal/session"
+	"github.com/FeelPulse/feelpulse/pkg/types"
+)
+
+type mockAdmin struct{ username string }
+
+func (m *mockAdmin) GetAdminUsername() string           { return m.username }
+func (m *mockAdmin) GetSystemStats() map[string]any     { return map[string]any{} }
+func (m *mockAdmin) GetAllSessions() []*session.Session { return nil }
+func (m *mockAdmin) ReloadConfig(context.Context) error { return nil }
+func (m *mockAdmin) ResetAllSessions() error            { return nil }
+
+type mockAccess struct {
+	admins map[int64]bool
+	users  map[int64]*UserInfo
+}
+
+func newMockAccess() *mockAccess {
+	return &mockAccess{
+		admins: map[int64]bool{1: true},
+		users: map[int64]*UserInfo{
+			7: {UserID: 7, Username: "bob", Status: "pending", Role: "user"},
+		},
+	}
+}
+
+func (m *mockAccess) IsAdmin(userID int64) bool { return m.admins[userID] }
+
+func (m *mockAccess) ListUsers() ([]UserInfo, error) {
+	var users []UserInfo
+	for _, u := range m.users {
+		users = append(users, *u)
+	}
+	return users, nil
+}
+
+func (m *mockAccess) set(userID int64, status, role string) (*UserInfo, error) {
+	u, ok := m.users[userID]
+	if !ok {
+		return nil, fmt.Errorf("unknown user: %d", userID)
+	}
+	u.Status = status
+	if role != "" {
+		u.Role = role
+	}
+	return u, nil
+}
+
+func (m *mockAccess) Approve(id int64) (*UserInfo, error) { return m.set(id, "approved", "") }
+func (m *mockAccess) Deny(id int64) (*UserInfo, error)    { return m.set(id, "denied", "") }
+func (m *mockAccess) Revoke(id int64) (*UserInfo, error)  { return m.set(id, "revoked", "user") }
+func (m *mockAccess) Promote(id int64) (*UserInfo, error) { return m.set(id, "approved", "admin") }
+
+func (m *mockAccess) CreateInvite(createdBy int64) (string, error) { return "c0ffee", nil }
+
+func adminMessage(text string, userID int64, from string) *types.Message {
+	return &types.Message{
+		Text:     text,
+		From:     from,
+		Channel:  "telegram",
+		Metadata: map[string]any{"user_id": userID},
+	}
+}
+
+func TestHandleAdminUsers(t *testing.T) {
+	h := NewHandler(session.NewStore(), config.Default())
+	h.SetAdmin(&mockAdmin{username: "alice"})
+	access := newMockAccess()
+	h.SetAccess(access)
+
+	tests := []struct {
+		name     string
+		msg      *types.Message
+		contains string
+	}{
+		{"non admin denied", adminMessage("/admin users", 7, "bob"), "Access denied"},
+		{"admin by username", adminMessage("/admin users", 2, "alice"), "@bob"},
+		{"admin by ID", adminMessage("/admin users list", 1, "someone"), "⏳ @bob `7`"},
+		{"approve", adminMessage("/admin users approve 7", 1, ""), "Approved @bob"},
+		{"promote", adminMessage("/admin users promote 7", 1, ""), "is now an admin"},
+		{"revoke", adminMessage("/admin users revoke 7", 1, ""), "Revoked access"},
+		{"unknown user", adminMessage("/admin users revoke 99", 1, ""), "unknown user"},
+		{"bad ID", adminMessage("/admin users approve bob", 1, ""), "Invalid user ID"},
+		{"missing ID", adminMessage("/admin users promote", 1, ""), "Usage"},
+		{"invite", adminMessage("/admin users invite", 1, ""), "/request c0ffee"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reply, err := h.Handle(tt.msg)
+			if err != nil {
+				t.Fatalf("Handle() error: %v", err)
+			}
+			if !strings.Contains(reply.Text, tt.contains) {
+				t.Errorf("reply %q does not contain %q", reply.Text, tt.contains)
+			}
+		})
+	}
+
+	if u := access.users[7]; u.Status != "revoked" || u.Role != "user" {
+		t.Errorf("user 7 should end up revoked, got %+v", u)
+	}
+
+	// Without admin.username every user passes isAdmin, but may not manage users
+	open := NewHandler(session.NewStore(), config.Default())
+	open.SetAdmin(&mockAdmin{})
+	open.SetAccess(newMockAccess())
+	for _, text := range []string{"/admin users promote 7", "/admin users invite", "/admin users approve 7"} {
+		reply, _ := open.Handle(adminMessage(text, 7, "bob"))
+		if !strings.Contains(reply.Text, "configured admin") {
+			t.Errorf("%s on an open bot: %q", text, reply.Text)
+		}
+	}
+	if u := open.access.(*mockAccess).users[7]; u.Status != "pending" || u.Role != "user" {
+		t.Errorf("user 7 should be unchanged, got %+v", u)
+	}
+}
+
+func TestHandleCallback_Access(t *testing.T) {
+	h := NewHandler(session.NewStore(), config.Default())
+	access := newMockAccess()
+	h.SetAccess(access)
+
+	// Only admins can decide
+	if text, _, _ := h.HandleCallback("telegram", 7, "access", "approve:7"); text != "" || access.users[7].Status != "pending" {
+		t.Fatalf("non-admin callback should be ignored, got %q", text)
+	}
+
+	text, _, err := h.HandleCallback("telegram", 1, "access", "approve:7")
+	if err != nil || !strings.Contains(text, "Approved @bob") {
+		t.Errorf("unexpected callback result %q (%v)", text, err)
+	}
+	if access.users[7].Status != "approved" {
+		t.Errorf("user should be approved, got %s", access.users[7].Status)
+	}
+
+	if text, _, _ := h.HandleCallback("telegram", 1, "access", "bogus:7"); !strings.Contains(text, "Invalid") {
+		t.Errorf("invalid decision should be rejected, got %q", text)
+	}
+}
+
+type mockWebhooks struct {
+	deliveries []WebhookDeliveryInfo
+	replayed   []string
+}
+
+func (m *mockWebhooks) ListWebhookDeliveries(status string, limit int) ([]WebhookDeliveryInfo, error) {
+	var list []WebhookDeliveryInfo
+	for _, d := range m.deliveries {
+		if status == "" || d.Status == status {
+			list = append(list, d)
+		}
+	}
+	return list, nil
+}
+
+func (m *mockWebhooks) ReplayWebhookDelivery(id string) error {
+	m.replayed = append(m.replayed, id)
+	return nil
+}
+
+func (m *mockWebhooks) ReplayFailedWebhooks() (int, error) {
+	n := 0
+	for _, d := range m.deliveries {
+		if d.Status == "failed" {
+			m.replayed = append(m.replayed, d.ID)
+			n++
+		}
+	}
+	return n, nil
+}
+
+func TestHandleAdminWebhooks(t *testing.T) {
+	h := NewHandler(session.NewStore(), config.Default())
+	h.SetAdmin(&mockAdmin{username: "alice"})
+	webhooks := &mockWebhooks{deliveries: []WebhookDeliveryInfo{
+		{ID: "aaaa1111-delivered", Subscription: "ci", EventType: "reply.sent", Status: "delivered", Attempts: 1},
+		{ID: "aaaa2222-failed", Subscription: "ci", EventType: "tool.called", Status: "failed", Attempts: 8, LastError: "HTTP 500"},
+	}}
+	h.SetWebhooks(webhooks)
+
+	tests := []struct {
+		name     string
+		text     string
+		contains string
+	}{
+		{"list", "/admin webhooks", "✅ `aaaa1111` reply.sent → ci"},
+		{"failed only", "/admin webhooks failed", "8 attempts: HTTP 500"},
+		{"replay by prefix", "/admin webhooks replay aaaa2", "Queued `aaaa2222`"},
+		{"ambiguous prefix", "/admin webhooks replay aaaa", "Ambiguous"},
+		{"unknown", "/admin webhooks replay bbbb", "not found"},
+		{"replay failed", "/admin webhooks replay failed", "Queued 1 failed"},
+		{"usage", "/admin webhooks replay", "Usage"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reply, err := h.Handle(adminMessage(tt.text, 1, "alice"))
+			if err != nil {
+				t.Fatalf("Handle() error: %v", err)
+			}
+			if !strings.Contains(reply.Text, tt.contains) {
+				t.Errorf("reply %q does not contain %q", reply.Text, tt.contains)
+			}
+		})
+	}
+
+	if fmt.Sprint(webhooks.replayed) != "[aaaa2222-failed aaaa2222-failed]" {
+		t.Errorf("unexpected replays: %v", webhooks.replayed)
+	}
+}
+
+type mockDashboard struct{ links int }
+
+func (m *mockDashboard) DashboardLoginLink() (string, time.Time, error) {
+	m.links++
+	return fmt.Sprintf("http://localhost:18789/dashboard/login?code=c%d", m.links), time.Now().Add(10 * time.Minute), nil
+}
+
+func TestHandleAdminLogin(t *testing.T) {
+	h := NewHandler(session.NewStore(), config.Default())
+	h.SetAdmin(&mockAdmin{username: "alice"})
+
+	reply, _ := h.Handle(adminMessage("/admin login", 1, "alice"))
+	if !strings.Contains(reply.Text, "not available") {
+		t.Errorf("without a dashboard: %q", reply.Text)
+	}
+
+	h.SetDashboard(&mockDashboard{})
+	reply, _ = h.Handle(adminMessage("/admin login", 1, "alice"))
+	if !strings.Contains(reply.Text, "/dashboard/login?code=c1") || !strings.Contains(reply.Text, "Single use") {
+		t.Errorf("unexpected reply: %q", reply.Text)
+	}
+
+	reply, _ = h.Handle(adminMessage("/admin login", 2, "mallory"))
+	if strings.Contains(reply.Text, "/dashboard/login") {
+		t.Error("non-admins must not get a login link")
+	}
+
+	group := adminMessage("/admin login", 1, "alice")
+	group.Metadata["session_id"] = "-100123"
+	reply, _ = h.Handle(group)
+	if strings.Contains(reply.Text, "/dashboard/login") || !strings.Contains(reply.Text, "private chat") {
+		t.Errorf("login links must not be posted to groups: %q", reply.Text)
+	}
+
+	// Without admin.username every user passes isAdmin, but not for links
+	open := NewHandler(session.NewStore(), config.Default())
+	open.SetAdmin(&mockAdmin{})
+	open.SetDashboard(&mockDashboard{})
+	reply, _ = open.Handle(adminMessage("/admin login", 2, "mallory"))
+	if strings.Contains(reply.Text, "/dashboard/login") || !strings.Contains(reply.Text, "configured admin") {
+		t.Errorf("open bots must not hand out login links: %q", reply.Text)
+	}
+}
@@ -2,6 +2,7 @@ package command
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -20,6 +21,59 @@ func (h *Handler) handleUsage(ch, userID string) string {
 	return stats.String()
 }
 
+// handleFeedback summarizes reactions to replies per model and per profile
+func (h *Handler) handleFeedback() string {
+	if h.feedback == nil {
+		return "❌ Feedback tracking is not enabled."
+	}
+
+	stats, err := h.feedback.FeedbackStats()
+	if err != nil {
+		return fmt.Sprintf("❌ Failed to load feedback: %v", err)
+	}
+	if len(stats) == 0 {
+		return "📭 No feedback yet.\n\nReact with 👍 or 👎 to a reply to rate it."
+	}
+
+	var sb strings.Builder
+	sb.WriteString("📊 *Feedback*\n\n🤖 *By model*\n")
+	writeFeedbackStats(&sb, stats, func(st FeedbackStat) string { return st.Model })
+	sb.WriteString("\n🎭 *By profile*\n")
+	writeFeedbackStats(&sb, stats, func(st FeedbackStat) string { return st.Profile })
+	return sb.String()
+}
+
+// writeFeedbackStats aggregates stats by key and writes one line per group
+func writeFeedbackStats(sb *strings.Builder, stats []FeedbackStat, key func(FeedbackStat) string) {
+	groups := make(map[string]*FeedbackStat)
+	var names []string
+	for _, st := range stats {
+		name := key(st)
+		if name == "" {
+			name = "default"
+		}
+		g, ok := groups[name]
+		if !ok {
+			g = &FeedbackStat{}
+			groups[name] = g
+			names = append(names, name)
+		}
+		g.Positive += st.Positive
+		g.Negative += st.Negative
+		g.Total += st.Total
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		g := groups[name]
+		sb.WriteString(fmt.Sprintf("• `%s` — 👍 %d  👎 %d", name, g.Positive, g.Negative))
+		if rated := g.Positive + g.Negative; rated > 0 {
+			sb.WriteString(fmt.Sprintf(" (%d%% positive)", g.Positive*100/rated))
+		}
+		sb.WriteString("\n")
+	}
+}
+
 // handleHelp shows available commands
 func (h *Handler) handleHelp() string {
 	return `🫀 *FeelPulse — AI Chat Assistant*
@@ -77,6 +131,7 @@ func (h *Handler) handleHelp() string {
 
 📊 *Stats*
   /usage — Show token usage & context
+  /feedback — Show 👍/👎 reactions per model and profile
 
 🔐 *Admin*
   /admin — Admin commands (restricted)
@@ -269,6 +324,22 @@ func (h *Handler) HandleCallback(ch string, userID int64, action, value string)
 		// Confirmation tap on new chat button - just acknowledge
 		return "🔄 Chat cleared! Send a message to continue.", nil, nil
 
+	case "access":
+		// Admin approved or denied an access request ("approve:<user_id>")
+		if h.access == nil || !h.access.IsAdmin(userID) {
+			return "", nil, nil
+		}
+		decision, idStr := channel.ParseCallbackData(value)
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil || (decision != "approve" && decision != "deny") {
+			return "❌ Invalid access request.", nil, nil
+		}
+		text, err := h.applyAccessDecision(decision, id)
+		if err != nil {
+			return fmt.Sprintf("❌ %v", err), nil, nil
+		}
+		return text, nil, nil
+
 	default:
 		return "", nil, nil
 	}
@@ -349,6 +349,38 @@ func TestHandlerUsage(t *testing.T) {
 	}
 }
 
+func TestHandlerFeedback(t *testing.T) {
+	handler := NewHandler(session.NewStore(), nil)
+	msg := &types.Message{Text: "/feedback", Channel: "telegram", Metadata: map[string]any{"user_id": "user123"}}
+
+	result, _ := handler.Handle(msg)
+	if !strings.Contains(result.Text, "not enabled") {
+		t.Errorf("Expected not enabled message, got: %s", result.Text)
+	}
+
+	handler.SetFeedback(&mockFeedback{stats: []FeedbackStat{
+		{Model: "claude-sonnet-4", Profile: "", Positive: 3, Negative: 1, Total: 5},
+		{Model: "claude-sonnet-4", Profile: "friendly", Positive: 1, Negative: 0, Total: 1},
+		{Model: "gpt-4o", Profile: "friendly", Positive: 0, Negative: 2, Total: 2},
+	}})
+
+	result, err := handler.Handle(msg)
+	if err != nil {
+		t.Fatalf("Handle error: %v", err)
+	}
+
+	for _, want := range []string{
+		"`claude-sonnet-4` — 👍 4  👎 1 (80% positive)",
+		"`gpt-4o` — 👍 0  👎 2 (0% positive)",
+		"`default` — 👍 3  👎 1 (75% positive)",
+		"`friendly` — 👍 1  👎 2 (33% positive)",
+	} {
+		if !strings.Contains(result.Text, want) {
+			t.Errorf("Expected %q in output, got: %s", want, result.Text)
+		}
+	}
+}
+
 func TestHandlerExport(t *testing.T) {
 	store := session.NewStore()
 	handler := NewHandler(store, nil)
@@ -1102,6 +1134,14 @@ func (m *mockCompactor) ForceCompact(messages []types.Message) ([]types.Message,
 	return messages, nil
 }
 
+type mockFeedback struct {
+	stats []FeedbackStat
+}
+
+func (m *mockFeedback) FeedbackStats() ([]FeedbackStat, error) {
+	return m.stats, nil
+}
+
 func boolPtr(b bool) *bool {
 	return &b
 }
@@ -141,7 +141,7 @@ func (h *Handler) handleTTS(ch, userID, args string) string {
 	switch args {
 	case "on", "enable", "true", "1":
 		sess.SetTTS(true)
-		return "🔊 *TTS Enabled*\n\nBot responses will now be spoken aloud."
+		return "🔊 *TTS Enabled*\n\nBot responses will now also be sent as voice notes."
 	case "off", "disable", "false", "0":
 		sess.SetTTS(false)
 		return "🔇 *TTS Disabled*\n\nBot responses will be text-only."
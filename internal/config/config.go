@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -13,24 +15,37 @@ type Config struct {
 	Agent     AgentConfig     `yaml:"agent"`
 	Channels  ChannelsConfig  `yaml:"channels"`
 	Hooks     HooksConfig     `yaml:"hooks"`
+	Webhooks  WebhooksConfig  `yaml:"webhooks"`
 	Workspace WorkspaceConfig `yaml:"workspace"`
 	Heartbeat HeartbeatConfig `yaml:"heartbeat"`
 	TTS       TTSConfig       `yaml:"tts"`
+	STT       STTConfig       `yaml:"stt"`
 	Browser   BrowserConfig   `yaml:"browser"`
 	Tools     ToolsConfig     `yaml:"tools"`
 	Log       LogConfig       `yaml:"log"`
 	Admin     AdminConfig     `yaml:"admin"`
+	Dashboard DashboardConfig `yaml:"dashboard"`
 	Metrics   MetricsConfig   `yaml:"metrics"`
 }
 
 // LogConfig holds logging configuration
 type LogConfig struct {
 	Level string `yaml:"level"` // debug, info, warn, error (default: info)
+	Audit bool   `yaml:"audit"` // Log every lifecycle event (message, reply, tool call, ...)
 }
 
 // AdminConfig holds admin user configuration
 type AdminConfig struct {
-	Username string `yaml:"username"` // Admin username (defaults to first allowedUser)
+	Username   string `yaml:"username"`   // Admin username (defaults to first allowedUser)
+	TelegramID int64  `yaml:"telegramId"` // Admin's Telegram user ID; receives access requests
+}
+
+// DashboardConfig holds dashboard login settings
+type DashboardConfig struct {
+	Password       string `yaml:"password"`       // Admin login password
+	ViewerPassword string `yaml:"viewerPassword"` // Read-only login password
+	SessionHours   int    `yaml:"sessionHours"`   // How long a login lasts (default: 24)
+	URL            string `yaml:"url"`            // Public dashboard address for login links (default: http://bind:port)
 }
 
 // MetricsConfig holds metrics endpoint configuration
@@ -63,8 +78,22 @@ type HeartbeatConfig struct {
 }
 
 type TTSConfig struct {
-	Enabled bool   `yaml:"enabled"` // Enable TTS globally (default: false)
-	Command string `yaml:"command"` // TTS command (espeak, say, etc.) - auto-detected if empty
+	Enabled   bool    `yaml:"enabled"`   // Enable TTS globally (default: false)
+	Command   string  `yaml:"command"`   // TTS command (espeak, say, piper, etc.) - auto-detected if empty
+	Voice     string  `yaml:"voice"`     // Voice name (espeak/say) or model path (piper)
+	Speed     float64 `yaml:"speed"`     // Speaking rate multiplier (default: 1.0)
+	MaxLength int     `yaml:"maxLength"` // Max characters spoken per reply (default: 1000)
+	FFmpeg    string  `yaml:"ffmpeg"`    // ffmpeg binary for OGG/Opus encoding (default: ffmpeg)
+}
+
+// STTConfig holds speech-to-text settings for voice messages
+type STTConfig struct {
+	Enabled            bool   `yaml:"enabled"`            // Transcribe Telegram voice messages (default: false)
+	Command            string `yaml:"command"`            // Command template: {input} = 16kHz mono WAV path, {language} = language hint
+	FFmpeg             string `yaml:"ffmpeg"`             // ffmpeg binary for conversion (default: ffmpeg, empty = no conversion)
+	Language           string `yaml:"language"`           // Language hint (default: auto)
+	MaxDurationSeconds int    `yaml:"maxDurationSeconds"` // Longest voice message to transcribe (default: 300)
+	TimeoutSeconds     int    `yaml:"timeoutSeconds"`     // Transcription timeout (default: 120)
 }
 
 type BrowserConfig struct {
@@ -77,11 +106,32 @@ type BrowserConfig struct {
 type WorkspaceConfig struct {
 	Path     string            `yaml:"path"`
 	Profiles map[string]string `yaml:"profiles"` // Map of profile name -> path to SOUL.md variant
+	Inbox    InboxConfig       `yaml:"inbox"`
+}
+
+// InboxConfig controls files uploaded through chat channels
+type InboxConfig struct {
+	Enabled      bool     `yaml:"enabled"`      // Accept document uploads into <workspace>/inbox (default: true)
+	MaxSizeMB    int      `yaml:"maxSizeMB"`    // Max upload size in MB (default: 20, the Bot API download limit)
+	AllowedTypes []string `yaml:"allowedTypes"` // MIME types ("text/*" wildcards) or extensions (".csv"); empty = built-in list
 }
 
 type GatewayConfig struct {
-	Port int    `yaml:"port"`
-	Bind string `yaml:"bind"`
+	Port int       `yaml:"port"`
+	Bind string    `yaml:"bind"`
+	API  APIConfig `yaml:"api"`
+}
+
+// APIConfig controls the OpenAI-compatible HTTP API
+type APIConfig struct {
+	SystemPrompt string `yaml:"systemPrompt"` // Client system messages "extend" (default) or "replace" the FeelPulse prompt
+	ServerTools  *bool  `yaml:"serverTools"`  // Offer FeelPulse's tools to API clients (nil = enabled)
+	UserSessions bool   `yaml:"userSessions"` // Map the request's user to a FeelPulse session like X-FeelPulse-Session
+
+	// Sessions outside the api channel that any chat key may continue: a
+	// channel ("telegram") or one session ("telegram:123"). Admin keys may
+	// continue any session.
+	SharedSessions []string `yaml:"sharedSessions"`
 }
 
 type AgentConfig struct {
@@ -102,15 +152,62 @@ type ChannelsConfig struct {
 }
 
 type TelegramConfig struct {
-	Enabled      bool     `yaml:"enabled"`
-	BotToken     string   `yaml:"token"`
-	AllowedUsers []string `yaml:"allowedUsers"` // empty = allow all; non-empty = only these usernames
+	Enabled          bool                 `yaml:"enabled"`
+	BotToken         string               `yaml:"token"`
+	AllowedUsers     []string             `yaml:"allowedUsers"` // empty = allow all; non-empty = only these usernames or numeric user/chat IDs
+	Groups           TelegramGroupsConfig `yaml:"groups"`
+	Streaming        bool                 `yaml:"streaming"`        // Stream replies by editing a message as text arrives (default: false)
+	StreamIntervalMs int                  `yaml:"streamIntervalMs"` // Min time between edits in ms (default: 1000, groups at least 3000)
+	APIURL           string               `yaml:"apiURL"`           // Bot API server (default: https://api.telegram.org)
+	FileURL          string               `yaml:"fileURL"`          // File download base URL (default: apiURL + "/file")
+	LocalMode        bool                 `yaml:"localMode"`        // Server runs with --local: 2 GB files, exchanged as paths on disk
+	LiveLocation     bool                 `yaml:"liveLocation"`     // Refresh the stored location from live-location updates
+}
+
+// TelegramGroupsConfig controls bot behavior in group chats
+type TelegramGroupsConfig struct {
+	RequireMention bool                               `yaml:"requireMention"` // Only respond when mentioned or replied to (default: true)
+	TopicSessions  bool                               `yaml:"topicSessions"`  // One session per forum topic instead of per group (default: true)
+	Chats          map[string]TelegramGroupChatConfig `yaml:"chats"`          // Per-chat overrides keyed by chat ID
+}
+
+// TelegramGroupChatConfig holds per-group overrides
+type TelegramGroupChatConfig struct {
+	RequireMention *bool `yaml:"requireMention"` // nil = use the group default
 }
 
 type HooksConfig struct {
-	Enabled bool   `yaml:"enabled"`
-	Token   string `yaml:"token"`
-	Path    string `yaml:"path"`
+	Enabled  bool          `yaml:"enabled"`
+	Token    string        `yaml:"token"`
+	Path     string        `yaml:"path"`
+	Mappings []HookMapping `yaml:"mappings"`
+}
+
+// HookMapping routes webhook requests on a path to the agent
+type HookMapping struct {
+	Path        string `yaml:"path"`        // Webhook path, e.g. /hooks/github ("github" is short for the same)
+	Name        string `yaml:"name"`        // Label for logs and the delivery log (default: last path segment)
+	Prompt      string `yaml:"prompt"`      // Go template rendered with the request, e.g. {{.Payload.action}}
+	Verify      string `yaml:"verify"`      // Signature check: "github" (HMAC), "secret" (shared secret) or "" (hooks.token)
+	Secret      string `yaml:"secret"`      // Key for the signature check
+	SubAgent    bool   `yaml:"subAgent"`    // Run the prompt as a background sub-agent
+	Channel     string `yaml:"channel"`     // Channel to deliver the result to, e.g. telegram
+	To          string `yaml:"to"`          // Chat ID on the channel
+	ServerTools bool   `yaml:"serverTools"` // Let the agent use its tools on the prompt (off: payloads are untrusted)
+}
+
+// WebhooksConfig holds outbound event webhook subscriptions
+type WebhooksConfig struct {
+	MaxAttempts   int                   `yaml:"maxAttempts"` // Attempts before a delivery fails (default: 8)
+	Subscriptions []WebhookSubscription `yaml:"subscriptions"`
+}
+
+// WebhookSubscription posts selected events to a URL
+type WebhookSubscription struct {
+	Name   string   `yaml:"name"`   // Label in logs and /admin webhooks (default: the URL)
+	URL    string   `yaml:"url"`    // Endpoint receiving JSON POSTs
+	Secret string   `yaml:"secret"` // HMAC-SHA256 key for X-FeelPulse-Signature-256 (empty = unsigned)
+	Events []string `yaml:"events"` // Event types to send (empty = all)
 }
 
 func Default() *Config {
@@ -128,7 +225,12 @@ func Default() *Config {
 		},
 		Channels: ChannelsConfig{
 			Telegram: TelegramConfig{
-				Enabled: false,
+				Enabled:          false,
+				StreamIntervalMs: 1000,
+				Groups: TelegramGroupsConfig{
+					RequireMention: true,
+					TopicSessions:  true,
+				},
 			},
 		},
 		Hooks: HooksConfig{
@@ -137,14 +239,28 @@ func Default() *Config {
 		},
 		Workspace: WorkspaceConfig{
 			Path: filepath.Join(home, ".feelpulse", "workspace"),
+			Inbox: InboxConfig{
+				Enabled:   true,
+				MaxSizeMB: 20,
+			},
 		},
 		Heartbeat: HeartbeatConfig{
 			Enabled:         false,
 			IntervalMinutes: 60,
 		},
 		TTS: TTSConfig{
-			Enabled: false,
-			Command: "", // Auto-detect
+			Enabled:   false,
+			Command:   "", // Auto-detect
+			Speed:     1.0,
+			MaxLength: 1000,
+			FFmpeg:    "ffmpeg",
+		},
+		STT: STTConfig{
+			Enabled:            false,
+			FFmpeg:             "ffmpeg",
+			Language:           "auto",
+			MaxDurationSeconds: 300,
+			TimeoutSeconds:     120,
 		},
 		Browser: BrowserConfig{
 			Enabled:        false, // Disabled by default (requires Chrome)
@@ -226,6 +342,11 @@ func (c *Config) Validate() *ValidationResult {
 		if c.Channels.Telegram.BotToken == "" {
 			result.Errors = append(result.Errors, "Telegram enabled but token not set: set channels.telegram.token")
 		}
+		for chatID := range c.Channels.Telegram.Groups.Chats {
+			if _, err := strconv.ParseInt(chatID, 10, 64); err != nil {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("Telegram group override '%s' is not a numeric chat ID and will be ignored", chatID))
+			}
+		}
 	}
 
 	// Check workspace path
@@ -245,6 +366,62 @@ func (c *Config) Validate() *ValidationResult {
 		result.Warnings = append(result.Warnings, fmt.Sprintf("Unknown provider '%s', supported: anthropic, openai", c.Agent.Provider))
 	}
 
+	// Check API system prompt mode
+	if mode := c.Gateway.API.SystemPrompt; mode != "" && mode != "extend" && mode != "replace" {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("Unknown gateway.api.systemPrompt '%s', supported: extend, replace", mode))
+	}
+
+	// Check webhook mappings
+	for i, m := range c.Hooks.Mappings {
+		name := m.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+		if m.Path == "" {
+			result.Errors = append(result.Errors, fmt.Sprintf("Hook mapping %s has no path: set hooks.mappings[].path", name))
+		}
+		if m.Prompt == "" {
+			result.Errors = append(result.Errors, fmt.Sprintf("Hook mapping %s has no prompt: set hooks.mappings[].prompt", name))
+		}
+		switch m.Verify {
+		case "":
+		case "github", "secret":
+			if m.Secret == "" {
+				result.Errors = append(result.Errors, fmt.Sprintf("Hook mapping %s verifies '%s' signatures but has no secret", name, m.Verify))
+			}
+		default:
+			result.Errors = append(result.Errors, fmt.Sprintf("Hook mapping %s has unknown verify '%s', supported: github, secret", name, m.Verify))
+		}
+		if m.Channel != "" && m.To == "" {
+			result.Errors = append(result.Errors, fmt.Sprintf("Hook mapping %s delivers to %s but has no 'to' chat", name, m.Channel))
+		}
+	}
+
+	// Check outbound webhooks
+	for i, sub := range c.Webhooks.Subscriptions {
+		if sub.URL == "" {
+			result.Errors = append(result.Errors, fmt.Sprintf("Webhook subscription #%d has no url: set webhooks.subscriptions[].url", i+1))
+		} else if !strings.HasPrefix(sub.URL, "http://") && !strings.HasPrefix(sub.URL, "https://") {
+			result.Errors = append(result.Errors, fmt.Sprintf("Webhook subscription #%d url must start with http:// or https://", i+1))
+		}
+		if sub.Secret == "" {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("Webhook subscription #%d has no secret: payloads will not be signed", i+1))
+		}
+	}
+
+	// Check dashboard logins
+	if c.Dashboard.Password != "" && c.Dashboard.Password == c.Dashboard.ViewerPassword {
+		result.Errors = append(result.Errors, "dashboard.password and dashboard.viewerPassword must differ")
+	}
+	if c.Dashboard.URL != "" && !strings.HasPrefix(c.Dashboard.URL, "http://") && !strings.HasPrefix(c.Dashboard.URL, "https://") {
+		result.Errors = append(result.Errors, "dashboard.url must start with http:// or https://")
+	}
+
+	// Check speech-to-text command
+	if c.STT.Enabled && c.STT.Command == "" {
+		result.Warnings = append(result.Warnings, "STT enabled but no command set: voice messages will be refused (set stt.command)")
+	}
+
 	// Check browser dependencies
 	if c.Browser.Enabled {
 		result.Warnings = append(result.Warnings, "Browser tools enabled - requires Chrome/Chromium installed")
@@ -142,6 +142,25 @@ func TestValidate_BrowserWarning(t *testing.T) {
 	}
 }
 
+func TestValidate_STTWithoutCommand(t *testing.T) {
+	cfg := Default()
+	cfg.Agent.APIKey = "sk-ant-api-test"
+	cfg.STT.Enabled = true
+
+	result := cfg.Validate()
+
+	hasSTTWarning := false
+	for _, warn := range result.Warnings {
+		if contains(warn, "STT") {
+			hasSTTWarning = true
+			break
+		}
+	}
+	if !hasSTTWarning {
+		t.Error("Expected STT warning when enabled without a command")
+	}
+}
+
 func TestValidate_UnknownProvider(t *testing.T) {
 	cfg := Default()
 	cfg.Agent.APIKey = "sk-ant-api-test"
@@ -161,6 +180,103 @@ func TestValidate_UnknownProvider(t *testing.T) {
 	}
 }
 
+func TestValidate_HookMappings(t *testing.T) {
+	tests := []struct {
+		name    string
+		mapping HookMapping
+		wantErr string
+	}{
+		{"valid", HookMapping{Path: "/hooks/github", Prompt: "{{.Event}}", Verify: "github", Secret: "s", Channel: "telegram", To: "42"}, ""},
+		{"no path", HookMapping{Prompt: "hi"}, "no path"},
+		{"no prompt", HookMapping{Path: "ci"}, "no prompt"},
+		{"unknown verify", HookMapping{Path: "ci", Prompt: "hi", Verify: "md5"}, "unknown verify"},
+		{"no secret", HookMapping{Path: "ci", Prompt: "hi", Verify: "secret"}, "no secret"},
+		{"no chat", HookMapping{Path: "ci", Prompt: "hi", Channel: "telegram"}, "no 'to' chat"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			cfg.Agent.APIKey = "sk-ant-api-test"
+			cfg.Hooks.Mappings = []HookMapping{tt.mapping}
+
+			result := cfg.Validate()
+
+			if tt.wantErr == "" {
+				if len(result.Errors) != 0 {
+					t.Errorf("unexpected errors: %v", result.Errors)
+				}
+				return
+			}
+			found := false
+			for _, err := range result.Errors {
+				if contains(err, tt.wantErr) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, result.Errors)
+			}
+		})
+	}
+}
+
+func TestValidate_WebhookSubscriptions(t *testing.T) {
+	cfg := Default()
+	cfg.Agent.APIKey = "sk-ant-api-test"
+	cfg.Webhooks.Subscriptions = []WebhookSubscription{
+		{URL: "https://example.com/hook", Secret: "s3cret"},
+		{URL: "ftp://example.com/hook", Secret: "s3cret"},
+		{Secret: "s3cret"},
+		{URL: "https://example.com/unsigned"},
+	}
+
+	result := cfg.Validate()
+
+	if len(result.Errors) != 2 || !contains(result.Errors[0], "#2") || !contains(result.Errors[1], "#3") {
+		t.Errorf("unexpected errors: %v", result.Errors)
+	}
+	hasSecretWarning := false
+	for _, warn := range result.Warnings {
+		if contains(warn, "#4 has no secret") {
+			hasSecretWarning = true
+		}
+	}
+	if !hasSecretWarning {
+		t.Error("Expected warning for an unsigned subscription")
+	}
+}
+
+func TestValidate_Dashboard(t *testing.T) {
+	tests := []struct {
+		name      string
+		dashboard DashboardConfig
+		want      string // Expected error, empty for none
+	}{
+		{"no login", DashboardConfig{}, ""},
+		{"passwords", DashboardConfig{Password: "admin", ViewerPassword: "viewer", URL: "https://fp.example.com"}, ""},
+		{"same passwords", DashboardConfig{Password: "same", ViewerPassword: "same"}, "must differ"},
+		{"bad url", DashboardConfig{Password: "admin", URL: "fp.example.com"}, "dashboard.url"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			cfg.Agent.APIKey = "sk-ant-api-test"
+			cfg.Dashboard = tt.dashboard
+
+			result := cfg.Validate()
+			if tt.want == "" && len(result.Errors) > 0 {
+				t.Errorf("unexpected errors: %v", result.Errors)
+			}
+			if tt.want != "" && (len(result.Errors) != 1 || !contains(result.Errors[0], tt.want)) {
+				t.Errorf("errors = %v, want %q", result.Errors, tt.want)
+			}
+		})
+	}
+}
+
 func TestLoadAndSave(t *testing.T) {
 	// Create temp directory
 	tmpDir, err := os.MkdirTemp("", "feelpulse-test")
@@ -0,0 +1,228 @@
+package events
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/FeelPulse/feelpulse/internal/logger"
+)
+
+// DefaultQueueSize is how many events a subscriber can fall behind
+// before further events are dropped for it
+const DefaultQueueSize = 256
+
+// Handler receives events from the bus
+type Handler func(Event)
+
+// SubscriberStats describes a subscriber's queue
+type SubscriberStats struct {
+	Name    string
+	Queued  int
+	Dropped int64
+}
+
+// Bus is an in-process publish/subscribe event bus.
+//
+// Each subscriber has its own queue and goroutine: it sees events in
+// the order they were published, and a slow subscriber never blocks
+// the publisher or other subscribers. When a subscriber's queue is
+// full, events are dropped for that subscriber only. Subscribers that
+// must see every event use SubscribeLossless instead, and the publisher
+// waits for them when they fall behind.
+//
+// A nil *Bus is valid and discards all events.
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers []*subscriber
+	closed      bool
+	queueSize   int
+	wg          sync.WaitGroup
+	log         *logger.Logger
+}
+
+type subscriber struct {
+	name     string
+	types    map[string]bool // Empty = all events
+	handler  Handler
+	queue    chan Event
+	lossless bool // Publish waits instead of dropping
+	dropped  atomic.Int64
+}
+
+// New creates an event bus
+func New(log *logger.Logger) *Bus {
+	if log == nil {
+		log = logger.GetDefaultLogger()
+	}
+	return &Bus{
+		queueSize: DefaultQueueSize,
+		log:       log.WithComponent("events"),
+	}
+}
+
+// SetQueueSize sets the queue size for subscribers added afterwards
+func (b *Bus) SetQueueSize(size int) {
+	if size <= 0 {
+		size = DefaultQueueSize
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.queueSize = size
+}
+
+// Subscribe registers a handler for the given event types (none = all
+// events). The returned function removes the subscription; events
+// already queued are still handled.
+func (b *Bus) Subscribe(name string, handler Handler, eventTypes ...string) (unsubscribe func()) {
+	return b.subscribe(name, handler, false, eventTypes)
+}
+
+// SubscribeLossless is like Subscribe, but the subscriber never misses an
+// event: when its queue is full, Publish blocks until there is room, so
+// its handler must not publish events itself.
+func (b *Bus) SubscribeLossless(name string, handler Handler, eventTypes ...string) (unsubscribe func()) {
+	return b.subscribe(name, handler, true, eventTypes)
+}
+
+func (b *Bus) subscribe(name string, handler Handler, lossless bool, eventTypes []string) (unsubscribe func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return func() {}
+	}
+
+	s := &subscriber{
+		name:     name,
+		types:    make(map[string]bool, len(eventTypes)),
+		handler:  handler,
+		queue:    make(chan Event, b.queueSize),
+		lossless: lossless,
+	}
+	for _, t := range eventTypes {
+		s.types[t] = true
+	}
+	b.subscribers = append(b.subscribers, s)
+
+	b.wg.Add(1)
+	go b.run(s)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { b.remove(s) })
+	}
+}
+
+// On subscribes a handler to one typed event, e.g.
+//
+//	events.On(bus, "metrics", func(e events.ReplySent) { ... })
+func On[T Event](b *Bus, name string, handler func(T)) (unsubscribe func()) {
+	var zero T
+	return b.Subscribe(name, func(e Event) {
+		if ev, ok := e.(T); ok {
+			handler(ev)
+		}
+	}, zero.Type())
+}
+
+// Publish queues an event for every subscriber that wants it. It only
+// blocks while a lossless subscriber's queue is full; events published
+// after Close are discarded.
+func (b *Bus) Publish(e Event) {
+	if b == nil || e == nil {
+		return
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return
+	}
+	for _, s := range b.subscribers {
+		if len(s.types) > 0 && !s.types[e.Type()] {
+			continue
+		}
+		if s.lossless {
+			s.queue <- e
+			continue
+		}
+		select {
+		case s.queue <- e:
+		default:
+			// Warn on the first drop and then every 100th
+			if n := s.dropped.Add(1); n%100 == 1 {
+				b.log.Warn("⚠️ Subscriber %s is falling behind, dropped %d event(s)", s.name, n)
+			}
+		}
+	}
+}
+
+// Stats returns the queue state of each subscriber
+func (b *Bus) Stats() []SubscriberStats {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	stats := make([]SubscriberStats, len(b.subscribers))
+	for i, s := range b.subscribers {
+		stats[i] = SubscriberStats{Name: s.name, Queued: len(s.queue), Dropped: s.dropped.Load()}
+	}
+	return stats
+}
+
+// Close stops accepting events and waits until subscribers have handled
+// the events already queued
+func (b *Bus) Close() {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	if !b.closed {
+		b.closed = true
+		for _, s := range b.subscribers {
+			close(s.queue)
+		}
+		b.subscribers = nil
+	}
+	b.mu.Unlock()
+
+	b.wg.Wait()
+}
+
+// remove unregisters a subscriber and lets it drain its queue
+func (b *Bus) remove(s *subscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for i, sub := range b.subscribers {
+		if sub == s {
+			b.subscribers = append(b.subscribers[:i], b.subscribers[i+1:]...)
+			close(s.queue)
+			return
+		}
+	}
+}
+
+// run delivers a subscriber's events in order
+func (b *Bus) run(s *subscriber) {
+	defer b.wg.Done()
+	for e := range s.queue {
+		b.deliver(s, e)
+	}
+}
+
+// deliver calls a handler, surviving panics so one bad subscriber
+// cannot take down the others
+func (b *Bus) deliver(s *subscriber, e Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			b.log.Error("❌ Subscriber %s panicked on %s: %v", s.name, e.Type(), r)
+		}
+	}()
+	s.handler(e)
+}
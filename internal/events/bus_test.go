@@ -0,0 +1,197 @@
+package events
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/FeelPulse/feelpulse/internal/logger"
+)
+
+func newTestBus() *Bus {
+	return New(logger.New(&logger.Config{Level: "error"}))
+}
+
+func TestBus_OrderedDelivery(t *testing.T) {
+	bus := newTestBus()
+
+	var got []string
+	bus.Subscribe("all", func(e Event) {
+		got = append(got, e.Type())
+	})
+
+	published := []Event{
+		MessageReceived{Text: "hi"},
+		ToolCalled{Tool: "exec"},
+		ReplySent{Text: "hello"},
+		ConfigReloaded{},
+	}
+	for _, e := range published {
+		bus.Publish(e)
+	}
+	bus.Close()
+
+	if len(got) != len(published) {
+		t.Fatalf("got %d events, want %d", len(got), len(published))
+	}
+	for i, e := range published {
+		if got[i] != e.Type() {
+			t.Errorf("event %d = %s, want %s", i, got[i], e.Type())
+		}
+	}
+}
+
+func TestBus_Filters(t *testing.T) {
+	tests := []struct {
+		name  string
+		types []string
+		want  int
+	}{
+		{"all events", nil, 3},
+		{"one type", []string{TypeReplySent}, 1},
+		{"two types", []string{TypeReplySent, TypeReminderFired}, 2},
+		{"unpublished type", []string{TypeConfigReloaded}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus := newTestBus()
+			count := 0
+			bus.Subscribe(tt.name, func(Event) { count++ }, tt.types...)
+
+			bus.Publish(MessageReceived{})
+			bus.Publish(ReplySent{})
+			bus.Publish(ReminderFired{})
+			bus.Close()
+
+			if count != tt.want {
+				t.Errorf("got %d events, want %d", count, tt.want)
+			}
+		})
+	}
+}
+
+func TestOn_Typed(t *testing.T) {
+	bus := newTestBus()
+
+	var tokens int
+	On(bus, "tokens", func(e ReplySent) {
+		tokens += e.InputTokens + e.OutputTokens
+	})
+
+	bus.Publish(ReplySent{InputTokens: 10, OutputTokens: 5})
+	bus.Publish(MessageReceived{})
+	bus.Publish(ReplySent{InputTokens: 1, OutputTokens: 2})
+	bus.Close()
+
+	if tokens != 18 {
+		t.Errorf("tokens = %d, want 18", tokens)
+	}
+}
+
+func TestBus_SlowSubscriberDoesNotBlock(t *testing.T) {
+	bus := newTestBus()
+	bus.SetQueueSize(2)
+
+	release := make(chan struct{})
+	bus.Subscribe("slow", func(Event) { <-release })
+
+	var mu sync.Mutex
+	fast := 0
+	bus.Subscribe("fast", func(Event) {
+		mu.Lock()
+		fast++
+		mu.Unlock()
+	})
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 10; i++ {
+			bus.Publish(ReminderFired{})
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Publish blocked on a slow subscriber")
+	}
+
+	var dropped int64
+	for _, s := range bus.Stats() {
+		if s.Name == "slow" {
+			dropped = s.Dropped
+		}
+	}
+	if dropped == 0 {
+		t.Error("slow subscriber should have dropped events")
+	}
+
+	close(release)
+	bus.Close()
+}
+
+func TestBus_PanicAndUnsubscribe(t *testing.T) {
+	bus := newTestBus()
+
+	count := 0
+	bus.Subscribe("panics", func(Event) { panic("boom") })
+	unsubscribe := bus.Subscribe("counter", func(Event) { count++ })
+
+	bus.Publish(ConfigReloaded{})
+	unsubscribe()
+	unsubscribe() // Safe to call twice
+	bus.Publish(ConfigReloaded{})
+	bus.Close()
+
+	if count != 1 {
+		t.Errorf("count = %d, want 1", count)
+	}
+
+	// Publishing after Close and on a nil bus is a no-op
+	bus.Publish(ConfigReloaded{})
+	var nilBus *Bus
+	nilBus.Publish(ConfigReloaded{})
+	nilBus.Close()
+}
+
+func TestBus_LosslessSubscriberWaits(t *testing.T) {
+	bus := newTestBus()
+	bus.SetQueueSize(2)
+
+	release := make(chan struct{})
+	var mu sync.Mutex
+	got := 0
+	bus.SubscribeLossless("outbox", func(Event) {
+		<-release
+		mu.Lock()
+		got++
+		mu.Unlock()
+	})
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 10; i++ {
+			bus.Publish(ReminderFired{})
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("Publish should wait for a lossless subscriber that fell behind")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	<-done
+	bus.Close()
+
+	if got != 10 {
+		t.Errorf("lossless subscriber got %d events, want 10", got)
+	}
+	if s := bus.Stats(); len(s) != 0 {
+		t.Errorf("unexpected stats after Close: %+v", s)
+	}
+}
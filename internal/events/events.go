@@ -0,0 +1,167 @@
+// Package events provides a typed in-process event bus for the message
+// lifecycle. Subsystems publish events; metrics, logs, webhooks and
+// extensions observe them as subscribers without the publisher knowing.
+package events
+
+import (
+	"time"
+
+	"github.com/FeelPulse/feelpulse/pkg/types"
+)
+
+// Event types
+const (
+	TypeMessageAccepted   = "message.accepted"
+	TypeMessageReceived   = "message.received"
+	TypeReplySent         = "reply.sent"
+	TypeToolStarted       = "tool.started"
+	TypeToolCalled        = "tool.called"
+	TypeTurnStarted       = "turn.started"
+	TypeTurnFailed        = "turn.failed"
+	TypeSubAgentStarted   = "subagent.started"
+	TypeSubAgentCompleted = "subagent.completed"
+	TypeReminderFired     = "reminder.fired"
+	TypeConfigReloaded    = "config.reloaded"
+	TypeAPIReplied        = "api.replied"
+)
+
+// Event is anything published on the bus
+type Event interface {
+	// Type returns the event type, e.g. "message.received"
+	Type() string
+}
+
+// MessageAccepted is published for every incoming channel message the
+// gateway takes, slash commands and rate-limited messages included
+type MessageAccepted struct {
+	Channel string `json:"channel"`
+	UserID  string `json:"user_id"`
+	From    string `json:"from"`
+}
+
+func (MessageAccepted) Type() string { return TypeMessageAccepted }
+
+// MessageReceived is published when an incoming channel message is added
+// to its session for the agent. Slash commands and rate-limited messages
+// never get there.
+type MessageReceived struct {
+	Channel   string `json:"channel"`
+	UserID    string `json:"user_id"`
+	Session   string `json:"session"`
+	MessageID string `json:"message_id,omitempty"`
+	From      string `json:"from"`
+	Text      string `json:"text"`
+
+	Message types.Message `json:"-"`
+}
+
+func (MessageReceived) Type() string { return TypeMessageReceived }
+
+// ReplySent is published when the agent's reply is added to a session
+type ReplySent struct {
+	Channel      string `json:"channel"`
+	UserID       string `json:"user_id"`
+	Session      string `json:"session"`
+	ReplyID      string `json:"reply_id"`
+	Text         string `json:"text"`
+	Model        string `json:"model,omitempty"`
+	InputTokens  int    `json:"input_tokens,omitempty"`
+	OutputTokens int    `json:"output_tokens,omitempty"`
+
+	Reply types.Message `json:"-"`
+}
+
+func (ReplySent) Type() string { return TypeReplySent }
+
+// ToolStarted is published when the agent or a sub-agent starts a tool
+type ToolStarted struct {
+	Session string `json:"session"`
+	Tool    string `json:"tool"`
+	Summary string `json:"summary,omitempty"` // Short description of the input
+}
+
+func (ToolStarted) Type() string { return TypeToolStarted }
+
+// ToolCalled is published after the agent or a sub-agent ran a tool
+type ToolCalled struct {
+	Session string         `json:"session"`
+	Tool    string         `json:"tool"`
+	Input   map[string]any `json:"input"`
+	Error   string         `json:"error,omitempty"`
+}
+
+func (ToolCalled) Type() string { return TypeToolCalled }
+
+// TurnStarted is published when the agent starts working on a message.
+// The turn ends with a reply.sent or turn.failed event for its session.
+type TurnStarted struct {
+	Channel string `json:"channel"`
+	UserID  string `json:"user_id"`
+	Session string `json:"session"`
+	From    string `json:"from"`
+	Text    string `json:"text"`
+}
+
+func (TurnStarted) Type() string { return TypeTurnStarted }
+
+// TurnFailed is published when the agent could not reply to a message
+type TurnFailed struct {
+	Channel string `json:"channel"`
+	UserID  string `json:"user_id"`
+	Session string `json:"session"`
+	Error   string `json:"error"`
+}
+
+func (TurnFailed) Type() string { return TypeTurnFailed }
+
+// SubAgentStarted is published when a sub-agent starts running
+type SubAgentStarted struct {
+	ID      string `json:"id"`
+	Label   string `json:"label"`
+	Session string `json:"session"`
+	Task    string `json:"task"`
+}
+
+func (SubAgentStarted) Type() string { return TypeSubAgentStarted }
+
+// SubAgentCompleted is published when a sub-agent finishes or fails
+type SubAgentCompleted struct {
+	ID         string        `json:"id"`
+	Label      string        `json:"label"`
+	Session    string        `json:"session"`
+	Result     string        `json:"result"`
+	DurationMS int64         `json:"duration_ms"`
+	Error      string        `json:"error,omitempty"`
+	Duration   time.Duration `json:"-"`
+}
+
+func (SubAgentCompleted) Type() string { return TypeSubAgentCompleted }
+
+// ReminderFired is published when a reminder is due
+type ReminderFired struct {
+	ID      string `json:"id"`
+	Channel string `json:"channel"`
+	UserID  string `json:"user_id"`
+	Message string `json:"message"`
+}
+
+func (ReminderFired) Type() string { return TypeReminderFired }
+
+// ConfigReloaded is published after a config hot reload
+type ConfigReloaded struct {
+	AgentChanged    bool `json:"agent_changed"`
+	TelegramChanged bool `json:"telegram_changed"`
+}
+
+func (ConfigReloaded) Type() string { return TypeConfigReloaded }
+
+// APIReplied is published when the agent answers a request of a compatible
+// API endpoint ("openai-compat", "anthropic-compat") or a webhook mapping
+// ("hooks")
+type APIReplied struct {
+	Endpoint     string `json:"endpoint"`
+	InputTokens  int    `json:"input_tokens,omitempty"`
+	OutputTokens int    `json:"output_tokens,omitempty"`
+}
+
+func (APIReplied) Type() string { return TypeAPIReplied }
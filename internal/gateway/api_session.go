@@ -0,0 +1,150 @@
+package gateway
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/FeelPulse/feelpulse/internal/apikey"
+	"github.com/FeelPulse/feelpulse/internal/session"
+	"github.com/FeelPulse/feelpulse/pkg/types"
+)
+
+// APISessionHeader names the FeelPulse session an API request continues
+const APISessionHeader = "X-FeelPulse-Session"
+
+// errAPISessionForbidden is returned for sessions of other channels or API
+// keys the request may not continue
+var errAPISessionForbidden = errors.New("sessions of other channels or API keys need an admin key or gateway.api.sharedSessions")
+
+// apiSession is a FeelPulse session used by an API client
+type apiSession struct {
+	channel string
+	userID  string
+}
+
+// key returns the session key
+func (s *apiSession) key() string {
+	return session.SessionKey(s.channel, s.userID)
+}
+
+// apiSessionFor returns the session named by the request header or, with
+// gateway.api.userSessions, by the request's user. A bare name ("alice")
+// belongs to the api channel, scoped to the request's API key so keys don't
+// share history; "telegram:123" continues a Telegram chat and "api:<key
+// id>.alice" another key's session, which only admin keys and
+// gateway.api.sharedSessions allow. Returns nil for stateless requests.
+func (gw *Gateway) apiSessionFor(r *http.Request, user string) (*apiSession, error) {
+	name := strings.TrimSpace(r.Header.Get(APISessionHeader))
+	if name == "" && gw.cfg.Gateway.API.UserSessions {
+		name = strings.TrimSpace(user)
+	}
+	if name == "" {
+		return nil, nil
+	}
+
+	channel, userID, found := strings.Cut(name, ":")
+	if !found {
+		channel, userID = "api", name
+		if key := apikey.FromContext(r.Context()); key != nil {
+			userID = key.ID + "." + name
+		}
+	}
+	if channel == "" || userID == "" {
+		return nil, fmt.Errorf("invalid session '%s': use a name or channel:id", name)
+	}
+	s := &apiSession{channel: channel, userID: userID}
+	if !ownsAPISession(r, s) && !gw.mayContinueSession(r, s) {
+		return nil, errAPISessionForbidden
+	}
+	return s, nil
+}
+
+// ownsAPISession reports whether s is an api channel session of the
+// request's API key. Requests without a key own every api session, as
+// before keys existed
+func ownsAPISession(r *http.Request, s *apiSession) bool {
+	if s.channel != "api" {
+		return false
+	}
+	key := apikey.FromContext(r.Context())
+	return key == nil || strings.HasPrefix(s.userID, key.ID+".")
+}
+
+// mayContinueSession reports whether a request may continue a session of
+// another channel: with an admin key, the legacy hooks.token, or when
+// gateway.api.sharedSessions lists the session or its channel
+func (gw *Gateway) mayContinueSession(r *http.Request, s *apiSession) bool {
+	for _, shared := range gw.cfg.Gateway.API.SharedSessions {
+		if shared == s.channel || shared == s.key() {
+			return true
+		}
+	}
+	if key := apikey.FromContext(r.Context()); key != nil {
+		return key.HasScope(apikey.ScopeAdmin)
+	}
+	// Without a key, only the legacy hooks.token, which grants every scope,
+	// got the request this far when authentication is on
+	return gw.authRequired(apikey.ScopeChat)
+}
+
+// startAPITurn adds the new turn of an API request to the session and
+// returns the history for the agent and the session's pinned notes.
+// Clients may resend the whole conversation; only the messages after the
+// last assistant message are new, earlier ones come from the session.
+func (gw *Gateway) startAPITurn(s *apiSession, messages []types.Message) ([]types.Message, string, error) {
+	turn := messages
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].IsBot {
+			turn = messages[i+1:]
+			break
+		}
+	}
+	if len(turn) == 0 {
+		return nil, "", fmt.Errorf("messages must end with a new user message")
+	}
+
+	gw.mu.RLock()
+	compactor := gw.compactor
+	gw.mu.RUnlock()
+
+	sess := gw.sessions.GetOrCreate(s.channel, s.userID)
+	for _, msg := range turn {
+		// The user ID lets tools such as spawn_agent find the session
+		metadata := map[string]any{"user_id": s.userID}
+		for k, v := range msg.Metadata {
+			metadata[k] = v
+		}
+		msg.Channel = s.channel
+		msg.Metadata = metadata
+		gw.sessions.AddMessageAndPersist(s.channel, s.userID, msg)
+	}
+
+	reqLog := gw.log.WithComponent("api").WithRequestID(s.key())
+	reqLog.Info("📡 API turn in session %s (%d new messages)", s.key(), len(turn))
+
+	history := gw.contextHistory(s.channel, s.userID, sess, compactor, reqLog)
+	return history, gw.sessionPins(s.key()), nil
+}
+
+// finishAPITurn stores the agent's reply in the session
+func (gw *Gateway) finishAPITurn(s *apiSession, reply *types.Message) {
+	stored := *reply
+	stored.Channel = s.channel
+	if stored.ID == "" {
+		stored.ID = newReplyID()
+	}
+	gw.sessions.AddMessageAndPersist(s.channel, s.userID, stored)
+}
+
+// joinSystem combines pinned notes with a client's system prompt
+func joinSystem(pins, system string) string {
+	switch {
+	case pins == "":
+		return system
+	case system == "":
+		return pins
+	}
+	return pins + "\n\n" + system
+}
@@ -0,0 +1,166 @@
+package gateway
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/FeelPulse/feelpulse/internal/apikey"
+	"github.com/FeelPulse/feelpulse/internal/config"
+	"github.com/FeelPulse/feelpulse/internal/logger"
+	"github.com/FeelPulse/feelpulse/internal/session"
+	"github.com/FeelPulse/feelpulse/pkg/types"
+)
+
+func TestAPISessionFor(t *testing.T) {
+	tests := []struct {
+		name         string
+		header       string
+		user         string
+		userSessions bool
+		want         string
+		wantErr      bool
+	}{
+		{"stateless", "", "", false, "", false},
+		{"bare name", "alice", "", false, "api:alice", false},
+		{"api channel and id", "api:alice", "", false, "api:alice", false},
+		{"shared channel", "telegram:12345", "", false, "telegram:12345", false},
+		{"shared session", "discord:42", "", false, "discord:42", false},
+		{"other channel", "discord:43", "", false, "", true},
+		{"other channel via user", "", "slack:7", true, "", true},
+		{"user ignored by default", "", "bob", false, "", false},
+		{"user sessions", "", "bob", true, "api:bob", false},
+		{"header wins over user", "alice", "bob", true, "api:alice", false},
+		{"missing channel", ":12345", "", false, "", true},
+		{"missing id", "telegram:", "", false, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.Gateway.API.UserSessions = tt.userSessions
+			cfg.Gateway.API.SharedSessions = []string{"telegram", "discord:42"}
+			gw := &Gateway{cfg: cfg}
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", nil)
+			if tt.header != "" {
+				req.Header.Set(APISessionHeader, tt.header)
+			}
+
+			sess, err := gw.apiSessionFor(req, tt.user)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var got string
+			if sess != nil {
+				got = sess.key()
+			}
+			if got != tt.want {
+				t.Errorf("session = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAPITurn(t *testing.T) {
+	gw := &Gateway{
+		cfg:      config.Default(),
+		sessions: session.NewStore(),
+		log:      logger.New(&logger.Config{Level: "error"}),
+	}
+	gw.sessions.AddMessageAndPersist("telegram", "12345", types.Message{Text: "Hi from Telegram", Channel: "telegram"})
+	gw.sessions.AddMessageAndPersist("telegram", "12345", types.Message{Text: "Hello!", Channel: "telegram", IsBot: true})
+
+	sess := &apiSession{channel: "telegram", userID: "12345"}
+
+	// The client resends its own copy of the conversation; only the new turn counts
+	history, _, err := gw.startAPITurn(sess, []types.Message{
+		{Text: "old question", Channel: "api"},
+		{Text: "old answer", Channel: "api", IsBot: true},
+		{Text: "What did I say earlier?", Channel: "api"},
+	})
+	if err != nil {
+		t.Fatalf("startAPITurn error: %v", err)
+	}
+	if len(history) != 3 || history[0].Text != "Hi from Telegram" || history[2].Text != "What did I say earlier?" {
+		t.Fatalf("unexpected history: %+v", history)
+	}
+	if history[2].Channel != "telegram" || history[2].Metadata["user_id"] != "12345" {
+		t.Errorf("new message should belong to the session: %+v", history[2])
+	}
+
+	gw.finishAPITurn(sess, &types.Message{Text: "You said hi.", Channel: "api", IsBot: true})
+	stored, _ := gw.sessions.Get("telegram", "12345")
+	messages := stored.GetAllMessages()
+	if len(messages) != 4 || messages[3].Text != "You said hi." || messages[3].ID == "" {
+		t.Errorf("reply should be stored with an ID: %+v", messages)
+	}
+
+	if _, _, err := gw.startAPITurn(sess, []types.Message{{Text: "answer", IsBot: true}}); err == nil {
+		t.Error("expected an error without a new user message")
+	}
+}
+
+func TestJoinSystem(t *testing.T) {
+	tests := []struct {
+		pins, system, want string
+	}{
+		{"", "", ""},
+		{"pins", "", "pins"},
+		{"", "system", "system"},
+		{"pins", "system", "pins\n\nsystem"},
+	}
+
+	for _, tt := range tests {
+		if got := joinSystem(tt.pins, tt.system); got != tt.want {
+			t.Errorf("joinSystem(%q, %q) = %q, want %q", tt.pins, tt.system, got, tt.want)
+		}
+	}
+}
+
+func TestAPISessionFor_KeyScopes(t *testing.T) {
+	gw := newAPIKeyGateway(t)
+	chat, chatKey, _ := gw.apiKeys.Create(apikey.CreateOptions{Name: "chat", Scopes: []string{"chat"}})
+	other, otherKey, _ := gw.apiKeys.Create(apikey.CreateOptions{Name: "other", Scopes: []string{"chat"}})
+	admin, _, _ := gw.apiKeys.Create(apikey.CreateOptions{Name: "admin", Scopes: []string{"admin"}})
+	gw.cfg.Hooks.Token = "legacy"
+
+	tests := []struct {
+		name    string
+		token   string
+		session string
+		want    string
+		wantErr error
+	}{
+		{"chat key on api session", chat, "alice", "api:" + chatKey.ID + ".alice", nil},
+		{"same name on another key", other, "alice", "api:" + otherKey.ID + ".alice", nil},
+		{"chat key on its own scoped session", chat, "api:" + chatKey.ID + ".alice", "api:" + chatKey.ID + ".alice", nil},
+		{"chat key on another key's session", other, "api:" + chatKey.ID + ".alice", "", errAPISessionForbidden},
+		{"chat key on unscoped api session", chat, "api:alice", "", errAPISessionForbidden},
+		{"admin key on another key's session", admin, "api:" + chatKey.ID + ".alice", "api:" + chatKey.ID + ".alice", nil},
+		{"chat key on telegram session", chat, "telegram:12345", "", errAPISessionForbidden},
+		{"admin key on telegram session", admin, "telegram:12345", "telegram:12345", nil},
+		{"legacy token on api session", "legacy", "alice", "api:alice", nil},
+		{"legacy token on telegram session", "legacy", "telegram:12345", "telegram:12345", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			req.Header.Set(APISessionHeader, tt.session)
+			r, ok := gw.authorize(httptest.NewRecorder(), req, apikey.ScopeChat)
+			if !ok {
+				t.Fatal("request should be authorized")
+			}
+			sess, err := gw.apiSessionFor(r, "")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+			if sess != nil && sess.key() != tt.want {
+				t.Errorf("session = %q, want %q", sess.key(), tt.want)
+			}
+		})
+	}
+}
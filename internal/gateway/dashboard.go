@@ -4,7 +4,11 @@ import (
 	"fmt"
 	"html/template"
 	"net/http"
+	"strings"
 	"time"
+
+	"github.com/FeelPulse/feelpulse/internal/apikey"
+	"github.com/FeelPulse/feelpulse/internal/webauth"
 )
 
 // DashboardData holds data for the dashboard
@@ -22,6 +26,13 @@ type DashboardData struct {
 	Channels       map[string]bool `json:"channels"`
 	Agent          string          `json:"agent"`
 	RecentActivity []ActivityEntry `json:"recent_activity"`
+	Feedback       []FeedbackEntry `json:"feedback,omitempty"`
+	Webhooks       []WebhookEntry  `json:"webhooks,omitempty"`
+	APIKeys        []APIKeyEntry   `json:"api_keys,omitempty"`
+	KeysEnabled    bool            `json:"keys_enabled"`
+	LoggedIn       bool            `json:"-"`         // Viewed with a login session
+	ReadOnly       bool            `json:"read_only"` // Viewer login
+	CSRFToken      string          `json:"-"`
 }
 
 // ActivityEntry represents recent activity
@@ -32,13 +43,57 @@ type ActivityEntry struct {
 	Preview   string `json:"preview"`
 }
 
+// FeedbackEntry represents reactions to replies from one model/profile
+type FeedbackEntry struct {
+	Model    string `json:"model"`
+	Profile  string `json:"profile"`
+	Positive int    `json:"positive"`
+	Negative int    `json:"negative"`
+	Total    int    `json:"total"`
+	Rate     string `json:"rate"` // Share of positive ratings, "-" if none
+}
+
+// WebhookEntry represents one webhook delivery
+type WebhookEntry struct {
+	ID         string `json:"id"`
+	ReceivedAt string `json:"received_at"`
+	Mapping    string `json:"mapping"`
+	Event      string `json:"event"`
+	Status     string `json:"status"`
+	Detail     string `json:"detail"` // Error, else delivery target
+}
+
+// APIKeyEntry represents one API key
+type APIKeyEntry struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Prefix   string `json:"prefix"`
+	Scopes   string `json:"scopes"`
+	Models   string `json:"models"` // "all" if unrestricted
+	Status   string `json:"status"`
+	Expires  string `json:"expires"`
+	LastUsed string `json:"last_used"`
+	Requests int64  `json:"requests"`
+	Tokens   int64  `json:"tokens"`
+}
+
 // handleDashboard serves the web dashboard
 func (gw *Gateway) handleDashboard(w http.ResponseWriter, r *http.Request) {
-	if !gw.checkAuth(w, r) {
+	r, ok := gw.authorize(w, r, apikey.ScopeAdmin)
+	if !ok {
 		return
 	}
 
 	data := gw.collectDashboardData()
+	if sess := webauth.FromContext(r.Context()); sess != nil {
+		data.LoggedIn = true
+		data.ReadOnly = sess.ReadOnly()
+		data.CSRFToken = sess.CSRFToken
+	}
+	if data.ReadOnly {
+		// Keys are admin-only, even to look at
+		data.KeysEnabled, data.APIKeys = false, nil
+	}
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	w.Write([]byte(generateDashboardHTML(data)))
@@ -88,9 +143,120 @@ func (gw *Gateway) collectDashboardData() DashboardData {
 	// Recent activity
 	data.RecentActivity = gw.getRecentActivity()
 
+	// Feedback on replies
+	data.Feedback = gw.getFeedback()
+
+	// Webhook delivery log
+	data.Webhooks = gw.getWebhooks()
+
+	// API keys
+	data.KeysEnabled = gw.apiKeys != nil
+	data.APIKeys = gw.getAPIKeys()
+
 	return data
 }
 
+// getFeedback returns reaction counts per model and profile
+func (gw *Gateway) getFeedback() []FeedbackEntry {
+	if gw.feedback == nil {
+		return nil
+	}
+
+	stats, err := gw.feedback.FeedbackStats()
+	if err != nil {
+		gw.log.Warn("Failed to load feedback stats: %v", err)
+		return nil
+	}
+
+	entries := make([]FeedbackEntry, 0, len(stats))
+	for _, st := range stats {
+		entry := FeedbackEntry{
+			Model:    st.Model,
+			Profile:  st.Profile,
+			Positive: st.Positive,
+			Negative: st.Negative,
+			Total:    st.Total,
+			Rate:     "-",
+		}
+		if entry.Profile == "" {
+			entry.Profile = "default"
+		}
+		if rated := st.Positive + st.Negative; rated > 0 {
+			entry.Rate = fmt.Sprintf("%d%%", st.Positive*100/rated)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// getWebhooks returns recent webhook deliveries, newest first
+func (gw *Gateway) getWebhooks() []WebhookEntry {
+	if gw.hooks == nil {
+		return nil
+	}
+
+	deliveries := gw.hooks.recent()
+	entries := make([]WebhookEntry, 0, len(deliveries))
+	for _, d := range deliveries {
+		entry := WebhookEntry{
+			ID:         d.ID,
+			ReceivedAt: d.ReceivedAt.Format("Jan 2 15:04:05"),
+			Mapping:    d.Mapping,
+			Event:      d.Event,
+			Status:     d.Status,
+			Detail:     d.Target,
+		}
+		if d.Error != "" {
+			entry.Detail = truncatePreview(d.Error, 80)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// getAPIKeys returns all API keys, newest first
+func (gw *Gateway) getAPIKeys() []APIKeyEntry {
+	if gw.apiKeys == nil {
+		return nil
+	}
+
+	keys, err := gw.apiKeys.List()
+	if err != nil {
+		gw.log.Warn("Failed to load API keys: %v", err)
+		return nil
+	}
+	now := time.Now()
+	entries := make([]APIKeyEntry, 0, len(keys))
+	for _, k := range keys {
+		entry := APIKeyEntry{
+			ID:       k.ID,
+			Name:     k.Name,
+			Prefix:   k.Prefix,
+			Scopes:   strings.Join(k.Scopes, ", "),
+			Models:   "all",
+			Status:   k.Status(now),
+			Expires:  "never",
+			LastUsed: "never",
+			Requests: k.Requests,
+			Tokens:   k.InputTokens + k.OutputTokens,
+		}
+		if k.ServerTools {
+			entry.Scopes += " + server tools"
+		}
+		if len(k.Models) > 0 {
+			entry.Models = strings.Join(k.Models, ", ")
+		}
+		if !k.ExpiresAt.IsZero() {
+			entry.Expires = k.ExpiresAt.Format("Jan 2 2006")
+		}
+		if !k.LastUsedAt.IsZero() {
+			entry.LastUsed = k.LastUsedAt.Format("Jan 2 15:04:05")
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
 // getRecentActivity returns recent session activity
 func (gw *Gateway) getRecentActivity() []ActivityEntry {
 	sessions := gw.sessions.GetRecent(10)
@@ -218,6 +384,36 @@ const dashboardTemplate = `<!DOCTYPE html>
         }
         .activity-preview { font-size: 0.9rem; }
         .full-width { grid-column: 1 / -1; }
+        .feedback-table, .live-table { width: 100%; border-collapse: collapse; font-size: 0.9rem; }
+        .feedback-table th, .live-table th { text-align: left; color: #888; font-weight: 500; padding: 0.5rem 0; }
+        .feedback-table td, .live-table td { padding: 0.5rem 0; border-top: 1px solid rgba(255,255,255,0.1); }
+        .key-form { display: flex; gap: 0.5rem; flex-wrap: wrap; margin-top: 1rem; }
+        .key-form input {
+            background: rgba(0,0,0,0.3);
+            border: 1px solid rgba(255,255,255,0.2);
+            border-radius: 6px;
+            color: #eee;
+            padding: 0.4rem 0.6rem;
+            font-size: 0.85rem;
+        }
+        .key-form button, .revoke-btn {
+            background: #166534;
+            border: none;
+            border-radius: 6px;
+            color: #fff;
+            padding: 0.4rem 0.8rem;
+            font-size: 0.85rem;
+            cursor: pointer;
+        }
+        .revoke-btn { background: #7f1d1d; padding: 0.2rem 0.6rem; }
+        .key-token { margin-top: 0.75rem; font-family: monospace; color: #4ade80; word-break: break-all; }
+        .live-status { float: right; font-size: 0.75rem; color: #888; text-transform: none; letter-spacing: 0; }
+        .live-status::before { content: '●'; margin-right: 0.3rem; color: #6b7280; }
+        .live-status.connected::before { color: #4ade80; }
+        .live-empty { color: #666; font-size: 0.9rem; }
+        .tool-badge { background: #1e3a8a; color: #93c5fd; border-radius: 4px; padding: 0.1rem 0.4rem; font-size: 0.8rem; }
+        .live-feed { max-height: 320px; overflow-y: auto; }
+        .live-feed .activity-item.error .activity-preview { color: #f87171; }
         footer {
             margin-top: 2rem;
             text-align: center;
@@ -234,8 +430,14 @@ const dashboardTemplate = `<!DOCTYPE html>
                 <h1>FeelPulse Dashboard</h1>
                 <p class="subtitle">v{{.Version}} • AI Assistant Platform</p>
             </div>
-            <div style="margin-left: auto;">
+            <div style="margin-left: auto; display: flex; gap: 1rem; align-items: center;">
                 <a href="/dashboard/config" style="color: #4ade80; text-decoration: none; font-size: 0.9rem;">⚙️ Config</a>
+                {{if .LoggedIn}}
+                <form method="POST" action="/dashboard/logout">
+                    <input type="hidden" name="csrf_token" value="{{.CSRFToken}}">
+                    <button type="submit" style="background: none; border: none; color: #888; cursor: pointer; font-size: 0.9rem;">{{if .ReadOnly}}👁️ Viewer • {{end}}Log out</button>
+                </form>
+                {{end}}
             </div>
         </header>
 
@@ -247,12 +449,12 @@ const dashboardTemplate = `<!DOCTYPE html>
 
             <div class="card">
                 <div class="card-title">Uptime</div>
-                <div class="card-value">{{.Uptime}}</div>
+                <div class="card-value" data-stat="uptime">{{.Uptime}}</div>
             </div>
 
             <div class="card">
                 <div class="card-title">Active Sessions</div>
-                <div class="card-value">{{.ActiveSessions}}</div>
+                <div class="card-value" data-stat="active_sessions">{{.ActiveSessions}}</div>
             </div>
 
             <div class="card">
@@ -265,19 +467,19 @@ const dashboardTemplate = `<!DOCTYPE html>
                 <div class="stat-grid">
                     <div class="stat">
                         <div class="stat-label">Total</div>
-                        <div class="stat-value">{{.TotalTokens}}</div>
+                        <div class="stat-value" data-stat="total_tokens">{{.TotalTokens}}</div>
                     </div>
                     <div class="stat">
                         <div class="stat-label">Requests</div>
-                        <div class="stat-value">{{.TotalRequests}}</div>
+                        <div class="stat-value" data-stat="total_requests">{{.TotalRequests}}</div>
                     </div>
                     <div class="stat">
                         <div class="stat-label">Input</div>
-                        <div class="stat-value">{{.InputTokens}}</div>
+                        <div class="stat-value" data-stat="input_tokens">{{.InputTokens}}</div>
                     </div>
                     <div class="stat">
                         <div class="stat-label">Output</div>
-                        <div class="stat-value">{{.OutputTokens}}</div>
+                        <div class="stat-value" data-stat="output_tokens">{{.OutputTokens}}</div>
                     </div>
                 </div>
             </div>
@@ -293,6 +495,22 @@ const dashboardTemplate = `<!DOCTYPE html>
                 </div>
             </div>
 
+            <div class="card full-width">
+                <div class="card-title">In Progress <span class="live-status" id="live-status">connecting</span></div>
+                <table class="live-table">
+                    <thead><tr><th>Session</th><th>From</th><th>Message</th><th>Running</th><th>Tools</th><th>Time</th></tr></thead>
+                    <tbody id="live-turns"></tbody>
+                </table>
+                <div class="live-empty" id="live-idle">Nothing running</div>
+            </div>
+
+            <div class="card full-width">
+                <div class="card-title">Live Events</div>
+                <div class="activity-list live-feed" id="live-feed">
+                    <div class="live-empty">Waiting for events…</div>
+                </div>
+            </div>
+
             {{if .RecentActivity}}
             <div class="card full-width">
                 <div class="card-title">Recent Activity</div>
@@ -306,12 +524,192 @@ const dashboardTemplate = `<!DOCTYPE html>
                 </div>
             </div>
             {{end}}
+
+            {{if .Feedback}}
+            <div class="card full-width">
+                <div class="card-title">Feedback</div>
+                <table class="feedback-table">
+                    <tr><th>Model</th><th>Profile</th><th>👍</th><th>👎</th><th>Positive</th></tr>
+                    {{range .Feedback}}
+                    <tr><td>{{.Model}}</td><td>{{.Profile}}</td><td>{{.Positive}}</td><td>{{.Negative}}</td><td>{{.Rate}}</td></tr>
+                    {{end}}
+                </table>
+            </div>
+            {{end}}
+
+            {{if .Webhooks}}
+            <div class="card full-width">
+                <div class="card-title">Webhooks</div>
+                <table class="feedback-table">
+                    <tr><th>Received</th><th>Mapping</th><th>Event</th><th>Status</th><th>Details</th></tr>
+                    {{range .Webhooks}}
+                    <tr title="{{.ID}}"><td>{{.ReceivedAt}}</td><td>{{.Mapping}}</td><td>{{.Event}}</td><td>{{.Status}}</td><td>{{.Detail}}</td></tr>
+                    {{end}}
+                </table>
+            </div>
+            {{end}}
+
+            {{if .KeysEnabled}}
+            <div class="card full-width">
+                <div class="card-title">API Keys</div>
+                {{if .APIKeys}}
+                <table class="feedback-table">
+                    <tr><th>Name</th><th>Key</th><th>Scopes</th><th>Models</th><th>Status</th><th>Expires</th><th>Last Used</th><th>Requests</th><th>Tokens</th><th></th></tr>
+                    {{range .APIKeys}}
+                    <tr title="{{.ID}}"><td>{{.Name}}</td><td>{{.Prefix}}…</td><td>{{.Scopes}}</td><td>{{.Models}}</td><td>{{.Status}}</td><td>{{.Expires}}</td><td>{{.LastUsed}}</td><td>{{.Requests}}</td><td>{{.Tokens}}</td>
+                        <td>{{if and (eq .Status "active") (not $.ReadOnly)}}<button class="revoke-btn" data-id="{{.ID}}">Revoke</button>{{end}}</td></tr>
+                    {{end}}
+                </table>
+                {{end}}
+                {{if not .ReadOnly}}
+                <form class="key-form" id="key-form">
+                    <input id="key-name" placeholder="Name" required>
+                    <input id="key-scopes" placeholder="Scopes (chat,hooks,config,admin)" required>
+                    <input id="key-models" placeholder="Models (optional)">
+                    <input id="key-rate" type="number" min="0" placeholder="Requests/min">
+                    <input id="key-expires" placeholder="Expires in (e.g. 90d)">
+                    <label><input id="key-server-tools" type="checkbox"> Server tools</label>
+                    <button type="submit">Create key</button>
+                </form>
+                <div class="key-token" id="key-token"></div>
+                {{end}}
+            </div>
+            {{end}}
         </div>
 
         <footer>
             FeelPulse • Fast AI Assistant Platform
         </footer>
     </div>
+
+    <script>
+        const csrfToken = '{{.CSRFToken}}';
+        const list = (id) => document.getElementById(id).value.split(',').map(s => s.trim()).filter(Boolean);
+
+        async function keysAPI(method, path, body) {
+            const resp = await fetch(path, {
+                method,
+                headers: { 'Content-Type': 'application/json', 'X-CSRF-Token': csrfToken },
+                body: body ? JSON.stringify(body) : undefined
+            });
+            const result = await resp.json();
+            if (!resp.ok) throw new Error(result.error || resp.statusText);
+            return result;
+        }
+
+        document.getElementById('key-form')?.addEventListener('submit', async (e) => {
+            e.preventDefault();
+            const out = document.getElementById('key-token');
+            try {
+                const result = await keysAPI('POST', '/api/keys', {
+                    name: document.getElementById('key-name').value,
+                    scopes: list('key-scopes'),
+                    models: list('key-models'),
+                    rate_limit: parseInt(document.getElementById('key-rate').value) || 0,
+                    server_tools: document.getElementById('key-server-tools').checked,
+                    expires_in: document.getElementById('key-expires').value.trim()
+                });
+                out.textContent = 'New key (shown once): ' + result.token;
+            } catch (err) {
+                out.textContent = '❌ ' + err.message;
+            }
+        });
+
+        // Live updates over server-sent events
+        const turns = new Map();
+        const agents = new Map();
+        const el = (tag, text, cls) => {
+            const e = document.createElement(tag);
+            if (text !== undefined) e.textContent = text;
+            if (cls) e.className = cls;
+            return e;
+        };
+        const elapsed = (since) => Math.max(0, Math.round((Date.now() - new Date(since)) / 1000)) + 's';
+
+        function renderTurns() {
+            const body = document.getElementById('live-turns');
+            body.replaceChildren();
+            for (const t of turns.values()) {
+                const tool = t.tool ? el('span', t.tool_summary ? t.tool + ': ' + t.tool_summary : t.tool, 'tool-badge') : el('span', 'thinking…');
+                const row = el('tr');
+                [el('td', t.session), el('td', t.from), el('td', t.preview)].forEach(c => row.append(c));
+                const toolCell = el('td'); toolCell.append(tool); row.append(toolCell);
+                row.append(el('td', String(t.tools)), el('td', elapsed(t.started_at)));
+                body.append(row);
+            }
+            for (const a of agents.values()) {
+                const row = el('tr');
+                const tool = el('td'); tool.append(el('span', a.tool || 'working…', a.tool ? 'tool-badge' : ''));
+                row.append(el('td', a.session), el('td', '🤖 ' + a.label), el('td', 'sub-agent ' + a.id), tool, el('td', ''), el('td', elapsed(a.started_at)));
+                body.append(row);
+            }
+            document.getElementById('live-idle').style.display = turns.size || agents.size ? 'none' : '';
+        }
+
+        function addFeed(meta, text, isError) {
+            const feed = document.getElementById('live-feed');
+            feed.querySelector('.live-empty')?.remove();
+            const item = el('div', undefined, 'activity-item' + (isError ? ' error' : ''));
+            item.append(el('div', new Date().toLocaleTimeString() + ' • ' + meta, 'activity-meta'), el('div', text, 'activity-preview'));
+            feed.prepend(item);
+            while (feed.children.length > 50) feed.lastChild.remove();
+        }
+
+        function setStats(stats) {
+            document.querySelectorAll('[data-stat]').forEach(e => {
+                if (stats[e.dataset.stat] !== undefined) e.textContent = stats[e.dataset.stat];
+            });
+        }
+
+        const source = new EventSource('/dashboard/events');
+        const on = (name, fn) => source.addEventListener(name, e => fn(JSON.parse(e.data)));
+        const status = document.getElementById('live-status');
+        source.onopen = () => { status.textContent = 'live'; status.classList.add('connected'); };
+        source.onerror = () => { status.textContent = 'disconnected'; status.classList.remove('connected'); };
+
+        on('snapshot', d => {
+            turns.clear(); agents.clear();
+            d.turns.forEach(t => turns.set(t.session, t));
+            d.subagents.forEach(a => agents.set(a.id, a));
+            setStats(d.stats);
+            renderTurns();
+        });
+        on('stats', setStats);
+        on('message.received', d => addFeed(d.channel + ' • ' + d.from, (d.command ? '⌨️ ' : '💬 ') + d.text));
+        on('turn.started', t => { turns.set(t.session, t); renderTurns(); });
+        on('tool.started', d => {
+            const t = turns.get(d.session);
+            if (t) { t.tool = d.tool; t.tool_summary = d.summary; t.tools++; }
+            for (const a of agents.values()) if (a.session === d.session) a.tool = d.tool;
+            renderTurns();
+        });
+        on('tool.called', d => {
+            const t = turns.get(d.session);
+            if (t) { t.tool = ''; t.tool_summary = ''; renderTurns(); }
+            if (d.error) addFeed(d.session + ' • ' + d.tool, '❌ ' + d.error, true);
+        });
+        on('reply.sent', d => {
+            turns.delete(d.session); renderTurns();
+            addFeed(d.session + ' • ' + (d.model || 'reply') + ' • ' + ((d.input_tokens || 0) + (d.output_tokens || 0)) + ' tokens', '🫀 ' + d.text);
+        });
+        on('turn.failed', d => { turns.delete(d.session); renderTurns(); addFeed(d.session, '❌ ' + d.error, true); });
+        on('subagent.started', a => { agents.set(a.id, a); renderTurns(); addFeed(a.session + ' • ' + a.id, '🤖 Sub-agent started: ' + a.label); });
+        on('subagent.completed', d => {
+            agents.delete(d.id); renderTurns();
+            addFeed(d.session + ' • ' + d.id, d.error ? '❌ Sub-agent ' + d.label + ' failed: ' + d.error : '✅ Sub-agent ' + d.label + ' done', !!d.error);
+        });
+        setInterval(renderTurns, 1000);
+
+        document.querySelectorAll('.revoke-btn').forEach(btn => btn.addEventListener('click', async () => {
+            if (!confirm('Revoke this key?')) return;
+            try {
+                await keysAPI('DELETE', '/api/keys/' + btn.dataset.id);
+                location.reload();
+            } catch (err) {
+                alert(err.message);
+            }
+        }));
+    </script>
 </body>
 </html>`
 
@@ -7,7 +7,9 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/FeelPulse/feelpulse/internal/apikey"
 	"github.com/FeelPulse/feelpulse/internal/config"
+	"github.com/FeelPulse/feelpulse/internal/webauth"
 )
 
 // ConfigPageData holds data for the config page template
@@ -48,11 +50,16 @@ type ConfigPageData struct {
 	Message  string
 	Warnings []string
 	IsError  bool
+
+	// Login session
+	ReadOnly  bool
+	CSRFToken string
 }
 
 // handleConfigPage serves the configuration editor page
 func (gw *Gateway) handleConfigPage(w http.ResponseWriter, r *http.Request) {
-	if !gw.checkAuth(w, r) {
+	r, ok := gw.authorize(w, r, apikey.ScopeConfig)
+	if !ok {
 		return
 	}
 
@@ -95,6 +102,10 @@ func (gw *Gateway) handleConfigPage(w http.ResponseWriter, r *http.Request) {
 		HooksEnabled:  cfg.Hooks.Enabled,
 		HooksHasToken: cfg.Hooks.Token != "",
 	}
+	if sess := webauth.FromContext(r.Context()); sess != nil {
+		data.ReadOnly = sess.ReadOnly()
+		data.CSRFToken = sess.CSRFToken
+	}
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	w.Write([]byte(generateConfigPageHTML(data)))
@@ -148,7 +159,8 @@ func (gw *Gateway) handleConfigSave(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if !gw.checkAuth(w, r) {
+	r, ok := gw.authorize(w, r, apikey.ScopeConfig)
+	if !ok {
 		return
 	}
 
@@ -163,6 +175,17 @@ func (gw *Gateway) handleConfigSave(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// hooks.token grants every scope, so setting it needs the admin scope
+	if key := apikey.FromContext(r.Context()); req.HooksToken != "" && key != nil && !key.HasScope(apikey.ScopeAdmin) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(ConfigSaveResponse{
+			OK:    false,
+			Error: "Forbidden: changing hooks.token requires the admin scope",
+		})
+		return
+	}
+
 	// Load existing config to preserve secrets and other settings
 	cfg, err := config.Load()
 	if err != nil {
@@ -636,12 +659,12 @@ const configPageTemplate = `<!DOCTYPE html>
                 <div class="form-group">
                     <label for="hooksToken">New Token (leave empty to keep current)</label>
                     <input type="text" id="hooksToken" name="hooksToken" placeholder="Enter new token to change">
-                    <div class="hint">Used for Authorization: Bearer &lt;token&gt;</div>
+                    <div class="hint">Deprecated: grants full access. Prefer scoped API keys (feelpulse keys create)</div>
                 </div>
             </div>
 
             <div class="actions">
-                <button type="submit" class="btn btn-primary" id="save-btn">
+                <button type="submit" class="btn btn-primary" id="save-btn"{{if .ReadOnly}} disabled title="Read-only login"{{end}}>
                     Save Configuration
                 </button>
             </div>
@@ -706,7 +729,7 @@ const configPageTemplate = `<!DOCTYPE html>
                     method: 'POST',
                     headers: {
                         'Content-Type': 'application/json',
-                        'Authorization': 'Bearer ' + (document.cookie.match(/token=([^;]+)/)?.[1] || '')
+                        'X-CSRF-Token': '{{.CSRFToken}}'
                     },
                     body: JSON.stringify(data)
                 });
@@ -135,6 +135,42 @@ func TestGenerateDashboardHTML(t *testing.T) {
 	}
 }
 
+func TestGenerateDashboardHTML_Feedback(t *testing.T) {
+	data := DashboardData{Status: "running", Version: "0.1.0"}
+	if containsStr(generateDashboardHTML(data), "feedback-table\"") {
+		t.Error("HTML should not contain feedback card without feedback")
+	}
+
+	data.Feedback = []FeedbackEntry{
+		{Model: "claude-sonnet-4", Profile: "friendly", Positive: 3, Negative: 1, Total: 4, Rate: "75%"},
+	}
+	html := generateDashboardHTML(data)
+
+	for _, want := range []string{"Feedback", "claude-sonnet-4", "friendly", "75%"} {
+		if !containsStr(html, want) {
+			t.Errorf("HTML should contain %q", want)
+		}
+	}
+}
+
+func TestGenerateDashboardHTML_Webhooks(t *testing.T) {
+	data := DashboardData{Status: "running", Version: "0.1.0"}
+	if containsStr(generateDashboardHTML(data), "Webhooks") {
+		t.Error("HTML should not contain webhooks card without deliveries")
+	}
+
+	data.Webhooks = []WebhookEntry{
+		{ID: "hook-1", ReceivedAt: "Oct 18 10:00:00", Mapping: "github", Event: "pull_request", Status: "delivered", Detail: "telegram:42"},
+	}
+	html := generateDashboardHTML(data)
+
+	for _, want := range []string{"Webhooks", "github", "pull_request", "delivered", "telegram:42"} {
+		if !containsStr(html, want) {
+			t.Errorf("HTML should contain %q", want)
+		}
+	}
+}
+
 func containsStr(s, substr string) bool {
 	for i := 0; i <= len(s)-len(substr); i++ {
 		if s[i:i+len(substr)] == substr {
@@ -4,34 +4,44 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"runtime"
 	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"syscall"
 	"time"
 
 	"github.com/FeelPulse/feelpulse/internal/agent"
+	"github.com/FeelPulse/feelpulse/internal/apikey"
 	"github.com/FeelPulse/feelpulse/internal/browser"
 	"github.com/FeelPulse/feelpulse/internal/channel"
 	"github.com/FeelPulse/feelpulse/internal/command"
 	"github.com/FeelPulse/feelpulse/internal/config"
 	"github.com/FeelPulse/feelpulse/internal/dailylog"
+	"github.com/FeelPulse/feelpulse/internal/events"
 	"github.com/FeelPulse/feelpulse/internal/heartbeat"
+	"github.com/FeelPulse/feelpulse/internal/inbox"
 	"github.com/FeelPulse/feelpulse/internal/logger"
 	"github.com/FeelPulse/feelpulse/internal/memory"
 	"github.com/FeelPulse/feelpulse/internal/metrics"
 	"github.com/FeelPulse/feelpulse/internal/ratelimit"
+	"github.com/FeelPulse/feelpulse/internal/scheduler"
 	"github.com/FeelPulse/feelpulse/internal/session"
 	"github.com/FeelPulse/feelpulse/internal/store"
+	"github.com/FeelPulse/feelpulse/internal/stt"
 	"github.com/FeelPulse/feelpulse/internal/subagent"
 	"github.com/FeelPulse/feelpulse/internal/tools"
+	"github.com/FeelPulse/feelpulse/internal/tts"
 	"github.com/FeelPulse/feelpulse/internal/usage"
 	"github.com/FeelPulse/feelpulse/internal/watcher"
+	"github.com/FeelPulse/feelpulse/internal/webauth"
+	"github.com/FeelPulse/feelpulse/internal/webhook"
 	"github.com/FeelPulse/feelpulse/pkg/types"
 )
 
@@ -55,6 +65,18 @@ type Gateway struct {
 	toolRegistry    *tools.Registry
 	subagentManager *subagent.Manager
 	pinManager      *pinManager
+	scheduler       *scheduler.Scheduler
+	accessManager   *accessManager
+	feedback        *feedbackRecorder
+	hooks           *hookManager
+	webhooks        *webhook.Dispatcher
+	events          *events.Bus
+	apiKeys         *apikey.Manager
+	keyLimiters     map[string]*ratelimit.Limiter // Per-key rate limiters, by key ID and limit
+	keyLimitersMu   sync.Mutex
+	logins          *webauth.Manager
+	loginLimiter    *ratelimit.Limiter // Login attempts per client address
+	live            *liveFeed          // Dashboard event stream
 	log             *logger.Logger
 	metrics        *metrics.Collector
 	startTime      time.Time
@@ -178,9 +200,14 @@ func New(cfg *config.Config) *Gateway {
 		toolRegistry:  toolRegistry,
 		log:           log,
 		metrics:       metricsCollector,
+		events:        events.New(log),
+		live:          newLiveFeed(),
 		startTime:     time.Now(),
 		shutdownCh:    make(chan struct{}),
 	}
+	gw.subscribeEvents()
+	gw.initializeAPIKeys()
+	gw.initializeDashboardLogin()
 
 	// Initialize sub-agent manager (callback set later when telegram is ready)
 	gw.subagentManager = subagent.NewManager(nil)
@@ -195,9 +222,15 @@ func (gw *Gateway) setupRoutes() {
 	gw.mux.HandleFunc("/health", gw.handleHealth)
 	gw.mux.HandleFunc("/hooks/", gw.handleHook)
 	gw.mux.HandleFunc("/v1/chat/completions", gw.handleOpenAIChatCompletion)
+	gw.mux.HandleFunc("/v1/models", gw.handleOpenAIModels)
+	gw.mux.HandleFunc("/v1/messages", gw.handleMessages)
 	gw.mux.HandleFunc("/dashboard", gw.handleDashboard)
 	gw.mux.HandleFunc("/dashboard/config", gw.handleConfigPage)
 	gw.mux.HandleFunc("/api/config", gw.handleConfigSave)
+	gw.setupSessionRoutes()
+	gw.setupAPIKeyRoutes()
+	gw.setupLoginRoutes()
+	gw.mux.HandleFunc("GET /dashboard/events", gw.handleDashboardEvents)
 
 	// Metrics endpoint
 	if gw.cfg.Metrics.Enabled {
@@ -213,6 +246,9 @@ func (gw *Gateway) Start() error {
 	ctx, cancel := context.WithCancel(context.Background())
 	gw.cancelCtx = cancel
 
+	// Initialize outbound webhooks first, so every subsystem can publish events
+	gw.initializeWebhooks()
+
 	// Initialize agent and telegram
 	gw.initializeAgent(ctx)
 	gw.initializeTelegram(ctx)
@@ -223,12 +259,18 @@ func (gw *Gateway) Start() error {
 	// Initialize sub-agent system (after agent and telegram are ready)
 	gw.initializeSubAgents()
 
+	// Initialize reminders
+	gw.initializeScheduler()
+
 	// Wire up command handler dependencies
 	gw.wireCommandHandler()
 
 	// Initialize heartbeat service
 	gw.initializeHeartbeat()
 
+	// Initialize webhook mappings
+	gw.initializeHooks()
+
 	// Start config hot reload watcher
 	gw.startConfigWatcher(ctx)
 
@@ -293,6 +335,15 @@ func (gw *Gateway) gracefulShutdown(cancel context.CancelFunc) {
 	if gw.heartbeat != nil {
 		gw.heartbeat.Stop()
 	}
+	if gw.scheduler != nil {
+		gw.scheduler.Stop()
+	}
+
+	// Let subscribers handle the events already published
+	gw.events.Close()
+	if gw.webhooks != nil {
+		gw.webhooks.Stop()
+	}
 
 	// Save all sessions to SQLite
 	if gw.db != nil {
@@ -372,6 +423,7 @@ func (gw *Gateway) initializeAgent(ctx context.Context) {
 	// Wire up tool registry for agentic tool calling
 	if gw.toolRegistry != nil {
 		router.SetToolRegistry(gw.toolRegistry)
+		router.SetToolObserver(gw.publishToolCalled)
 		toolCount := len(gw.toolRegistry.List())
 		if toolCount > 0 {
 			gw.log.Info("🔧 Tool registry attached with %d tools", toolCount)
@@ -409,9 +461,47 @@ func (gw *Gateway) initializeTelegram(ctx context.Context) {
 	}
 
 	telegram := channel.NewTelegramBot(gw.cfg.Channels.Telegram.BotToken, gw.log)
+	if tgCfg := gw.cfg.Channels.Telegram; tgCfg.APIURL != "" || tgCfg.FileURL != "" || tgCfg.LocalMode {
+		telegram.SetAPIServer(tgCfg.APIURL, tgCfg.FileURL, tgCfg.LocalMode)
+		gw.log.Info("📡 Using Telegram Bot API server: %s (local mode: %v)", tgCfg.APIURL, tgCfg.LocalMode)
+	}
 	telegram.SetHandler(gw.handleMessage)
 	telegram.SetCallbackHandler(gw.handleTelegramCallback)
 	telegram.SetAllowedUsers(gw.cfg.Channels.Telegram.AllowedUsers)
+	telegram.SetGroupPolicy(gw.telegramGroupPolicy(gw.cfg))
+	telegram.SetMetrics(gw.metrics)
+	telegram.SetStreaming(gw.cfg.Channels.Telegram.Streaming, time.Duration(gw.cfg.Channels.Telegram.StreamIntervalMs)*time.Millisecond)
+
+	speaker := gw.newSpeaker(gw.cfg)
+	if speaker.Command != "" {
+		telegram.SetSpeechSynthesizer(speaker)
+	}
+
+	if inboxCfg := gw.cfg.Workspace.Inbox; inboxCfg.Enabled {
+		maxSize := int64(inboxCfg.MaxSizeMB) * 1024 * 1024
+		telegram.SetDocumentStore(inbox.New(gw.memory.Path(), maxSize, inboxCfg.AllowedTypes))
+	}
+
+	gw.initializeAccess(telegram)
+	gw.initializeFeedback(telegram)
+	gw.initializeLocation(telegram)
+
+	if gw.db != nil {
+		if err := gw.db.EnsureUpdatesTables(); err != nil {
+			gw.log.Warn("Failed to create update tracking tables: %v", err)
+		} else {
+			telegram.SetUpdateStore(&updateStoreAdapter{db: gw.db, channel: "telegram"})
+		}
+	}
+
+	if gw.cfg.STT.Enabled {
+		transcriber := stt.New(gw.cfg.STT.Command, gw.cfg.STT.FFmpeg, gw.cfg.STT.Language, time.Duration(gw.cfg.STT.TimeoutSeconds)*time.Second)
+		if !transcriber.Available() {
+			gw.log.Warn("⚠️ STT command not available: %q (voice messages will fail)", gw.cfg.STT.Command)
+		}
+		telegram.SetTranscriber(transcriber, gw.cfg.STT.MaxDurationSeconds)
+		gw.log.Info("🎙️ Voice transcription enabled")
+	}
 
 	if len(gw.cfg.Channels.Telegram.AllowedUsers) > 0 {
 		gw.log.Info("🔒 Telegram allowlist: %v", gw.cfg.Channels.Telegram.AllowedUsers)
@@ -429,6 +519,49 @@ func (gw *Gateway) initializeTelegram(ctx context.Context) {
 	gw.log.Info("📱 Telegram bot started")
 }
 
+// updateStoreAdapter wraps SQLiteStore to implement channel.UpdateStore
+type updateStoreAdapter struct {
+	db      *store.SQLiteStore
+	channel string
+}
+
+func (a *updateStoreAdapter) LoadOffset() (int64, error) {
+	return a.db.LoadUpdateOffset(a.channel)
+}
+
+func (a *updateStoreAdapter) SaveOffset(offset int64) error {
+	return a.db.SaveUpdateOffset(a.channel, offset)
+}
+
+func (a *updateStoreAdapter) MarkProcessed(chatID, messageID int64) (bool, error) {
+	return a.db.MarkUpdateProcessed(a.channel, chatID, messageID)
+}
+
+func (a *updateStoreAdapter) CleanProcessed(maxAge time.Duration) (int64, error) {
+	return a.db.CleanProcessedUpdates(maxAge)
+}
+
+// telegramGroupPolicy converts the group configuration into a channel policy
+func (gw *Gateway) telegramGroupPolicy(cfg *config.Config) channel.GroupPolicy {
+	groups := cfg.Channels.Telegram.Groups
+	policy := channel.GroupPolicy{
+		RequireMention: groups.RequireMention,
+		TopicSessions:  groups.TopicSessions,
+		Chats:          make(map[int64]bool),
+	}
+	for key, chat := range groups.Chats {
+		chatID, err := strconv.ParseInt(key, 10, 64)
+		if err != nil {
+			gw.log.Warn("⚠️ Ignoring Telegram group override '%s': not a chat ID", key)
+			continue
+		}
+		if chat.RequireMention != nil {
+			policy.Chats[chatID] = *chat.RequireMention
+		}
+	}
+	return policy
+}
+
 // initializeHeartbeat sets up the heartbeat service
 func (gw *Gateway) initializeHeartbeat() {
 	if !gw.cfg.Heartbeat.Enabled {
@@ -449,16 +582,10 @@ func (gw *Gateway) initializeHeartbeat() {
 
 	// Set callback to send messages via Telegram
 	gw.heartbeat.SetCallback(func(ch string, userID int64, message string) {
-		gw.mu.RLock()
-		telegram := gw.telegram
-		gw.mu.RUnlock()
-
-		if telegram != nil && ch == "telegram" {
-			if err := telegram.SendMessage(userID, message, true); err != nil {
-				gw.log.Warn("Failed to send heartbeat to %d: %v", userID, err)
-			} else {
-				gw.log.Debug("💓 Sent heartbeat to user %d", userID)
-			}
+		if err := gw.notify(ch, strconv.FormatInt(userID, 10), message); err != nil {
+			gw.log.Warn("Failed to send heartbeat to %d: %v", userID, err)
+		} else {
+			gw.log.Debug("💓 Sent heartbeat to user %d", userID)
 		}
 	})
 
@@ -543,6 +670,29 @@ func (gw *Gateway) wireCommandHandler() {
 
 	// Wire up admin provider for /admin commands
 	gw.commands.SetAdmin(gw)
+	if gw.accessManager != nil {
+		gw.commands.SetAccess(gw.accessManager)
+	}
+
+	// Wire up feedback stats for /feedback command
+	if gw.feedback != nil {
+		gw.commands.SetFeedback(gw.feedback)
+	}
+
+	// Wire up scheduler for /remind commands
+	if gw.scheduler != nil {
+		gw.commands.SetScheduler(gw.scheduler)
+	}
+
+	// Wire up outbound webhooks for /admin webhooks
+	if gw.webhooks != nil {
+		gw.commands.SetWebhooks(gw)
+	}
+
+	// Wire up dashboard login links for /admin login
+	if gw.logins != nil {
+		gw.commands.SetDashboard(gw)
+	}
 
 	// Wire up sub-agent provider for /agents command
 	if gw.subagentManager != nil {
@@ -650,6 +800,14 @@ func (gw *Gateway) handleConfigReload(ctx context.Context) {
 		gw.log.Warn("Failed to reload workspace files: %v", err)
 	}
 
+	// Reload webhook mappings and subscriptions
+	if gw.hooks != nil {
+		gw.hooks.setMappings(newCfg.Hooks.Mappings)
+	}
+	if gw.webhooks != nil {
+		gw.applyWebhookConfig(newCfg)
+	}
+
 	// Check if agent needs reinitialization
 	agentChanged := oldCfg.Agent.APIKey != newCfg.Agent.APIKey ||
 		oldCfg.Agent.AuthToken != newCfg.Agent.AuthToken ||
@@ -683,6 +841,8 @@ func (gw *Gateway) handleConfigReload(ctx context.Context) {
 		gw.mu.RUnlock()
 		if telegram != nil {
 			telegram.SetAllowedUsers(newCfg.Channels.Telegram.AllowedUsers)
+			telegram.SetGroupPolicy(gw.telegramGroupPolicy(newCfg))
+			telegram.SetStreaming(newCfg.Channels.Telegram.Streaming, time.Duration(newCfg.Channels.Telegram.StreamIntervalMs)*time.Millisecond)
 			if len(newCfg.Channels.Telegram.AllowedUsers) > 0 {
 				gw.log.Info("🔒 Telegram allowlist updated: %v", newCfg.Channels.Telegram.AllowedUsers)
 			}
@@ -702,14 +862,17 @@ func (gw *Gateway) handleConfigReload(ctx context.Context) {
 			gw.log.Info("⏱️  Rate limiting disabled")
 		}
 	}
+
+	gw.publish(events.ConfigReloaded{AgentChanged: agentChanged, TelegramChanged: telegramChanged})
 }
 
 // messageProcessingContext holds state for message processing
 type messageProcessingContext struct {
-	userID    string
-	reqLog    *logger.ContextLogger
-	router    *agent.Router
-	history   []types.Message
+	userID  string
+	reqLog  *logger.ContextLogger
+	router  *agent.Router
+	history []types.Message
+	pins    string // Pinned notes, added to the system prompt
 }
 
 // prepareMessageProcessing handles common setup for message processing.
@@ -733,17 +896,15 @@ func (gw *Gateway) prepareMessageProcessing(msg *types.Message) (*messageProcess
 	// Update last message timestamp (atomic, no lock needed)
 	gw.lastMessageAt.Store(time.Now().UnixNano())
 
-	// Track message in metrics
-	gw.metrics.IncrementMessages(msg.Channel)
-	gw.metrics.SetActiveSessions(gw.sessions.Count())
-
 	userID := gw.getUserID(msg)
 	reqLog := gw.log.WithComponent("message").WithRequestID(userID)
 
 	reqLog.Info("Processing message from %s", msg.From)
 
-	// Register user for heartbeat (if enabled)
-	if gw.heartbeat != nil {
+	gw.publish(events.MessageAccepted{Channel: msg.Channel, UserID: userID, From: msg.From})
+
+	// Register user for heartbeat (if enabled); group sessions are not DMs
+	if _, group := msg.Metadata["session_id"]; gw.heartbeat != nil && !group {
 		if uid, ok := gw.getUserIDInt64(msg); ok {
 			gw.heartbeat.RegisterUser(msg.Channel, uid)
 		}
@@ -785,17 +946,49 @@ func (gw *Gateway) prepareMessageProcessing(msg *types.Message) (*messageProcess
 	// Get session
 	sess := gw.sessions.GetOrCreate(msg.Channel, userID)
 
-	// Add incoming message to session history (and persist)
-	gw.sessions.AddMessageAndPersist(msg.Channel, userID, *msg)
+	// Remember a shared location for the user_location tool
+	if loc, ok := locationFromMetadata(msg); ok {
+		sess.SetLocation(loc)
+	}
 
-	// Log to daily file
-	if gw.dailylog != nil {
-		if err := gw.dailylog.Log(*msg); err != nil {
-			reqLog.Warn("Failed to write daily log: %v", err)
+	// An edited message replaces the last turn, but only if it is the latest one
+	if edited, _ := msg.Metadata["edited"].(bool); edited {
+		if msg.ID == "" || !sess.RemoveLastTurn(func(m types.Message) bool { return m.ID == msg.ID }) {
+			reqLog.Debug("Ignoring edit of an earlier message")
+			gw.activeRequests.Done()
+			return nil, &types.Message{Channel: msg.Channel, IsBot: true}
 		}
+		reqLog.Info("✏️ Message edited, regenerating reply")
 	}
 
+	// Add incoming message to session history (and persist)
+	gw.sessions.AddMessageAndPersist(msg.Channel, userID, *msg)
+
+	// The daily log, webhooks and live feed observe this
+	gw.publish(events.MessageReceived{
+		Channel:   msg.Channel,
+		UserID:    userID,
+		Session:   session.SessionKey(msg.Channel, userID),
+		MessageID: msg.ID,
+		From:      msg.From,
+		Text:      msg.Text,
+		Message:   *msg,
+	})
+
 	// Get conversation history for agent
+	history := gw.contextHistory(msg.Channel, userID, sess, compactor, reqLog)
+
+	return &messageProcessingContext{
+		userID:  userID,
+		reqLog:  reqLog,
+		router:  router,
+		history: history,
+		pins:    gw.sessionPins(session.SessionKey(msg.Channel, userID)),
+	}, nil
+}
+
+// contextHistory returns a session's history for the agent, compacted if needed
+func (gw *Gateway) contextHistory(channel, userID string, sess *session.Session, compactor *session.Compactor, reqLog *logger.ContextLogger) []types.Message {
 	history := sess.GetAllMessages()
 
 	// Compact history if needed (summarize old messages)
@@ -809,7 +1002,7 @@ func (gw *Gateway) prepareMessageProcessing(msg *types.Message) (*messageProcess
 			history = compacted
 			// Track compaction
 			if gw.usage != nil {
-				gw.usage.RecordCompaction(msg.Channel, userID)
+				gw.usage.RecordCompaction(channel, userID)
 			}
 		}
 	}
@@ -817,37 +1010,44 @@ func (gw *Gateway) prepareMessageProcessing(msg *types.Message) (*messageProcess
 	// Track context window usage
 	if gw.usage != nil {
 		contextTokens := session.EstimateHistoryTokens(history)
-		gw.usage.UpdateContextWindow(msg.Channel, userID, contextTokens, maxContextTokens)
+		gw.usage.UpdateContextWindow(channel, userID, contextTokens, maxContextTokens)
 	}
 
-	return &messageProcessingContext{
-		userID:  userID,
-		reqLog:  reqLog,
-		router:  router,
-		history: history,
-	}, nil
+	return history
+}
+
+// sessionPins returns the session's pinned notes for the system prompt
+func (gw *Gateway) sessionPins(sessionKey string) string {
+	if gw.pinManager == nil {
+		return ""
+	}
+	return strings.TrimSpace(gw.pinManager.GetPins(sessionKey))
 }
 
 // finalizeMessageProcessing handles post-processing after agent response
 func (gw *Gateway) finalizeMessageProcessing(msg *types.Message, ctx *messageProcessingContext, reply *types.Message) {
-	// Track token usage in metrics
-	if reply.Metadata != nil {
-		if input, ok := reply.Metadata["input_tokens"].(int); ok {
-			if output, ok := reply.Metadata["output_tokens"].(int); ok {
-				gw.metrics.AddTokens(input, output)
-			}
-		}
+	// Add bot reply to session history (and persist); the ID lets
+	// reactions refer back to it
+	if reply.ID == "" {
+		reply.ID = newReplyID()
 	}
-
-	// Add bot reply to session history (and persist)
 	gw.sessions.AddMessageAndPersist(msg.Channel, ctx.userID, *reply)
 
-	// Log bot reply to daily file
-	if gw.dailylog != nil {
-		if err := gw.dailylog.Log(*reply); err != nil {
-			ctx.reqLog.Warn("Failed to write daily log: %v", err)
-		}
+	// Metrics, usage, daily log and webhooks observe this
+	event := events.ReplySent{
+		Channel: msg.Channel,
+		UserID:  ctx.userID,
+		Session: session.SessionKey(msg.Channel, ctx.userID),
+		ReplyID: reply.ID,
+		Text:    reply.Text,
+		Reply:   *reply,
 	}
+	if reply.Metadata != nil {
+		event.Model, _ = reply.Metadata["model"].(string)
+		event.InputTokens, _ = reply.Metadata["input_tokens"].(int)
+		event.OutputTokens, _ = reply.Metadata["output_tokens"].(int)
+	}
+	gw.publish(event)
 }
 
 // handleMessage processes incoming messages from channels
@@ -858,10 +1058,14 @@ func (gw *Gateway) handleMessage(msg *types.Message) (reply *types.Message, err
 	}
 	defer gw.activeRequests.Done() // single Done, always runs
 
+	sessionKey := session.SessionKey(msg.Channel, ctx.userID)
+	gw.publish(events.TurnStarted{Channel: msg.Channel, UserID: ctx.userID, Session: sessionKey, From: msg.From, Text: msg.Text})
+
 	// Panic recovery - ensure we don't crash from unexpected panics
 	defer func() {
 		if r := recover(); r != nil {
 			ctx.reqLog.Error("panic in handleMessage: %v", r)
+			gw.publishTurnFailed(msg, ctx, fmt.Errorf("panic: %v", r))
 			reply = &types.Message{
 				Text:    "❌ An unexpected error occurred. Please try again.",
 				Channel: msg.Channel,
@@ -871,16 +1075,28 @@ func (gw *Gateway) handleMessage(msg *types.Message) (reply *types.Message, err
 		}
 	}()
 
-	// Extract immediate sender from message metadata (set by channel layer)
-	var onIterationText func(string)
+	// Extract real-time callbacks from message metadata (set by channel layer)
+	opts := agent.ProcessOptions{System: ctx.pins}
 	if sender, ok := msg.Metadata["immediate_sender"].(func(string)); ok {
-		onIterationText = sender
+		opts.OnIterationText = sender
+	}
+	if delta, ok := msg.Metadata["stream_delta"].(func(string)); ok {
+		opts.OnText = delta
+	}
+	// The live dashboard shows which tool each turn is running
+	status, _ := msg.Metadata["tool_status"].(func(string, string))
+	opts.OnToolCall = func(name, summary string) {
+		gw.publish(events.ToolStarted{Session: sessionKey, Tool: name, Summary: summary})
+		if status != nil {
+			status(name, summary)
+		}
 	}
 
 	// Route to agent with full history
-	reply, err = ctx.router.ProcessWithHistoryStream(ctx.history, nil, onIterationText)
+	reply, err = ctx.router.ProcessWithOptions(ctx.history, opts)
 	if err != nil {
 		ctx.reqLog.Error("Agent error: %v", err)
+		gw.publishTurnFailed(msg, ctx, err)
 		return &types.Message{
 			Text:    "❌ Sorry, I encountered an error processing your message.",
 			Channel: msg.Channel,
@@ -889,12 +1105,41 @@ func (gw *Gateway) handleMessage(msg *types.Message) (reply *types.Message, err
 	}
 
 	gw.finalizeMessageProcessing(msg, ctx, reply)
+
+	// Ask the channel to speak the reply if TTS is on for this session
+	if gw.ttsEnabled(msg.Channel, ctx.userID) {
+		reply.Metadata["tts"] = true
+	}
+
 	return reply, nil
 }
 
-// getUserID extracts the user ID from a message
+// ttsEnabled reports whether replies in a session should also be sent as
+// voice notes: the session's /tts preference, or the global default
+func (gw *Gateway) ttsEnabled(channel, userID string) bool {
+	if pref := gw.sessions.GetOrCreate(channel, userID).GetTTS(); pref != nil {
+		return *pref
+	}
+	return gw.cfg.TTS.Enabled
+}
+
+// newSpeaker creates a TTS speaker from config (auto-detecting the command)
+func (gw *Gateway) newSpeaker(cfg *config.Config) *tts.Speaker {
+	speaker := tts.New(cfg.TTS.Command)
+	speaker.Voice = cfg.TTS.Voice
+	speaker.Speed = cfg.TTS.Speed
+	speaker.MaxLength = cfg.TTS.MaxLength
+	speaker.FFmpeg = cfg.TTS.FFmpeg
+	return speaker
+}
+
+// getUserID extracts the user ID from a message.
+// A session_id (set for group chats) takes precedence so members share a session.
 func (gw *Gateway) getUserID(msg *types.Message) string {
 	if msg.Metadata != nil {
+		if sessionID, ok := msg.Metadata["session_id"].(string); ok && sessionID != "" {
+			return sessionID
+		}
 		if userID, ok := msg.Metadata["user_id"]; ok {
 			switch v := userID.(type) {
 			case string:
@@ -1012,58 +1257,95 @@ func formatDuration(d time.Duration) string {
 	return fmt.Sprintf("%dm", minutes)
 }
 
-// checkAuth verifies the Authorization header against the configured hooks token.
-// Returns true if authorized (or no auth required). Writes 401 and returns false if unauthorized.
-func (gw *Gateway) checkAuth(w http.ResponseWriter, r *http.Request) bool {
-	if gw.cfg.Hooks.Token == "" {
-		return true // no auth configured
+func (gw *Gateway) handleHook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
 	}
 
-	// Try Authorization header first (Bearer token)
-	authHeader := r.Header.Get("Authorization")
-	if authHeader == "Bearer "+gw.cfg.Hooks.Token {
-		return true
+	if !gw.cfg.Hooks.Enabled {
+		http.NotFound(w, r)
+		return
 	}
 
-	// Try query parameter (e.g., ?token=xxx for browser access)
-	queryToken := r.URL.Query().Get("token")
-	if queryToken == gw.cfg.Hooks.Token {
-		return true
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxHookBodySize))
+	if err != nil {
+		http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+		return
 	}
 
-	http.Error(w, "Unauthorized", http.StatusUnauthorized)
-	return false
-}
-
-func (gw *Gateway) handleHook(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
+	var route *hookRoute
+	if gw.hooks != nil {
+		route = gw.hooks.route(r.URL.Path)
 	}
 
-	if !gw.checkAuth(w, r) {
+	// Mappings with a secret verify the sender instead of the hooks token
+	if route == nil || route.mapping.Verify == "" {
+		if _, ok := gw.authorize(w, r, apikey.ScopeHooks); !ok {
+			return
+		}
+	} else if err := verifyHookSignature(route.mapping, r.Header, body); err != nil {
+		gw.log.Warn("🔒 Hook %s rejected: %v", route.name, err)
+		gw.hooks.record(&store.HookDeliveryData{
+			ID:         newHookDeliveryID(),
+			Mapping:    route.name,
+			Path:       r.URL.Path,
+			Event:      hookEvent(r.Header),
+			Status:     hookRejected,
+			Error:      err.Error(),
+			ReceivedAt: time.Now(),
+			FinishedAt: time.Now(),
+		})
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
 
-	var body map[string]any
-	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+	var payload any
+	if err := json.Unmarshal(body, &payload); err != nil {
 		http.Error(w, "Invalid JSON", http.StatusBadRequest)
 		return
 	}
 
 	gw.log.Info("📨 Hook received: %s", r.URL.Path)
 
-	// TODO: Route to agent based on hook mappings
-
 	w.Header().Set("Content-Type", "application/json")
+
+	// Without a mapping, or for GitHub's setup ping, there is nothing to run
+	if route == nil || hookEvent(r.Header) == "ping" {
+		json.NewEncoder(w).Encode(map[string]any{
+			"ok": true,
+		})
+		return
+	}
+
+	delivery := &store.HookDeliveryData{
+		ID:         newHookDeliveryID(),
+		Mapping:    route.name,
+		Path:       r.URL.Path,
+		Event:      hookEvent(r.Header),
+		Status:     hookAccepted,
+		Target:     route.target(),
+		ReceivedAt: time.Now(),
+	}
+	gw.hooks.record(delivery)
+
+	data := newHookData(route, r, payload)
+	gw.activeRequests.Add(1)
+	go func() {
+		defer gw.activeRequests.Done()
+		gw.runHook(route, delivery, data)
+	}()
+
+	w.WriteHeader(http.StatusAccepted)
 	json.NewEncoder(w).Encode(map[string]any{
 		"ok": true,
+		"id": delivery.ID,
 	})
 }
 
 // handleMetrics returns Prometheus-compatible metrics
 func (gw *Gateway) handleMetrics(w http.ResponseWriter, r *http.Request) {
-	if !gw.checkAuth(w, r) {
+	if _, ok := gw.authorize(w, r, apikey.ScopeAdmin); !ok {
 		return
 	}
 
@@ -0,0 +1,311 @@
+package gateway
+
+import (
+	"crypto/rand"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/FeelPulse/feelpulse/internal/channel"
+	"github.com/FeelPulse/feelpulse/internal/command"
+	"github.com/FeelPulse/feelpulse/internal/logger"
+	"github.com/FeelPulse/feelpulse/internal/store"
+)
+
+// inviteTTL is how long an invite code stays valid
+const inviteTTL = 7 * 24 * time.Hour
+
+// accessManager implements channel.AccessControl and command.AccessProvider
+// using the SQLite users table. Users are keyed by Telegram user ID.
+type accessManager struct {
+	db      *store.SQLiteStore
+	log     *logger.Logger
+	adminID func() int64 // Configured admin Telegram ID (0 = none)
+	send    func(chatID int64, text string, keyboard *channel.InlineKeyboard) error
+}
+
+func newAccessManager(db *store.SQLiteStore, log *logger.Logger) (*accessManager, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database not available")
+	}
+	if err := db.EnsureUsersTables(); err != nil {
+		return nil, fmt.Errorf("failed to create users tables: %w", err)
+	}
+	return &accessManager{db: db, log: log}, nil
+}
+
+// initializeAccess sets up access requests for the Telegram bot
+func (gw *Gateway) initializeAccess(telegram *channel.TelegramBot) {
+	if gw.db == nil {
+		return
+	}
+
+	am, err := newAccessManager(gw.db, gw.log)
+	if err != nil {
+		gw.log.Warn("Failed to initialize access manager: %v", err)
+		return
+	}
+	am.adminID = func() int64 { return gw.cfg.Admin.TelegramID }
+	am.send = func(chatID int64, text string, keyboard *channel.InlineKeyboard) error {
+		if keyboard != nil {
+			return telegram.SendMessageWithKeyboard(chatID, text, *keyboard, true)
+		}
+		return telegram.SendMessage(chatID, text, true)
+	}
+
+	gw.accessManager = am
+	telegram.SetAccessControl(am)
+}
+
+// IsApproved reports whether a user was granted access at runtime
+func (am *accessManager) IsApproved(userID int64) bool {
+	if id := am.adminID(); id != 0 && userID == id {
+		return true
+	}
+	u, err := am.db.LoadUser("telegram", userID)
+	if err != nil {
+		am.log.Warn("Failed to load user %d: %v", userID, err)
+		return false
+	}
+	return u != nil && u.Status == "approved"
+}
+
+// IsAdmin reports whether a user is the configured admin or was promoted
+func (am *accessManager) IsAdmin(userID int64) bool {
+	if id := am.adminID(); id != 0 && userID == id {
+		return true
+	}
+	u, err := am.db.LoadUser("telegram", userID)
+	return err == nil && u != nil && u.Status == "approved" && u.Role == "admin"
+}
+
+// RequestAccess records a pending request and asks the admins to decide,
+// or grants access straight away for a valid invite code
+func (am *accessManager) RequestAccess(user *channel.TelegramUser, code string) string {
+	existing, err := am.db.LoadUser("telegram", user.ID)
+	if err != nil {
+		am.log.Warn("Failed to load user %d: %v", user.ID, err)
+		return "❌ Something went wrong. Please try again later."
+	}
+
+	if code != "" {
+		return am.redeemInvite(user, existing, code)
+	}
+
+	if existing != nil {
+		switch existing.Status {
+		case "approved":
+			return "✅ You already have access."
+		case "pending":
+			return "⏳ Your request is waiting for approval."
+		default:
+			return "⛔ Your access request was declined."
+		}
+	}
+
+	u := newUserData(user, "pending", "user")
+	if err := am.db.SaveUser(u); err != nil {
+		am.log.Warn("Failed to save access request: %v", err)
+		return "❌ Something went wrong. Please try again later."
+	}
+	am.log.Info("🙋 Access requested by %s (%d)", user.Username, user.ID)
+
+	keyboard := channel.BuildInlineKeyboard([]channel.InlineButton{
+		{Text: "✅ Approve", CallbackData: fmt.Sprintf("access:approve:%d", user.ID)},
+		{Text: "❌ Deny", CallbackData: fmt.Sprintf("access:deny:%d", user.ID)},
+	})
+	if n := am.notifyAdmins(fmt.Sprintf("🙋 *Access request*\n\n%s (`%d`) wants to use the bot.", displayName(u), user.ID), &keyboard); n == 0 {
+		am.log.Warn("⚠️ No admin to notify of access request from %d (set admin.telegramId); use /admin users approve %d", user.ID, user.ID)
+	}
+
+	return "📨 Access requested. You'll get a message once an admin approves it."
+}
+
+// redeemInvite grants access for a valid invite code
+func (am *accessManager) redeemInvite(user *channel.TelegramUser, existing *store.UserData, code string) string {
+	if existing != nil && existing.Status == "approved" {
+		return "✅ You already have access."
+	}
+
+	inv, err := am.db.RedeemInvite(code, user.ID)
+	if err != nil {
+		am.log.Warn("Failed to redeem invite: %v", err)
+		return "❌ Something went wrong. Please try again later."
+	}
+	if inv == nil {
+		return "❌ This invite code is invalid or has expired."
+	}
+
+	u := newUserData(user, "approved", inv.Role)
+	if existing != nil {
+		u.CreatedAt = existing.CreatedAt
+	}
+	if err := am.db.SaveUser(u); err != nil {
+		am.log.Warn("Failed to save user: %v", err)
+		return "❌ Something went wrong. Please try again later."
+	}
+
+	am.log.Info("🎟 %s (%d) joined with an invite", user.Username, user.ID)
+	am.notifyAdmins(fmt.Sprintf("🎟 %s (`%d`) joined with an invite.", displayName(u), user.ID), nil)
+	return "✅ Welcome! You now have access. Send a message to get started."
+}
+
+// ListUsers returns all users who requested or were granted access
+func (am *accessManager) ListUsers() ([]command.UserInfo, error) {
+	users, err := am.db.ListUsers("telegram")
+	if err != nil {
+		return nil, err
+	}
+	result := make([]command.UserInfo, len(users))
+	for i, u := range users {
+		result[i] = userInfo(u)
+	}
+	return result, nil
+}
+
+// Approve grants a user access and lets them know
+func (am *accessManager) Approve(userID int64) (*command.UserInfo, error) {
+	u, err := am.update(userID, func(u *store.UserData) { u.Status = "approved" })
+	if err != nil {
+		return nil, err
+	}
+	if am.send != nil {
+		if err := am.send(userID, "✅ Your access request was approved. Send a message to get started.", nil); err != nil {
+			am.log.Warn("Failed to notify user %d: %v", userID, err)
+		}
+	}
+	return u, nil
+}
+
+// Deny declines a pending request
+func (am *accessManager) Deny(userID int64) (*command.UserInfo, error) {
+	return am.update(userID, func(u *store.UserData) { u.Status = "denied" })
+}
+
+// Revoke removes a user's access (and admin role)
+func (am *accessManager) Revoke(userID int64) (*command.UserInfo, error) {
+	return am.update(userID, func(u *store.UserData) {
+		u.Status = "revoked"
+		u.Role = "user"
+	})
+}
+
+// Promote makes a user an admin, approving them if needed
+func (am *accessManager) Promote(userID int64) (*command.UserInfo, error) {
+	return am.update(userID, func(u *store.UserData) {
+		u.Status = "approved"
+		u.Role = "admin"
+	})
+}
+
+// CreateInvite creates a single-use invite code
+func (am *accessManager) CreateInvite(createdBy int64) (string, error) {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate invite code: %w", err)
+	}
+	code := fmt.Sprintf("%x", b)
+
+	now := time.Now()
+	err := am.db.SaveInvite(&store.InviteData{
+		Code:      code,
+		Role:      "user",
+		CreatedBy: createdBy,
+		ExpiresAt: now.Add(inviteTTL),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return "", err
+	}
+	am.log.Debug("🎟 Invite created by %d", createdBy)
+	return code, nil
+}
+
+// update applies a change to a known user and saves it
+func (am *accessManager) update(userID int64, change func(*store.UserData)) (*command.UserInfo, error) {
+	u, err := am.db.LoadUser("telegram", userID)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		return nil, fmt.Errorf("unknown user: %d", userID)
+	}
+
+	change(u)
+	u.UpdatedAt = time.Now()
+	if err := am.db.SaveUser(u); err != nil {
+		return nil, err
+	}
+
+	am.log.Info("👥 User %d is now %s (%s)", userID, u.Status, u.Role)
+	info := userInfo(u)
+	return &info, nil
+}
+
+// notifyAdmins sends a message to the configured admin and all promoted
+// admins. Returns the number of admins notified.
+func (am *accessManager) notifyAdmins(text string, keyboard *channel.InlineKeyboard) int {
+	if am.send == nil {
+		return 0
+	}
+
+	var ids []int64
+	if id := am.adminID(); id != 0 {
+		ids = append(ids, id)
+	}
+	users, err := am.db.ListUsers("telegram")
+	if err != nil {
+		am.log.Warn("Failed to list admins: %v", err)
+	}
+	for _, u := range users {
+		if u.Status == "approved" && u.Role == "admin" && u.UserID != am.adminID() {
+			ids = append(ids, u.UserID)
+		}
+	}
+
+	sent := 0
+	for _, id := range ids {
+		if err := am.send(id, text, keyboard); err != nil {
+			am.log.Warn("Failed to notify admin %d: %v", id, err)
+			continue
+		}
+		sent++
+	}
+	return sent
+}
+
+func newUserData(user *channel.TelegramUser, status, role string) *store.UserData {
+	now := time.Now()
+	return &store.UserData{
+		Channel:   "telegram",
+		UserID:    user.ID,
+		Username:  user.Username,
+		Name:      strings.TrimSpace(user.FirstName + " " + user.LastName),
+		Status:    status,
+		Role:      role,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+func userInfo(u *store.UserData) command.UserInfo {
+	return command.UserInfo{
+		UserID:    u.UserID,
+		Username:  u.Username,
+		Name:      u.Name,
+		Status:    u.Status,
+		Role:      u.Role,
+		UpdatedAt: u.UpdatedAt,
+	}
+}
+
+// displayName returns "@username", falling back to the user's name
+func displayName(u *store.UserData) string {
+	if u.Username != "" {
+		return "@" + u.Username
+	}
+	if u.Name != "" {
+		return u.Name
+	}
+	return "A user"
+}
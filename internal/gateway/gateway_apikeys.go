@@ -0,0 +1,421 @@
+package gateway
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/FeelPulse/feelpulse/internal/apikey"
+	"github.com/FeelPulse/feelpulse/internal/ratelimit"
+	"github.com/FeelPulse/feelpulse/internal/scheduler"
+	"github.com/FeelPulse/feelpulse/internal/store"
+)
+
+// NewAPIKeyManager returns an API key manager backed by the database. The
+// CLI uses it too, so keys it creates take effect in a running gateway.
+func NewAPIKeyManager(db *store.SQLiteStore) (*apikey.Manager, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database not available")
+	}
+	if err := db.EnsureAPIKeysTable(); err != nil {
+		return nil, fmt.Errorf("failed to create api_keys table: %w", err)
+	}
+	return apikey.NewManager(&apiKeyPersister{db: db}), nil
+}
+
+// initializeAPIKeys enables scoped API keys when the database is available
+func (gw *Gateway) initializeAPIKeys() {
+	if gw.db == nil {
+		return
+	}
+	keys, err := NewAPIKeyManager(gw.db)
+	if err != nil {
+		gw.log.Warn("Failed to initialize API keys: %v", err)
+		return
+	}
+	gw.apiKeys = keys
+
+	if ok, _ := keys.HasKeys(); ok && gw.cfg.Hooks.Token != "" {
+		gw.log.Warn("⚠️ hooks.token is deprecated and grants full access; use API keys instead")
+	}
+}
+
+// authConfigured reports whether HTTP endpoints require credentials. Once
+// any API key exists, they do for good: revoking or outliving the last key
+// must not open the gateway.
+func (gw *Gateway) authConfigured() bool {
+	if gw.cfg.Hooks.Token != "" {
+		return true
+	}
+	if gw.apiKeys == nil {
+		return false
+	}
+	ok, err := gw.apiKeys.HasKeys()
+	if err != nil {
+		gw.log.Warn("Failed to count API keys: %v", err)
+		return true // Fail closed
+	}
+	return ok
+}
+
+// authorize checks a request's credentials for a scope. It accepts an API
+// key or the legacy hooks.token, which grants every scope. Without either
+// configured, every request is allowed. On success it returns the request
+// carrying the API key (if any) in its context; otherwise it writes 401 or
+// 403 and returns false.
+func (gw *Gateway) authorize(w http.ResponseWriter, r *http.Request, scope string) (*http.Request, bool) {
+	// Browsers logged in to the dashboard carry a session cookie
+	if scope == apikey.ScopeAdmin || scope == apikey.ScopeConfig {
+		if sess := gw.dashboardSession(r); sess != nil {
+			return gw.authorizeDashboardSession(w, r, sess)
+		}
+	}
+
+	if !gw.authRequired(scope) {
+		return r, true // no auth configured
+	}
+
+	token := requestToken(r, scope)
+	if token == "" {
+		if isDashboardPage(r) {
+			redirectToLogin(w, r)
+			return r, false
+		}
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return r, false
+	}
+
+	if legacy := gw.cfg.Hooks.Token; legacy != "" && subtle.ConstantTimeCompare([]byte(token), []byte(legacy)) == 1 {
+		return r, true
+	}
+
+	if gw.apiKeys == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return r, false
+	}
+	key, err := gw.apiKeys.Authenticate(token)
+	if err != nil {
+		if !errors.Is(err, apikey.ErrInvalid) && !errors.Is(err, apikey.ErrExpired) && !errors.Is(err, apikey.ErrRevoked) {
+			gw.log.Error("API key check failed: %v", err)
+		}
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return r, false
+	}
+	if !key.HasScope(scope) {
+		gw.log.Info("🔒 API key %s lacks the %s scope for %s", key.Prefix, scope, r.URL.Path)
+		http.Error(w, fmt.Sprintf("Forbidden: API key lacks the %s scope", scope), http.StatusForbidden)
+		return r, false
+	}
+	return r.WithContext(apikey.NewContext(r.Context(), key)), true
+}
+
+// requestToken returns the credentials a request carries. The ?token=
+// query parameter is only accepted for webhooks, whose senders often can't
+// set headers.
+func requestToken(r *http.Request, scope string) string {
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		return strings.TrimPrefix(auth, "Bearer ")
+	}
+
+	// Anthropic SDKs send the key as x-api-key
+	if key := r.Header.Get("X-Api-Key"); key != "" {
+		return key
+	}
+
+	if scope == apikey.ScopeHooks {
+		return r.URL.Query().Get("token")
+	}
+	return ""
+}
+
+// allowAPIKey applies the rate limit of the key a request was made with
+func (gw *Gateway) allowAPIKey(r *http.Request) bool {
+	key := apikey.FromContext(r.Context())
+	if key == nil || key.RateLimit <= 0 {
+		return true
+	}
+
+	// A changed limit gets a fresh limiter
+	id := fmt.Sprintf("%s:%d", key.ID, key.RateLimit)
+	gw.keyLimitersMu.Lock()
+	limiter, ok := gw.keyLimiters[id]
+	if !ok {
+		if gw.keyLimiters == nil {
+			gw.keyLimiters = make(map[string]*ratelimit.Limiter)
+		}
+		limiter = ratelimit.New(key.RateLimit)
+		gw.keyLimiters[id] = limiter
+	}
+	gw.keyLimitersMu.Unlock()
+
+	if !limiter.Allow(key.ID) {
+		gw.log.Info("⏱️ API key %s rate limited", key.Prefix)
+		return false
+	}
+	return true
+}
+
+// allowAPIServerTools reports whether the key a request was made with may
+// use FeelPulse's own tools. Requests without a key (no auth configured or
+// the legacy hooks.token) keep the gateway-wide setting.
+func allowAPIServerTools(r *http.Request) bool {
+	key := apikey.FromContext(r.Context())
+	return key == nil || key.AllowsServerTools()
+}
+
+// allowAPIModel reports whether the key a request was made with may use a model
+func allowAPIModel(r *http.Request, model string) bool {
+	key := apikey.FromContext(r.Context())
+	return key == nil || key.AllowsModel(model)
+}
+
+// recordAPIKeyUsage adds a reply's token usage to the key a request was made with
+func (gw *Gateway) recordAPIKeyUsage(r *http.Request, inputTokens, outputTokens int) {
+	key := apikey.FromContext(r.Context())
+	if key == nil || gw.apiKeys == nil {
+		return
+	}
+	if err := gw.apiKeys.RecordUsage(key.ID, inputTokens, outputTokens); err != nil {
+		gw.log.Warn("Failed to record API key usage: %v", err)
+	}
+}
+
+// === Key management API ===
+
+// APIKeyInfo describes an API key in the key management API
+type APIKeyInfo struct {
+	ID           string     `json:"id"`
+	Name         string     `json:"name"`
+	Prefix       string     `json:"prefix"`
+	Scopes       []string   `json:"scopes"`
+	Models       []string   `json:"models,omitempty"`
+	RateLimit    int        `json:"rate_limit,omitempty"`
+	ServerTools  bool       `json:"server_tools"`
+	Status       string     `json:"status"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	LastUsedAt   *time.Time `json:"last_used_at,omitempty"`
+	Requests     int64      `json:"requests"`
+	InputTokens  int64      `json:"input_tokens"`
+	OutputTokens int64      `json:"output_tokens"`
+}
+
+// APIKeyCreateRequest is the body of POST /api/keys
+type APIKeyCreateRequest struct {
+	Name        string   `json:"name"`
+	Scopes      []string `json:"scopes"`
+	Models      []string `json:"models"`
+	RateLimit   int      `json:"rate_limit"`
+	ServerTools bool     `json:"server_tools"` // Offer FeelPulse's own tools to chat requests
+	ExpiresIn   string   `json:"expires_in"`   // e.g. "90d"; empty = never
+}
+
+func (gw *Gateway) setupAPIKeyRoutes() {
+	gw.mux.HandleFunc("GET /api/keys", gw.requireKeyAPIAuth(gw.handleListAPIKeys))
+	gw.mux.HandleFunc("POST /api/keys", gw.requireKeyAPIAuth(gw.handleCreateAPIKey))
+	gw.mux.HandleFunc("DELETE /api/keys/{id}", gw.requireKeyAPIAuth(gw.handleRevokeAPIKey))
+}
+
+// requireKeyAPIAuth guards the key management API, which needs the admin
+// scope and is closed to viewer logins. Like the session API it is never
+// open: the first key is created with the CLI or by a dashboard login.
+func (gw *Gateway) requireKeyAPIAuth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if gw.apiKeys == nil {
+			writeAPIError(w, http.StatusServiceUnavailable, "API keys are not available")
+			return
+		}
+		if !gw.authRequired(apikey.ScopeAdmin) {
+			writeAPIError(w, http.StatusForbidden, "key API requires an API key or dashboard password; create a key with 'feelpulse keys create'")
+			return
+		}
+		r, ok := gw.authorize(w, r, apikey.ScopeAdmin)
+		if !ok {
+			return
+		}
+		denyViewers(next)(w, r)
+	}
+}
+
+func (gw *Gateway) handleListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := gw.apiKeys.List()
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	now := time.Now()
+	infos := make([]APIKeyInfo, 0, len(keys))
+	for _, k := range keys {
+		infos = append(infos, newAPIKeyInfo(k, now))
+	}
+	writeAPIJSON(w, http.StatusOK, map[string]any{"keys": infos})
+}
+
+func (gw *Gateway) handleCreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	var req APIKeyCreateRequest
+	if !decodeAPIRequest(w, r, &req) {
+		return
+	}
+
+	var expiresIn time.Duration
+	if req.ExpiresIn != "" {
+		d, err := parseKeyExpiry(req.ExpiresIn)
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		expiresIn = d
+	}
+	for _, m := range req.Models {
+		if !gw.isKnownModel(m) {
+			writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("unknown model '%s'", m))
+			return
+		}
+	}
+
+	token, key, err := gw.apiKeys.Create(apikey.CreateOptions{
+		Name:        req.Name,
+		Scopes:      req.Scopes,
+		Models:      req.Models,
+		RateLimit:   req.RateLimit,
+		ServerTools: req.ServerTools,
+		ExpiresIn:   expiresIn,
+	})
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	gw.log.Info("🔑 API key %s (%s) created with scopes %s", key.Prefix, key.Name, strings.Join(key.Scopes, ","))
+	writeAPIJSON(w, http.StatusCreated, map[string]any{
+		"key":   newAPIKeyInfo(key, time.Now()),
+		"token": token,
+	})
+}
+
+func (gw *Gateway) handleRevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	key, err := gw.apiKeys.Revoke(r.PathValue("id"))
+	if err != nil {
+		status := http.StatusBadRequest
+		if strings.Contains(err.Error(), "not found") {
+			status = http.StatusNotFound
+		}
+		writeAPIError(w, status, err.Error())
+		return
+	}
+	gw.log.Info("🔑 API key %s (%s) revoked", key.Prefix, key.Name)
+	writeAPIJSON(w, http.StatusOK, newAPIKeyInfo(key, time.Now()))
+}
+
+// parseKeyExpiry parses a key lifetime like "90d" or "12h"
+func parseKeyExpiry(s string) (time.Duration, error) {
+	d, err := scheduler.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid expiry '%s' (use e.g. 90d or 12h)", s)
+	}
+	return d, nil
+}
+
+func newAPIKeyInfo(k *apikey.Key, now time.Time) APIKeyInfo {
+	info := APIKeyInfo{
+		ID:           k.ID,
+		Name:         k.Name,
+		Prefix:       k.Prefix,
+		Scopes:       k.Scopes,
+		Models:       k.Models,
+		RateLimit:    k.RateLimit,
+		ServerTools:  k.ServerTools,
+		Status:       k.Status(now),
+		CreatedAt:    k.CreatedAt,
+		Requests:     k.Requests,
+		InputTokens:  k.InputTokens,
+		OutputTokens: k.OutputTokens,
+	}
+	if !k.ExpiresAt.IsZero() {
+		info.ExpiresAt = &k.ExpiresAt
+	}
+	if !k.LastUsedAt.IsZero() {
+		info.LastUsedAt = &k.LastUsedAt
+	}
+	return info
+}
+
+// apiKeyPersister wraps SQLiteStore to implement apikey.Persister
+type apiKeyPersister struct {
+	db *store.SQLiteStore
+}
+
+func (p *apiKeyPersister) SaveAPIKey(k *apikey.Key) error {
+	return p.db.SaveAPIKey(&store.APIKeyData{
+		ID:           k.ID,
+		Name:         k.Name,
+		Prefix:       k.Prefix,
+		Hash:         k.Hash,
+		Scopes:       strings.Join(k.Scopes, ","),
+		Models:       strings.Join(k.Models, ","),
+		RateLimit:    k.RateLimit,
+		ServerTools:  k.ServerTools,
+		ExpiresAt:    k.ExpiresAt,
+		CreatedAt:    k.CreatedAt,
+		LastUsedAt:   k.LastUsedAt,
+		RevokedAt:    k.RevokedAt,
+		Requests:     k.Requests,
+		InputTokens:  k.InputTokens,
+		OutputTokens: k.OutputTokens,
+	})
+}
+
+func (p *apiKeyPersister) LoadAPIKeys() ([]*apikey.Key, error) {
+	data, err := p.db.LoadAPIKeys()
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]*apikey.Key, 0, len(data))
+	for _, d := range data {
+		keys = append(keys, apiKeyFromData(d))
+	}
+	return keys, nil
+}
+
+func (p *apiKeyPersister) LoadAPIKeyByHash(hash string) (*apikey.Key, error) {
+	d, err := p.db.LoadAPIKeyByHash(hash)
+	if err != nil || d == nil {
+		return nil, err
+	}
+	return apiKeyFromData(d), nil
+}
+
+func (p *apiKeyPersister) CountAPIKeys() (int, error) {
+	return p.db.CountAPIKeys()
+}
+
+func (p *apiKeyPersister) TouchAPIKey(id string, at time.Time) error {
+	return p.db.TouchAPIKey(id, at)
+}
+
+func (p *apiKeyPersister) AddAPIKeyUsage(id string, inputTokens, outputTokens int) error {
+	return p.db.AddAPIKeyUsage(id, inputTokens, outputTokens)
+}
+
+func apiKeyFromData(d *store.APIKeyData) *apikey.Key {
+	return &apikey.Key{
+		ID:           d.ID,
+		Name:         d.Name,
+		Prefix:       d.Prefix,
+		Hash:         d.Hash,
+		Scopes:       apikey.SplitList(d.Scopes),
+		Models:       apikey.SplitList(d.Models),
+		RateLimit:    d.RateLimit,
+		ServerTools:  d.ServerTools,
+		ExpiresAt:    d.ExpiresAt,
+		CreatedAt:    d.CreatedAt,
+		LastUsedAt:   d.LastUsedAt,
+		RevokedAt:    d.RevokedAt,
+		Requests:     d.Requests,
+		InputTokens:  d.InputTokens,
+		OutputTokens: d.OutputTokens,
+	}
+}
@@ -0,0 +1,223 @@
+package gateway
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/FeelPulse/feelpulse/internal/apikey"
+	"github.com/FeelPulse/feelpulse/internal/config"
+	"github.com/FeelPulse/feelpulse/internal/logger"
+	"github.com/FeelPulse/feelpulse/internal/store"
+)
+
+func newAPIKeyGateway(t *testing.T) *Gateway {
+	t.Helper()
+
+	db, err := store.NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	gw := &Gateway{
+		cfg: config.Default(),
+		mux: http.NewServeMux(),
+		db:  db,
+		log: logger.New(&logger.Config{Level: "error"}),
+	}
+	gw.initializeAPIKeys()
+	gw.setupAPIKeyRoutes()
+	gw.mux.HandleFunc("/v1/models", gw.handleOpenAIModels)
+	return gw
+}
+
+// authorizeWith checks a request made with a bearer token for a scope and
+// returns the status written, 200 if authorized
+func authorizeWith(gw *Gateway, method, path, token, scope string) int {
+	req := httptest.NewRequest(method, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	if _, ok := gw.authorize(rec, req, scope); ok {
+		return http.StatusOK
+	}
+	return rec.Code
+}
+
+func TestAuthorize_Scopes(t *testing.T) {
+	gw := newAPIKeyGateway(t)
+
+	if code := authorizeWith(gw, "POST", "/v1/messages", "", apikey.ScopeChat); code != http.StatusOK {
+		t.Errorf("without keys or hooks.token requests should be open, got %d", code)
+	}
+
+	chat, _, _ := gw.apiKeys.Create(apikey.CreateOptions{Name: "chat", Scopes: []string{"chat"}})
+	admin, _, _ := gw.apiKeys.Create(apikey.CreateOptions{Name: "admin", Scopes: []string{"admin"}})
+	revoked, key, _ := gw.apiKeys.Create(apikey.CreateOptions{Name: "old", Scopes: []string{"chat"}})
+	gw.apiKeys.Revoke(key.ID)
+	gw.cfg.Hooks.Token = "legacy"
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		scope  string
+		want   int
+	}{
+		{"no credentials", "POST", "/v1/messages", "", apikey.ScopeChat, 401},
+		{"chat key", "POST", "/v1/messages", chat, apikey.ScopeChat, 200},
+		{"chat key on config", "POST", "/api/config", chat, apikey.ScopeConfig, 403},
+		{"admin key on config", "POST", "/api/config", admin, apikey.ScopeConfig, 200},
+		{"revoked key", "POST", "/v1/messages", revoked, apikey.ScopeChat, 401},
+		{"unknown key", "POST", "/v1/messages", "fpk_guess", apikey.ScopeChat, 401},
+		{"legacy token", "GET", "/api/sessions", "legacy", apikey.ScopeAdmin, 200},
+		{"query token on chat", "POST", "/v1/messages?token=" + chat, "", apikey.ScopeChat, 401},
+		{"query token on dashboard", "GET", "/dashboard?token=" + admin, "", apikey.ScopeAdmin, 303},
+		{"query token on hooks", "POST", "/hooks/ci?token=legacy", "", apikey.ScopeHooks, 200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := authorizeWith(gw, tt.method, tt.path, tt.token, tt.scope); got != tt.want {
+				t.Errorf("status = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAPIKey_LimitsAndUsage(t *testing.T) {
+	gw := newAPIKeyGateway(t)
+	token, key, err := gw.apiKeys.Create(apikey.CreateOptions{Name: "ci", Scopes: []string{"chat"},
+		Models: []string{"claude-3-haiku-20240307"}, RateLimit: 1})
+	if err != nil {
+		t.Fatalf("Create error: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/models", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	gw.mux.ServeHTTP(rec, req)
+	var list OpenAIModelList
+	json.Unmarshal(rec.Body.Bytes(), &list)
+	if len(list.Data) != 1 || list.Data[0].ID != "claude-3-haiku-20240307" {
+		t.Errorf("models should be limited to the key's allowlist: %+v", list.Data)
+	}
+
+	r, ok := gw.authorize(httptest.NewRecorder(), req, apikey.ScopeChat)
+	if !ok {
+		t.Fatal("key should be authorized")
+	}
+	if !allowAPIModel(r, "claude-3-haiku-20240307") || allowAPIModel(r, "claude-opus-4-20250514") {
+		t.Error("unexpected model allowlist")
+	}
+	if gw.apiServerTools(r) {
+		t.Error("chat keys should not be offered server tools unless enabled for the key")
+	}
+	if !gw.apiServerTools(httptest.NewRequest(http.MethodPost, "/v1/messages", nil)) {
+		t.Error("requests without a key should keep the gateway-wide server tools setting")
+	}
+	if !gw.allowAPIKey(r) || gw.allowAPIKey(r) {
+		t.Error("second request within a minute should exceed the key's rate limit")
+	}
+
+	gw.recordAPIKeyUsage(r, 100, 20)
+	keys, _ := gw.apiKeys.List()
+	if keys[0].ID != key.ID || keys[0].Requests != 2 || keys[0].InputTokens != 100 || keys[0].OutputTokens != 20 {
+		t.Errorf("unexpected key usage: %+v", keys[0])
+	}
+}
+
+func TestAPIKey_ManagementAPI(t *testing.T) {
+	gw := newAPIKeyGateway(t)
+
+	do := func(method, path, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		gw.mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := do("GET", "/api/keys", "", ""); rec.Code != http.StatusForbidden {
+		t.Errorf("without any key: status = %d, want 403", rec.Code)
+	}
+
+	admin, _, _ := gw.apiKeys.Create(apikey.CreateOptions{Name: "admin", Scopes: []string{"admin"}})
+
+	rec := do("POST", "/api/keys", admin, `{"name": "ci", "scopes": ["chat"], "expires_in": "30d"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create: status = %d: %s", rec.Code, rec.Body.String())
+	}
+	var created struct {
+		Key   APIKeyInfo `json:"key"`
+		Token string     `json:"token"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &created)
+	if created.Key.ExpiresAt == nil || !strings.HasPrefix(created.Token, apikey.TokenPrefix) {
+		t.Errorf("unexpected created key: %s", rec.Body.String())
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		body   string
+		status int
+		want   string
+	}{
+		{"list", "GET", "/api/keys", admin, "", 200, `"name":"ci"`},
+		{"list without admin", "GET", "/api/keys", created.Token, "", 403, ""},
+		{"unknown scope", "POST", "/api/keys", admin, `{"name": "x", "scopes": ["root"]}`, 400, "unknown scope"},
+		{"bad expiry", "POST", "/api/keys", admin, `{"name": "x", "scopes": ["chat"], "expires_in": "soon"}`, 400, "invalid expiry"},
+		{"unknown model", "POST", "/api/keys", admin, `{"name": "x", "scopes": ["chat"], "models": ["gpt-2"]}`, 400, "unknown model"},
+		{"revoke", "DELETE", "/api/keys/" + created.Key.ID, admin, "", 200, `"status":"revoked"`},
+		{"revoke unknown", "DELETE", "/api/keys/zzzz", admin, "", 404, "not found"},
+		{"revoked key", "GET", "/v1/models", created.Token, "", 401, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := do(tt.method, tt.path, tt.token, tt.body)
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body.String())
+			}
+			if !strings.Contains(rec.Body.String(), tt.want) {
+				t.Errorf("body %s does not contain %s", rec.Body.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestConfigSave_HooksTokenNeedsAdmin(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	gw := newAPIKeyGateway(t)
+	gw.mux.HandleFunc("/api/config", gw.handleConfigSave)
+
+	configKey, _, _ := gw.apiKeys.Create(apikey.CreateOptions{Name: "config", Scopes: []string{"config"}})
+	admin, _, _ := gw.apiKeys.Create(apikey.CreateOptions{Name: "admin", Scopes: []string{"admin"}})
+
+	save := func(token, body string) int {
+		req := httptest.NewRequest(http.MethodPost, "/api/config", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		gw.mux.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := save(configKey, `{"hooksToken": "mine"}`); code != http.StatusForbidden {
+		t.Errorf("config key setting hooks.token: status = %d, want 403", code)
+	}
+	if code := save(configKey, `{"hooksToken": ""}`); code == http.StatusForbidden {
+		t.Error("config key should save settings that leave hooks.token alone")
+	}
+	if code := save(admin, `{"hooksToken": "mine"}`); code == http.StatusForbidden {
+		t.Error("admin key should be able to set hooks.token")
+	}
+}
@@ -0,0 +1,132 @@
+package gateway
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/FeelPulse/feelpulse/internal/events"
+	"github.com/FeelPulse/feelpulse/internal/session"
+	"github.com/FeelPulse/feelpulse/internal/webhook"
+	"github.com/FeelPulse/feelpulse/pkg/types"
+)
+
+// subscribeEvents registers the gateway's own subsystems on the event bus.
+// Each subsystem is one subscriber, so it sees events in publish order.
+// Metrics, token usage, the webhook outbox and the live feed's turns must
+// not miss events, so they subscribe losslessly.
+func (gw *Gateway) subscribeEvents() {
+	gw.events.SubscribeLossless("metrics", gw.metricsSubscriber,
+		events.TypeMessageAccepted, events.TypeReplySent, events.TypeAPIReplied)
+	gw.events.SubscribeLossless("usage", gw.usageSubscriber, events.TypeReplySent)
+	gw.events.Subscribe("dailylog", gw.dailylogSubscriber,
+		events.TypeMessageReceived, events.TypeReplySent)
+	gw.events.Subscribe("hooks", gw.hooksSubscriber, events.TypeSubAgentCompleted)
+	gw.events.SubscribeLossless("webhooks", gw.webhooksSubscriber, webhook.Events...)
+	gw.events.Subscribe("audit", gw.auditSubscriber)
+	gw.events.SubscribeLossless("live", gw.liveSubscriber)
+}
+
+// Events returns the gateway's event bus, for extensions that observe
+// the message lifecycle
+func (gw *Gateway) Events() *events.Bus {
+	return gw.events
+}
+
+// publish puts an event on the bus
+func (gw *Gateway) publish(e events.Event) {
+	gw.events.Publish(e)
+}
+
+// publishToolCalled publishes a tool.called event
+func (gw *Gateway) publishToolCalled(sessionKey, name string, input map[string]any, err error) {
+	e := events.ToolCalled{Session: sessionKey, Tool: name, Input: input}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	gw.publish(e)
+}
+
+// publishTurnFailed publishes a turn.failed event
+func (gw *Gateway) publishTurnFailed(msg *types.Message, ctx *messageProcessingContext, err error) {
+	gw.publish(events.TurnFailed{
+		Channel: msg.Channel,
+		UserID:  ctx.userID,
+		Session: session.SessionKey(msg.Channel, ctx.userID),
+		Error:   err.Error(),
+	})
+}
+
+// metricsSubscriber counts messages, API requests and tokens
+func (gw *Gateway) metricsSubscriber(e events.Event) {
+	switch e := e.(type) {
+	case events.MessageAccepted:
+		gw.metrics.IncrementMessages(e.Channel)
+		gw.metrics.SetActiveSessions(gw.sessions.Count())
+	case events.ReplySent:
+		gw.metrics.AddTokens(e.InputTokens, e.OutputTokens)
+	case events.APIReplied:
+		gw.metrics.IncrementMessages(e.Endpoint)
+		gw.metrics.AddTokens(e.InputTokens, e.OutputTokens)
+	}
+}
+
+// usageSubscriber records per-session token usage for /usage
+func (gw *Gateway) usageSubscriber(e events.Event) {
+	if reply, ok := e.(events.ReplySent); ok && gw.usage != nil {
+		gw.usage.Record(reply.Channel, reply.UserID, reply.InputTokens, reply.OutputTokens, reply.Model)
+	}
+}
+
+// dailylogSubscriber writes conversations to the workspace daily log
+func (gw *Gateway) dailylogSubscriber(e events.Event) {
+	if gw.dailylog == nil {
+		return
+	}
+
+	var err error
+	switch e := e.(type) {
+	case events.MessageReceived:
+		err = gw.dailylog.Log(e.Message)
+	case events.ReplySent:
+		err = gw.dailylog.Log(e.Reply)
+	}
+	if err != nil {
+		gw.log.Warn("Failed to write daily log: %v", err)
+	}
+}
+
+// hooksSubscriber finishes webhook deliveries handled by a sub-agent
+func (gw *Gateway) hooksSubscriber(e events.Event) {
+	done, ok := e.(events.SubAgentCompleted)
+	if !ok || gw.hooks == nil {
+		return
+	}
+	var err error
+	if done.Error != "" {
+		err = errors.New(done.Error)
+	}
+	gw.hooks.finishAgent(done.ID, err)
+}
+
+// webhooksSubscriber queues events for outbound webhooks
+func (gw *Gateway) webhooksSubscriber(e events.Event) {
+	if gw.webhooks == nil {
+		return
+	}
+	if err := gw.webhooks.Publish(e.Type(), e); err != nil {
+		gw.log.Warn("Failed to publish %s event: %v", e.Type(), err)
+	}
+}
+
+// auditSubscriber writes every event to the log when log.audit is set
+func (gw *Gateway) auditSubscriber(e events.Event) {
+	if gw.cfg == nil || !gw.cfg.Log.Audit {
+		return
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		gw.log.Warn("Failed to encode %s event for audit: %v", e.Type(), err)
+		return
+	}
+	gw.log.WithComponent("audit").Info("📋 %s %s", e.Type(), data)
+}
@@ -0,0 +1,106 @@
+package gateway
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FeelPulse/feelpulse/internal/agent"
+	"github.com/FeelPulse/feelpulse/internal/config"
+	"github.com/FeelPulse/feelpulse/internal/dailylog"
+	"github.com/FeelPulse/feelpulse/internal/events"
+	"github.com/FeelPulse/feelpulse/internal/logger"
+	"github.com/FeelPulse/feelpulse/internal/metrics"
+	"github.com/FeelPulse/feelpulse/internal/ratelimit"
+	"github.com/FeelPulse/feelpulse/internal/session"
+	"github.com/FeelPulse/feelpulse/internal/usage"
+	"github.com/FeelPulse/feelpulse/pkg/types"
+)
+
+func TestGatewaySubscribers(t *testing.T) {
+	workspace := t.TempDir()
+	log := logger.New(&logger.Config{Level: "error"})
+	gw := &Gateway{
+		cfg:      config.Default(),
+		log:      log,
+		sessions: session.NewStore(),
+		metrics:  metrics.NewCollector(),
+		usage:    usage.NewTracker(),
+		dailylog: dailylog.NewWriter(workspace, true),
+		events:   events.New(log),
+	}
+	gw.subscribeEvents()
+
+	now := time.Now()
+	gw.publish(events.MessageAccepted{Channel: "telegram", UserID: "1"})
+	gw.publish(events.MessageReceived{Channel: "telegram", UserID: "1",
+		Message: types.Message{Text: "Hello there", Timestamp: now}})
+	gw.publish(events.ReplySent{Channel: "telegram", UserID: "1", Model: "claude", InputTokens: 100, OutputTokens: 20,
+		Reply: types.Message{Text: "Hi!", IsBot: true, Timestamp: now}})
+	gw.publish(events.APIReplied{Endpoint: "openai-compat", InputTokens: 10, OutputTokens: 5})
+	gw.events.Close()
+
+	if in, out := gw.metrics.GetTokensTotal(); in != 110 || out != 25 {
+		t.Errorf("tokens_total = %d/%d, want 110/25", in, out)
+	}
+	if messages := gw.metrics.GetMessagesTotal(); messages["telegram"] != 1 || messages["openai-compat"] != 1 {
+		t.Errorf("unexpected messages_total: %v", messages)
+	}
+
+	stats := gw.usage.Get("telegram", "1")
+	if stats.TotalTokens != 120 || stats.ModelsUsed["claude"] != 1 {
+		t.Errorf("unexpected usage: %+v", stats)
+	}
+
+	data, err := os.ReadFile(filepath.Join(workspace, "memory", now.Format("2006-01-02")+".md"))
+	if err != nil {
+		t.Fatalf("daily log not written: %v", err)
+	}
+	daily := string(data)
+	if i, j := strings.Index(daily, "Hello there"), strings.Index(daily, "Hi!"); i < 0 || j < i {
+		t.Errorf("daily log should hold the message, then the reply:\n%s", daily)
+	}
+}
+
+func TestPrepareMessageProcessing_MessageReceived(t *testing.T) {
+	cfg := config.Default()
+	cfg.Agent.APIKey = "test-key"
+	router, err := agent.NewRouter(cfg)
+	if err != nil {
+		t.Fatalf("NewRouter error: %v", err)
+	}
+	log := logger.New(&logger.Config{Level: "error"})
+	gw := &Gateway{
+		cfg:        cfg,
+		log:        log,
+		router:     router,
+		limiter:    ratelimit.New(1),
+		sessions:   session.NewStore(),
+		metrics:    metrics.NewCollector(),
+		events:     events.New(log),
+		shutdownCh: make(chan struct{}),
+	}
+	gw.subscribeEvents()
+	var received []string
+	gw.events.Subscribe("test", func(e events.Event) {
+		received = append(received, e.(events.MessageReceived).Text)
+	}, events.TypeMessageReceived)
+
+	for _, text := range []string{"first", "rate limited"} {
+		ctx, _ := gw.prepareMessageProcessing(&types.Message{Channel: "telegram", From: "alice", Text: text,
+			Metadata: map[string]any{"user_id": int64(1)}})
+		if ctx != nil {
+			gw.activeRequests.Done()
+		}
+	}
+	gw.events.Close()
+
+	if len(received) != 1 || received[0] != "first" {
+		t.Errorf("message.received should only fire for messages passed to the agent: %v", received)
+	}
+	if got := gw.metrics.GetMessagesTotal()["telegram"]; got != 2 {
+		t.Errorf("messages_total = %d, want 2", got)
+	}
+}
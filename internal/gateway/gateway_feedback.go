@@ -0,0 +1,134 @@
+package gateway
+
+import (
+	"crypto/rand"
+	"fmt"
+	"time"
+
+	"github.com/FeelPulse/feelpulse/internal/channel"
+	"github.com/FeelPulse/feelpulse/internal/command"
+	"github.com/FeelPulse/feelpulse/internal/session"
+	"github.com/FeelPulse/feelpulse/internal/store"
+	"github.com/FeelPulse/feelpulse/pkg/types"
+)
+
+// Reactions counted as positive or negative feedback; others are neutral
+var (
+	positiveReactions = map[string]bool{"👍": true, "❤": true, "🔥": true, "🥰": true, "👏": true, "🎉": true, "🤩": true, "💯": true, "😍": true, "🙏": true, "👌": true, "🏆": true}
+	negativeReactions = map[string]bool{"👎": true, "💩": true, "🤮": true, "😡": true, "🤬": true, "😢": true, "🥱": true, "😴": true}
+)
+
+// reactionScore maps a reaction emoji to 1 (positive), -1 (negative) or 0
+func reactionScore(emoji string) int {
+	switch {
+	case positiveReactions[emoji]:
+		return 1
+	case negativeReactions[emoji]:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// newReplyID returns an ID for a stored assistant message, so feedback can
+// refer to it
+func newReplyID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return fmt.Sprintf("msg-%x", b)
+}
+
+// feedbackRecorder implements channel.FeedbackRecorder and
+// command.FeedbackProvider using SQLite
+type feedbackRecorder struct {
+	db       *store.SQLiteStore
+	sessions *session.Store
+	userID   func(msg *types.Message) string
+}
+
+// initializeFeedback enables reaction tracking for the Telegram bot
+func (gw *Gateway) initializeFeedback(telegram *channel.TelegramBot) {
+	if gw.db == nil {
+		return
+	}
+	if err := gw.db.EnsureFeedbackTables(); err != nil {
+		gw.log.Warn("Failed to create feedback tables: %v", err)
+		return
+	}
+
+	gw.feedback = &feedbackRecorder{db: gw.db, sessions: gw.sessions, userID: gw.getUserID}
+	telegram.SetFeedbackRecorder(gw.feedback)
+}
+
+// RecordSent stores which Telegram messages delivered a reply, with the
+// model and profile that produced it
+func (f *feedbackRecorder) RecordSent(chatID int64, messageIDs []int64, msg, reply *types.Message) error {
+	if reply.ID == "" {
+		return nil // Command and error replies are not stored
+	}
+
+	userID := f.userID(msg)
+	model, _ := reply.Metadata["model"].(string)
+	var profile string
+	if sess, ok := f.sessions.Get(msg.Channel, userID); ok {
+		profile = sess.GetProfile()
+	}
+
+	now := time.Now()
+	for _, id := range messageIDs {
+		err := f.db.SaveSentMessage(&store.SentMessageData{
+			Channel:    msg.Channel,
+			ChatID:     chatID,
+			MessageID:  id,
+			ReplyID:    reply.ID,
+			SessionKey: session.SessionKey(msg.Channel, userID),
+			Model:      model,
+			Profile:    profile,
+			SentAt:     now,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RecordReaction stores (or removes) a reaction to one of the bot's replies.
+// Reactions to other messages are ignored.
+func (f *feedbackRecorder) RecordReaction(chatID, messageID, userID int64, emoji string) error {
+	sent, err := f.db.LoadSentMessage("telegram", chatID, messageID)
+	if err != nil || sent == nil {
+		return err
+	}
+
+	if emoji == "" {
+		return f.db.DeleteFeedback("telegram", chatID, messageID, userID)
+	}
+
+	return f.db.SaveFeedback(&store.FeedbackData{
+		SentMessageData: *sent,
+		UserID:          userID,
+		Reaction:        emoji,
+		Score:           reactionScore(emoji),
+		CreatedAt:       time.Now(),
+	})
+}
+
+// FeedbackStats returns feedback counts per model and profile
+func (f *feedbackRecorder) FeedbackStats() ([]command.FeedbackStat, error) {
+	stats, err := f.db.FeedbackStats()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]command.FeedbackStat, len(stats))
+	for i, st := range stats {
+		result[i] = command.FeedbackStat{
+			Model:    st.Model,
+			Profile:  st.Profile,
+			Positive: st.Positive,
+			Negative: st.Negative,
+			Total:    st.Total,
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,469 @@
+package gateway
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/FeelPulse/feelpulse/internal/agent"
+	"github.com/FeelPulse/feelpulse/internal/config"
+	"github.com/FeelPulse/feelpulse/internal/logger"
+	"github.com/FeelPulse/feelpulse/internal/session"
+	"github.com/FeelPulse/feelpulse/internal/store"
+	"github.com/FeelPulse/feelpulse/internal/subagent"
+	"github.com/FeelPulse/feelpulse/internal/tools"
+	"github.com/FeelPulse/feelpulse/pkg/types"
+)
+
+const (
+	maxHookBodySize    = 1 << 20 // 1 MB
+	maxHookDeliveries  = 50      // Deliveries kept in memory for the dashboard
+	hookDeliveryMaxAge = 30 * 24 * time.Hour
+)
+
+// Webhook delivery statuses
+const (
+	hookAccepted  = "accepted"
+	hookRunning   = "running"
+	hookDelivered = "delivered" // Result sent to the mapping's channel
+	hookDone      = "done"      // Finished, no channel to deliver to
+	hookFailed    = "failed"
+	hookRejected  = "rejected" // Signature check failed
+)
+
+// hookRoute is a webhook mapping with its parsed prompt template
+type hookRoute struct {
+	mapping config.HookMapping
+	name    string
+	path    string
+	prompt  *template.Template
+}
+
+// hookData is what a mapping's prompt template is rendered with
+type hookData struct {
+	Name    string
+	Path    string
+	Event   string            // X-GitHub-Event or X-Hook-Event header
+	Headers map[string]string // First value of each header, without credentials
+	Query   map[string]string // First value of each query parameter, without the token
+	Payload any               // Decoded JSON body
+}
+
+// hookTemplateFuncs are available in prompt templates
+var hookTemplateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		data, err := json.MarshalIndent(v, "", "  ")
+		return string(data), err
+	},
+	"truncate": func(n int, s string) string {
+		return truncateForDisplay(s, n)
+	},
+}
+
+// newHookRoute parses a mapping's prompt template
+func newHookRoute(m config.HookMapping) (*hookRoute, error) {
+	route := &hookRoute{mapping: m, path: hookPath(m.Path), name: m.Name}
+	if route.name == "" {
+		route.name = path.Base(route.path)
+	}
+
+	tmpl, err := template.New(route.name).Funcs(hookTemplateFuncs).Parse(m.Prompt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse prompt of hook %s: %w", route.name, err)
+	}
+	route.prompt = tmpl
+	return route, nil
+}
+
+// render builds the agent prompt for a request
+func (r *hookRoute) render(data hookData) (string, error) {
+	var buf bytes.Buffer
+	if err := r.prompt.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render prompt: %w", err)
+	}
+	prompt := strings.TrimSpace(buf.String())
+	if prompt == "" {
+		return "", fmt.Errorf("prompt rendered empty")
+	}
+	return prompt, nil
+}
+
+// target returns the session the result is delivered to, if any
+func (r *hookRoute) target() string {
+	if r.mapping.Channel == "" {
+		return ""
+	}
+	return session.SessionKey(r.mapping.Channel, r.mapping.To)
+}
+
+// options returns how the agent runs the mapping's prompts: without server
+// tools unless serverTools is set, since the prompt embeds an untrusted payload
+func (r *hookRoute) options() agent.ProcessOptions {
+	return agent.ProcessOptions{NoServerTools: !r.mapping.ServerTools}
+}
+
+// tools returns the registry the mapping's sub-agents may use, like options
+func (r *hookRoute) tools(registry *tools.Registry) *tools.Registry {
+	if !r.mapping.ServerTools {
+		return tools.NewRegistry()
+	}
+	return registry
+}
+
+// hookPath normalizes a mapping path; "github" is short for /hooks/github
+func hookPath(p string) string {
+	p = strings.TrimSuffix(strings.TrimSpace(p), "/")
+	if !strings.HasPrefix(p, "/") {
+		p = "/hooks/" + p
+	}
+	return p
+}
+
+// hookEvent returns the event type a webhook sender declared
+func hookEvent(header http.Header) string {
+	if event := header.Get("X-GitHub-Event"); event != "" {
+		return event
+	}
+	return header.Get("X-Hook-Event")
+}
+
+// verifyHookSignature checks a request against its mapping's secret.
+// GitHub signs the body with HMAC-SHA256 in X-Hub-Signature-256; other
+// senders pass the shared secret in X-Hook-Secret. A mapping that verifies
+// without a secret rejects every request, since anyone could sign with an
+// empty key.
+func verifyHookSignature(m config.HookMapping, header http.Header, body []byte) error {
+	if m.Verify != "" && m.Secret == "" {
+		return fmt.Errorf("mapping has no secret to verify with")
+	}
+	switch m.Verify {
+	case "":
+		return nil
+	case "github":
+		signature, found := strings.CutPrefix(header.Get("X-Hub-Signature-256"), "sha256=")
+		if !found {
+			return fmt.Errorf("missing X-Hub-Signature-256 header")
+		}
+		got, err := hex.DecodeString(signature)
+		if err != nil {
+			return fmt.Errorf("malformed signature")
+		}
+		mac := hmac.New(sha256.New, []byte(m.Secret))
+		mac.Write(body)
+		if !hmac.Equal(got, mac.Sum(nil)) {
+			return fmt.Errorf("signature mismatch")
+		}
+		return nil
+	case "secret":
+		secret := header.Get("X-Hook-Secret")
+		if secret == "" {
+			return fmt.Errorf("missing X-Hook-Secret header")
+		}
+		if subtle.ConstantTimeCompare([]byte(secret), []byte(m.Secret)) != 1 {
+			return fmt.Errorf("secret mismatch")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown verify method: %s", m.Verify)
+	}
+}
+
+// newHookDeliveryID returns an ID for a webhook delivery
+func newHookDeliveryID() string {
+	b := make([]byte, 6)
+	rand.Read(b)
+	return fmt.Sprintf("hook-%x", b)
+}
+
+// hookManager routes webhook paths to mappings and keeps the delivery log,
+// persisted in SQLite when available
+type hookManager struct {
+	db  *store.SQLiteStore
+	log *logger.Logger
+
+	mu         sync.RWMutex
+	routes     map[string]*hookRoute
+	deliveries []*store.HookDeliveryData // Newest first
+}
+
+// newHookManager creates a hook manager and loads recent deliveries
+func newHookManager(db *store.SQLiteStore, log *logger.Logger) *hookManager {
+	h := &hookManager{db: db, log: log, routes: make(map[string]*hookRoute)}
+	if db == nil {
+		return h
+	}
+
+	if err := db.EnsureHookDeliveriesTable(); err != nil {
+		log.Warn("Failed to create hook deliveries table: %v", err)
+		h.db = nil
+		return h
+	}
+	if removed, err := db.CleanOldHookDeliveries(hookDeliveryMaxAge); err == nil && removed > 0 {
+		log.Debug("🧹 Removed %d old webhook deliveries", removed)
+	}
+	deliveries, err := db.LoadHookDeliveries(maxHookDeliveries)
+	if err != nil {
+		log.Warn("Failed to load webhook deliveries: %v", err)
+		return h
+	}
+	h.deliveries = deliveries
+	return h
+}
+
+// setMappings replaces the routes, skipping mappings whose prompt doesn't
+// parse or that verify signatures without a secret
+func (h *hookManager) setMappings(mappings []config.HookMapping) {
+	routes := make(map[string]*hookRoute, len(mappings))
+	for _, m := range mappings {
+		if m.Verify != "" && m.Secret == "" {
+			h.log.Warn("⚠️ Webhook mapping %s disabled: verify %s needs a secret", hookPath(m.Path), m.Verify)
+			continue
+		}
+		route, err := newHookRoute(m)
+		if err != nil {
+			h.log.Error("❌ Webhook mapping disabled: %v", err)
+			continue
+		}
+		routes[route.path] = route
+	}
+
+	h.mu.Lock()
+	h.routes = routes
+	h.mu.Unlock()
+}
+
+// route returns the mapping for a request path, or nil
+func (h *hookManager) route(p string) *hookRoute {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.routes[strings.TrimSuffix(p, "/")]
+}
+
+// record adds a delivery to the log
+func (h *hookManager) record(d *store.HookDeliveryData) {
+	h.mu.Lock()
+	h.deliveries = append([]*store.HookDeliveryData{d}, h.deliveries...)
+	if len(h.deliveries) > maxHookDeliveries {
+		h.deliveries = h.deliveries[:maxHookDeliveries]
+	}
+	snapshot := *d
+	h.mu.Unlock()
+
+	h.save(&snapshot)
+}
+
+// update changes a delivery and persists it
+func (h *hookManager) update(d *store.HookDeliveryData, change func(d *store.HookDeliveryData)) {
+	h.mu.Lock()
+	change(d)
+	snapshot := *d
+	h.mu.Unlock()
+
+	h.save(&snapshot)
+}
+
+// finish marks a delivery as finished with the given status
+func (h *hookManager) finish(d *store.HookDeliveryData, status string, err error) {
+	h.update(d, func(d *store.HookDeliveryData) {
+		d.Status = status
+		if err != nil {
+			d.Error = err.Error()
+		}
+		d.FinishedAt = time.Now()
+	})
+}
+
+// spawn starts a sub-agent for a delivery. The lock is held until the agent
+// ID is recorded, so finishAgent can't miss a sub-agent that ends quickly.
+func (h *hookManager) spawn(d *store.HookDeliveryData, start func() string) {
+	h.mu.Lock()
+	d.AgentID = start()
+	d.Status = hookRunning
+	snapshot := *d
+	h.mu.Unlock()
+
+	h.save(&snapshot)
+}
+
+// finishAgent finishes the delivery that started a sub-agent, if any
+func (h *hookManager) finishAgent(agentID string, err error) {
+	h.mu.RLock()
+	var delivery *store.HookDeliveryData
+	for _, d := range h.deliveries {
+		if d.AgentID == agentID && d.FinishedAt.IsZero() {
+			delivery = d
+			break
+		}
+	}
+	h.mu.RUnlock()
+	if delivery == nil {
+		return
+	}
+
+	switch {
+	case err != nil:
+		h.finish(delivery, hookFailed, err)
+	case delivery.Target != "":
+		h.finish(delivery, hookDelivered, nil)
+	default:
+		h.finish(delivery, hookDone, nil)
+	}
+}
+
+// recent returns a copy of the delivery log, newest first
+func (h *hookManager) recent() []store.HookDeliveryData {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	deliveries := make([]store.HookDeliveryData, len(h.deliveries))
+	for i, d := range h.deliveries {
+		deliveries[i] = *d
+	}
+	return deliveries
+}
+
+func (h *hookManager) save(d *store.HookDeliveryData) {
+	if h.db == nil {
+		return
+	}
+	if err := h.db.SaveHookDelivery(d); err != nil {
+		h.log.Warn("Failed to save webhook delivery %s: %v", d.ID, err)
+	}
+}
+
+// initializeHooks sets up webhook mappings and the delivery log
+func (gw *Gateway) initializeHooks() {
+	gw.hooks = newHookManager(gw.db, gw.log)
+	gw.hooks.setMappings(gw.cfg.Hooks.Mappings)
+	if n := len(gw.cfg.Hooks.Mappings); n > 0 {
+		gw.log.Info("🪝 %d webhook mapping(s) configured", n)
+	}
+}
+
+// runHook renders a mapping's prompt, runs it through the agent and
+// delivers the result to the mapping's channel
+func (gw *Gateway) runHook(route *hookRoute, d *store.HookDeliveryData, data hookData) {
+	reqLog := gw.log.WithComponent("hooks").WithRequestID(d.ID)
+
+	prompt, err := route.render(data)
+	if err != nil {
+		reqLog.Warn("⚠️ Hook %s failed: %v", route.name, err)
+		gw.hooks.finish(d, hookFailed, err)
+		return
+	}
+
+	if route.mapping.SubAgent {
+		gw.runHookSubAgent(route, d, prompt, reqLog)
+		return
+	}
+
+	gw.hooks.update(d, func(d *store.HookDeliveryData) { d.Status = hookRunning })
+
+	gw.mu.RLock()
+	router := gw.router
+	gw.mu.RUnlock()
+	if router == nil {
+		gw.hooks.finish(d, hookFailed, fmt.Errorf("agent not configured"))
+		return
+	}
+
+	// Tools see the target session, so e.g. reminders reach the right chat
+	ch, userID := "hooks", route.name
+	if route.mapping.Channel != "" {
+		ch, userID = route.mapping.Channel, route.mapping.To
+	}
+	msg := types.Message{
+		Text:      prompt,
+		Channel:   ch,
+		From:      route.name,
+		Timestamp: time.Now(),
+		Metadata:  map[string]any{"user_id": userID, "hook": route.name},
+	}
+
+	reqLog.Info("🪝 Running hook %s (%d chars)", route.name, len(prompt))
+	reply, err := router.ProcessWithOptions([]types.Message{msg}, route.options())
+	if err != nil {
+		reqLog.Error("❌ Hook %s agent error: %v", route.name, err)
+		gw.hooks.finish(d, hookFailed, err)
+		return
+	}
+	gw.trackAPIUsage("hooks", reply)
+
+	if route.mapping.Channel == "" {
+		gw.hooks.finish(d, hookDone, nil)
+		return
+	}
+
+	// Keep the exchange in the chat's history so the user can follow up
+	stored := *reply
+	stored.Channel = ch
+	stored.ID = newReplyID()
+	gw.sessions.AddMessageAndPersist(ch, userID, msg)
+	gw.sessions.AddMessageAndPersist(ch, userID, stored)
+
+	if err := gw.notify(ch, userID, reply.Text); err != nil {
+		reqLog.Warn("⚠️ Hook %s result not delivered to %s: %v", route.name, d.Target, err)
+		gw.hooks.finish(d, hookFailed, err)
+		return
+	}
+	reqLog.Info("✅ Hook %s delivered to %s", route.name, d.Target)
+	gw.hooks.finish(d, hookDelivered, nil)
+}
+
+// runHookSubAgent runs a hook's prompt as a sub-agent; handleSubAgentComplete
+// injects the result into the target session and finishes the delivery
+func (gw *Gateway) runHookSubAgent(route *hookRoute, d *store.HookDeliveryData, prompt string, reqLog *logger.ContextLogger) {
+	if gw.subagentManager == nil {
+		gw.hooks.finish(d, hookFailed, fmt.Errorf("sub-agents are not available"))
+		return
+	}
+
+	parentKey := d.Target
+	if parentKey == "" {
+		parentKey = session.SessionKey("hooks", route.name)
+	}
+
+	runner := subagent.NewSimpleRunner(gw.createSubAgentChatFunc(), subagent.DefaultMaxIterations)
+	gw.hooks.spawn(d, func() string {
+		return gw.subagentManager.Spawn(prompt, route.name, defaultSubAgentPrompt, parentKey, runner, route.tools(gw.toolRegistry))
+	})
+	reqLog.Info("🪝 Hook %s spawned sub-agent %s", route.name, d.AgentID)
+}
+
+// Headers not passed to prompt templates
+var hookSecretHeaders = map[string]bool{"Authorization": true, "X-Api-Key": true, "X-Hook-Secret": true}
+
+// newHookData collects what a prompt template can refer to
+func newHookData(route *hookRoute, r *http.Request, payload any) hookData {
+	data := hookData{
+		Name:    route.name,
+		Path:    r.URL.Path,
+		Event:   hookEvent(r.Header),
+		Headers: make(map[string]string, len(r.Header)),
+		Query:   make(map[string]string),
+		Payload: payload,
+	}
+	for name := range r.Header {
+		if hookSecretHeaders[name] {
+			continue
+		}
+		data.Headers[name] = r.Header.Get(name)
+	}
+	for name, values := range r.URL.Query() {
+		if len(values) > 0 && name != "token" {
+			data.Query[name] = values[0]
+		}
+	}
+	return data
+}
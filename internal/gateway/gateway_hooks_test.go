@@ -0,0 +1,246 @@
+package gateway
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/FeelPulse/feelpulse/internal/config"
+	"github.com/FeelPulse/feelpulse/internal/logger"
+	"github.com/FeelPulse/feelpulse/internal/store"
+	"github.com/FeelPulse/feelpulse/internal/tools"
+)
+
+func githubSignature(secret, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifyHookSignature(t *testing.T) {
+	body := `{"action":"opened"}`
+	github := config.HookMapping{Verify: "github", Secret: "s3cret"}
+	secret := config.HookMapping{Verify: "secret", Secret: "s3cret"}
+
+	tests := []struct {
+		name    string
+		mapping config.HookMapping
+		header  string
+		value   string
+		wantErr bool
+	}{
+		{"no verification", config.HookMapping{}, "", "", false},
+		{"github valid", github, "X-Hub-Signature-256", githubSignature("s3cret", body), false},
+		{"github wrong secret", github, "X-Hub-Signature-256", githubSignature("guess", body), true},
+		{"github malformed", github, "X-Hub-Signature-256", "sha256=zz", true},
+		{"github missing", github, "", "", true},
+		{"secret valid", secret, "X-Hook-Secret", "s3cret", false},
+		{"secret wrong", secret, "X-Hook-Secret", "guess", true},
+		{"secret missing", secret, "", "", true},
+		{"github without secret", config.HookMapping{Verify: "github"}, "X-Hub-Signature-256", githubSignature("", body), true},
+		{"secret without secret", config.HookMapping{Verify: "secret"}, "X-Hook-Secret", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.header != "" {
+				header.Set(tt.header, tt.value)
+			}
+			err := verifyHookSignature(tt.mapping, header, []byte(body))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("verifyHookSignature() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHookPath(t *testing.T) {
+	tests := []struct {
+		path, want string
+	}{
+		{"github", "/hooks/github"},
+		{"/hooks/github", "/hooks/github"},
+		{"/hooks/ci/", "/hooks/ci"},
+		{" alerts ", "/hooks/alerts"},
+	}
+
+	for _, tt := range tests {
+		if got := hookPath(tt.path); got != tt.want {
+			t.Errorf("hookPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestHookRoute_Render(t *testing.T) {
+	route, err := newHookRoute(config.HookMapping{
+		Path:   "github",
+		Prompt: `{{.Event}} on {{.Payload.repository.full_name}} by {{.Headers.User}}: {{truncate 5 .Payload.title}}`,
+	})
+	if err != nil {
+		t.Fatalf("newHookRoute error: %v", err)
+	}
+	if route.name != "github" || route.path != "/hooks/github" {
+		t.Errorf("unexpected route: %s %s", route.name, route.path)
+	}
+
+	var payload any
+	_ = json.Unmarshal([]byte(`{"repository": {"full_name": "FeelPulse/feelpulse"}, "title": "Fix the parser"}`), &payload)
+
+	req := httptest.NewRequest(http.MethodPost, "/hooks/github?token=t", nil)
+	req.Header.Set("X-GitHub-Event", "issues")
+	req.Header.Set("User", "octocat")
+	req.Header.Set("Authorization", "Bearer t")
+	data := newHookData(route, req, payload)
+	if _, ok := data.Headers["Authorization"]; ok {
+		t.Error("credentials should not be passed to templates")
+	}
+	if _, ok := data.Query["token"]; ok {
+		t.Error("token should not be passed to templates")
+	}
+
+	prompt, err := route.render(data)
+	if err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	if prompt != "issues on FeelPulse/feelpulse by octocat: Fi..." {
+		t.Errorf("unexpected prompt: %q", prompt)
+	}
+
+	if _, err := newHookRoute(config.HookMapping{Path: "x", Prompt: "{{.Payload"}); err == nil {
+		t.Error("expected an error for an invalid template")
+	}
+	empty, _ := newHookRoute(config.HookMapping{Path: "x", Prompt: "{{if false}}x{{end}}"})
+	if _, err := empty.render(hookData{}); err == nil {
+		t.Error("expected an error for an empty prompt")
+	}
+}
+
+func TestHookRoute_Tools(t *testing.T) {
+	registry := tools.NewRegistry()
+	registry.Register(&tools.Tool{Name: "exec"})
+
+	route, _ := newHookRoute(config.HookMapping{Path: "ci", Prompt: "x"})
+	if !route.options().NoServerTools {
+		t.Error("server tools should be off by default")
+	}
+	if n := len(route.tools(registry).List()); n != 0 {
+		t.Errorf("sub-agent tools = %d, want 0", n)
+	}
+
+	route, _ = newHookRoute(config.HookMapping{Path: "ci", Prompt: "x", ServerTools: true})
+	if route.options().NoServerTools {
+		t.Error("serverTools should offer the server tools")
+	}
+	if route.tools(registry) != registry {
+		t.Error("serverTools should give sub-agents the tool registry")
+	}
+}
+
+func TestHandleHook(t *testing.T) {
+	cfg := config.Default()
+	cfg.Hooks.Token = "token"
+	log := logger.New(&logger.Config{Level: "error"})
+	gw := &Gateway{cfg: cfg, log: log, hooks: newHookManager(nil, log)}
+	gw.hooks.setMappings([]config.HookMapping{
+		{Path: "github", Prompt: "{{.Event}}", Verify: "github", Secret: "s3cret", Channel: "telegram", To: "42"},
+		{Path: "/hooks/ci", Name: "ci", Prompt: "Build {{.Payload.status}}"},
+		{Path: "unkeyed", Prompt: "{{.Event}}", Verify: "github"},
+	})
+
+	body := `{"status": "failed"}`
+	tests := []struct {
+		name    string
+		path    string
+		body    string
+		headers map[string]string
+		status  int
+		logged  string // Status of the recorded delivery, "" if none
+	}{
+		{"unmapped path", "/hooks/other", body, map[string]string{"Authorization": "Bearer token"}, http.StatusOK, ""},
+		{"unmapped needs token", "/hooks/other", body, nil, http.StatusUnauthorized, ""},
+		{"token mapping", "/hooks/ci", body, map[string]string{"Authorization": "Bearer token"}, http.StatusAccepted, hookFailed},
+		{"bad signature", "/hooks/github", body, map[string]string{"X-Hub-Signature-256": githubSignature("guess", body)}, http.StatusUnauthorized, hookRejected},
+		{"ping", "/hooks/github", body, map[string]string{"X-Hub-Signature-256": githubSignature("s3cret", body), "X-GitHub-Event": "ping"}, http.StatusOK, ""},
+		{"signed", "/hooks/github", body, map[string]string{"X-Hub-Signature-256": githubSignature("s3cret", body), "X-GitHub-Event": "push"}, http.StatusAccepted, hookFailed},
+		{"empty-key signature", "/hooks/unkeyed", body, map[string]string{"X-Hub-Signature-256": githubSignature("", body), "X-GitHub-Event": "push"}, http.StatusUnauthorized, ""},
+		{"invalid JSON", "/hooks/ci", "{", map[string]string{"Authorization": "Bearer token"}, http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := len(gw.hooks.recent())
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			gw.handleHook(rec, req)
+			gw.activeRequests.Wait()
+
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+
+			deliveries := gw.hooks.recent()
+			if tt.logged == "" {
+				if len(deliveries) != before {
+					t.Errorf("unexpected delivery: %+v", deliveries[0])
+				}
+				return
+			}
+			if len(deliveries) != before+1 {
+				t.Fatalf("expected a recorded delivery")
+			}
+			// Without an agent, accepted deliveries fail in the background
+			if d := deliveries[0]; d.Status != tt.logged || d.FinishedAt.IsZero() {
+				t.Errorf("unexpected delivery: %+v", d)
+			}
+			if tt.status == http.StatusAccepted && !strings.Contains(rec.Body.String(), deliveries[0].ID) {
+				t.Errorf("response should contain the delivery ID: %s", rec.Body.String())
+			}
+		})
+	}
+
+	cfg.Hooks.Enabled = false
+	rec := httptest.NewRecorder()
+	gw.handleHook(rec, httptest.NewRequest(http.MethodPost, "/hooks/ci", strings.NewReader(body)))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("disabled hooks: status = %d, want 404", rec.Code)
+	}
+}
+
+func TestHookManager_FinishAgent(t *testing.T) {
+	h := newHookManager(nil, logger.New(&logger.Config{Level: "error"}))
+
+	delivered := &store.HookDeliveryData{ID: "hook-1", Target: "telegram:42"}
+	failed := &store.HookDeliveryData{ID: "hook-2"}
+	h.record(delivered)
+	h.record(failed)
+	h.spawn(delivered, func() string { return "agent-1" })
+	h.spawn(failed, func() string { return "agent-2" })
+
+	h.finishAgent("agent-1", nil)
+	h.finishAgent("agent-2", errors.New("timeout"))
+	h.finishAgent("agent-3", nil) // Not started by a webhook
+
+	for _, d := range h.recent() {
+		switch d.ID {
+		case "hook-1":
+			if d.Status != hookDelivered || d.FinishedAt.IsZero() {
+				t.Errorf("unexpected delivery: %+v", d)
+			}
+		case "hook-2":
+			if d.Status != hookFailed || d.Error != "timeout" {
+				t.Errorf("unexpected delivery: %+v", d)
+			}
+		}
+	}
+}
@@ -0,0 +1,267 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/FeelPulse/feelpulse/internal/apikey"
+	"github.com/FeelPulse/feelpulse/internal/events"
+)
+
+const (
+	// liveClientBuffer is how many events a dashboard client can fall
+	// behind before further events are dropped for it
+	liveClientBuffer = 64
+
+	// liveStatsInterval is how often clients get fresh counters
+	liveStatsInterval = 5 * time.Second
+
+	// livePreviewLength limits message text sent to the dashboard
+	livePreviewLength = 100
+)
+
+// LiveTurn is a message the agent is working on
+type LiveTurn struct {
+	Session     string    `json:"session"`
+	Channel     string    `json:"channel"`
+	From        string    `json:"from"`
+	Preview     string    `json:"preview"`
+	Tool        string    `json:"tool,omitempty"` // Tool running right now
+	ToolSummary string    `json:"tool_summary,omitempty"`
+	Tools       int       `json:"tools"` // Tools run so far
+	StartedAt   time.Time `json:"started_at"`
+}
+
+// LiveSubAgent is a running sub-agent
+type LiveSubAgent struct {
+	ID        string    `json:"id"`
+	Label     string    `json:"label"`
+	Session   string    `json:"session"`
+	Tool      string    `json:"tool,omitempty"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+// LiveStats holds the dashboard counters
+type LiveStats struct {
+	Uptime         string `json:"uptime"`
+	UptimeSeconds  int64  `json:"uptime_seconds"`
+	ActiveSessions int    `json:"active_sessions"`
+	TotalTokens    int    `json:"total_tokens"`
+	InputTokens    int    `json:"input_tokens"`
+	OutputTokens   int    `json:"output_tokens"`
+	TotalRequests  int    `json:"total_requests"`
+}
+
+// LiveSnapshot is the first event a dashboard client receives
+type LiveSnapshot struct {
+	Turns     []LiveTurn     `json:"turns"`
+	SubAgents []LiveSubAgent `json:"subagents"`
+	Stats     LiveStats      `json:"stats"`
+}
+
+// liveEvent is one server-sent event
+type liveEvent struct {
+	name string
+	data any
+}
+
+// liveFeed tracks turns and sub-agents in progress and fans gateway events
+// out to dashboard clients
+type liveFeed struct {
+	mu        sync.Mutex
+	clients   map[chan liveEvent]struct{}
+	turns     map[string]*LiveTurn     // By session key
+	subAgents map[string]*LiveSubAgent // By ID
+}
+
+func newLiveFeed() *liveFeed {
+	return &liveFeed{
+		clients:   make(map[chan liveEvent]struct{}),
+		turns:     make(map[string]*LiveTurn),
+		subAgents: make(map[string]*LiveSubAgent),
+	}
+}
+
+// subscribe registers a client and returns its channel and the current
+// turns and sub-agents
+func (f *liveFeed) subscribe() (chan liveEvent, []LiveTurn, []LiveSubAgent, func()) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ch := make(chan liveEvent, liveClientBuffer)
+	f.clients[ch] = struct{}{}
+
+	turns := make([]LiveTurn, 0, len(f.turns))
+	for _, t := range f.turns {
+		turns = append(turns, *t)
+	}
+	sort.Slice(turns, func(i, j int) bool { return turns[i].StartedAt.Before(turns[j].StartedAt) })
+
+	agents := make([]LiveSubAgent, 0, len(f.subAgents))
+	for _, a := range f.subAgents {
+		agents = append(agents, *a)
+	}
+	sort.Slice(agents, func(i, j int) bool { return agents[i].StartedAt.Before(agents[j].StartedAt) })
+
+	return ch, turns, agents, func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		delete(f.clients, ch)
+	}
+}
+
+// broadcastLocked sends an event to every client, dropping it for clients
+// that fell behind
+func (f *liveFeed) broadcastLocked(name string, data any) {
+	for ch := range f.clients {
+		select {
+		case ch <- liveEvent{name: name, data: data}:
+		default:
+		}
+	}
+}
+
+// handle updates the feed's state for a bus event and forwards it
+func (f *liveFeed) handle(e events.Event) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch e := e.(type) {
+	case events.MessageReceived:
+		e.Text = truncatePreview(e.Text, livePreviewLength)
+		f.broadcastLocked(e.Type(), e)
+	case events.TurnStarted:
+		turn := &LiveTurn{
+			Session:   e.Session,
+			Channel:   e.Channel,
+			From:      e.From,
+			Preview:   truncatePreview(e.Text, livePreviewLength),
+			StartedAt: time.Now(),
+		}
+		f.turns[e.Session] = turn
+		f.broadcastLocked(e.Type(), *turn)
+	case events.ToolStarted:
+		if turn, ok := f.turns[e.Session]; ok {
+			turn.Tool, turn.ToolSummary = e.Tool, e.Summary
+			turn.Tools++
+		}
+		for _, a := range f.subAgents {
+			if a.Session == e.Session {
+				a.Tool = e.Tool
+			}
+		}
+		f.broadcastLocked(e.Type(), e)
+	case events.ToolCalled:
+		if turn, ok := f.turns[e.Session]; ok {
+			turn.Tool, turn.ToolSummary = "", ""
+		}
+		e.Input = nil // May hold whole files
+		f.broadcastLocked(e.Type(), e)
+	case events.ReplySent:
+		delete(f.turns, e.Session)
+		e.Text = truncatePreview(e.Text, livePreviewLength)
+		f.broadcastLocked(e.Type(), e)
+	case events.TurnFailed:
+		delete(f.turns, e.Session)
+		f.broadcastLocked(e.Type(), e)
+	case events.SubAgentStarted:
+		agent := &LiveSubAgent{ID: e.ID, Label: e.Label, Session: e.Session, StartedAt: time.Now()}
+		f.subAgents[e.ID] = agent
+		f.broadcastLocked(e.Type(), *agent)
+	case events.SubAgentCompleted:
+		delete(f.subAgents, e.ID)
+		e.Result = truncatePreview(e.Result, livePreviewLength)
+		f.broadcastLocked(e.Type(), e)
+	}
+}
+
+// liveSubscriber feeds the dashboard's event stream
+func (gw *Gateway) liveSubscriber(e events.Event) {
+	if gw.live != nil {
+		gw.live.handle(e)
+	}
+}
+
+// liveStats returns the current dashboard counters
+func (gw *Gateway) liveStats() LiveStats {
+	var stats LiveStats
+	if !gw.startTime.IsZero() {
+		stats.UptimeSeconds = int64(time.Since(gw.startTime).Seconds())
+		stats.Uptime = formatUptime(stats.UptimeSeconds)
+	}
+	if gw.sessions != nil {
+		stats.ActiveSessions = gw.sessions.Count()
+	}
+	if gw.usage != nil {
+		global := gw.usage.GetGlobal()
+		stats.TotalTokens = global.TotalTokens
+		stats.InputTokens = global.InputTokens
+		stats.OutputTokens = global.OutputTokens
+		stats.TotalRequests = global.RequestCount
+	}
+	return stats
+}
+
+// handleDashboardEvents streams gateway events to the dashboard as
+// server-sent events: a snapshot first, then each event as it happens and
+// fresh counters every few seconds
+func (gw *Gateway) handleDashboardEvents(w http.ResponseWriter, r *http.Request) {
+	r, ok := gw.authorize(w, r, apikey.ScopeAdmin)
+	if !ok {
+		return
+	}
+	if gw.live == nil {
+		http.Error(w, "Event stream not available", http.StatusServiceUnavailable)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming is not supported by the server", http.StatusInternalServerError)
+		return
+	}
+
+	// The stream outlives the server's write timeout
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	ch, turns, agents, unsubscribe := gw.live.subscribe()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // Don't let nginx buffer the stream
+	w.WriteHeader(http.StatusOK)
+
+	send := func(name string, data any) {
+		payload, err := json.Marshal(data)
+		if err != nil {
+			gw.log.Warn("Failed to encode %s event for the dashboard: %v", name, err)
+			return
+		}
+		fmt.Fprintf(w, "event: %s\ndata: %s\n\n", name, payload)
+		flusher.Flush()
+	}
+
+	fmt.Fprint(w, "retry: 3000\n\n")
+	send("snapshot", LiveSnapshot{Turns: turns, SubAgents: agents, Stats: gw.liveStats()})
+
+	ticker := time.NewTicker(liveStatsInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-gw.shutdownCh:
+			return
+		case e := <-ch:
+			send(e.name, e.data)
+		case <-ticker.C:
+			send("stats", gw.liveStats())
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package gateway
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FeelPulse/feelpulse/internal/config"
+	"github.com/FeelPulse/feelpulse/internal/events"
+	"github.com/FeelPulse/feelpulse/internal/logger"
+	"github.com/FeelPulse/feelpulse/internal/session"
+	"github.com/FeelPulse/feelpulse/internal/usage"
+)
+
+func TestLiveFeed_TracksTurns(t *testing.T) {
+	feed := newLiveFeed()
+	ch, _, _, unsubscribe := feed.subscribe()
+	defer unsubscribe()
+
+	feed.handle(events.TurnStarted{Channel: "telegram", Session: "telegram:1", From: "alice", Text: strings.Repeat("a", 500)})
+	feed.handle(events.ToolStarted{Session: "telegram:1", Tool: "web_search", Summary: "weather"})
+	feed.handle(events.SubAgentStarted{ID: "sa-1", Label: "research", Session: "telegram:2"})
+	feed.handle(events.ToolCalled{Session: "telegram:3", Tool: "file_write", Input: map[string]any{"content": "secret"}})
+
+	_, turns, agents, stop := feed.subscribe()
+	stop()
+	if len(turns) != 1 || turns[0].Tool != "web_search" || turns[0].Tools != 1 || len(turns[0].Preview) > livePreviewLength+3 {
+		t.Errorf("unexpected turns: %+v", turns)
+	}
+	if len(agents) != 1 || agents[0].ID != "sa-1" {
+		t.Errorf("unexpected sub-agents: %+v", agents)
+	}
+
+	feed.handle(events.ReplySent{Session: "telegram:1", Text: "done"})
+	feed.handle(events.SubAgentCompleted{ID: "sa-1", Session: "telegram:2"})
+	_, turns, agents, stop = feed.subscribe()
+	stop()
+	if len(turns) != 0 || len(agents) != 0 {
+		t.Errorf("finished work should be removed: %+v %+v", turns, agents)
+	}
+
+	var got []string
+	for len(ch) > 0 {
+		e := <-ch
+		got = append(got, e.name)
+		if called, ok := e.data.(events.ToolCalled); ok && called.Input != nil {
+			t.Error("tool input should not be streamed")
+		}
+	}
+	want := "turn.started tool.started subagent.started tool.called reply.sent subagent.completed"
+	if strings.Join(got, " ") != want {
+		t.Errorf("events = %v, want %s", got, want)
+	}
+}
+
+func TestLiveFeed_DropsForSlowClients(t *testing.T) {
+	feed := newLiveFeed()
+	ch, _, _, unsubscribe := feed.subscribe()
+	defer unsubscribe()
+
+	for i := 0; i < liveClientBuffer*2; i++ {
+		feed.handle(events.TurnFailed{Session: "telegram:1", Error: "boom"})
+	}
+	if len(ch) != liveClientBuffer {
+		t.Errorf("queued %d events, want %d", len(ch), liveClientBuffer)
+	}
+}
+
+func TestDashboardEvents_Stream(t *testing.T) {
+	log := logger.New(&logger.Config{Level: "error"})
+	gw := &Gateway{
+		cfg:       config.Default(),
+		mux:       http.NewServeMux(),
+		log:       log,
+		sessions:  session.NewStore(),
+		usage:     usage.NewTracker(),
+		events:    events.New(log),
+		live:      newLiveFeed(),
+		startTime: time.Now(),
+	}
+	gw.subscribeEvents()
+	gw.mux.HandleFunc("GET /dashboard/events", gw.handleDashboardEvents)
+	gw.usage.Record("telegram", "1", 100, 20, "claude")
+
+	server := httptest.NewServer(gw.mux)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/dashboard/events", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %s", ct)
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	next := func() (string, string) {
+		t.Helper()
+		var name, data string
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				t.Fatalf("stream ended: %v", err)
+			}
+			line = strings.TrimSpace(line)
+			switch {
+			case strings.HasPrefix(line, "event: "):
+				name = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				data = strings.TrimPrefix(line, "data: ")
+			case line == "" && name != "":
+				return name, data
+			}
+		}
+	}
+
+	name, data := next()
+	var snapshot LiveSnapshot
+	if err := json.Unmarshal([]byte(data), &snapshot); name != "snapshot" || err != nil {
+		t.Fatalf("first event = %s %s", name, data)
+	}
+	if snapshot.Stats.TotalTokens != 120 || snapshot.Stats.TotalRequests != 1 {
+		t.Errorf("unexpected stats: %+v", snapshot.Stats)
+	}
+
+	gw.publish(events.TurnStarted{Channel: "telegram", Session: "telegram:1", From: "alice", Text: "hi"})
+	gw.publish(events.ToolStarted{Session: "telegram:1", Tool: "exec", Summary: "ls"})
+
+	if name, data := next(); name != "turn.started" || !strings.Contains(data, `"from":"alice"`) {
+		t.Errorf("unexpected event: %s %s", name, data)
+	}
+	if name, data := next(); name != "tool.started" || !strings.Contains(data, `"tool":"exec"`) {
+		t.Errorf("unexpected event: %s %s", name, data)
+	}
+}
@@ -0,0 +1,96 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/FeelPulse/feelpulse/internal/channel"
+	"github.com/FeelPulse/feelpulse/internal/session"
+	"github.com/FeelPulse/feelpulse/internal/tools"
+	"github.com/FeelPulse/feelpulse/pkg/types"
+)
+
+// initializeLocation registers the user_location tool and, if enabled,
+// live-location tracking for the Telegram bot
+func (gw *Gateway) initializeLocation(telegram *channel.TelegramBot) {
+	if gw.cfg.Channels.Telegram.LiveLocation {
+		telegram.SetLiveLocation(gw)
+	}
+
+	if gw.toolRegistry == nil {
+		return
+	}
+	gw.toolRegistry.Register(&tools.Tool{
+		Name:        "user_location",
+		Description: "Get the location the user last shared in this conversation (coordinates, venue, accuracy and age). Use it for location-aware requests such as nearby places, weather or directions.",
+		Parameters:  []tools.Parameter{},
+		Handler: func(ctx context.Context, params map[string]any) (string, error) {
+			key, _ := ctx.Value("session_key").(string)
+			if sess, ok := gw.sessions.GetSession(key); ok {
+				if loc := sess.GetLocation(); loc != nil {
+					return formatLocation(loc, time.Now()), nil
+				}
+			}
+			return "No location shared yet. Ask the user to share their location from Telegram's attachment menu.", nil
+		},
+	})
+}
+
+// UpdateLocation implements channel.LocationUpdater for live locations
+func (gw *Gateway) UpdateLocation(msg *types.Message) {
+	loc, ok := locationFromMetadata(msg)
+	if !ok {
+		return
+	}
+	loc.Live = true
+
+	userID := gw.getUserID(msg)
+	gw.sessions.GetOrCreate(msg.Channel, userID).SetLocation(loc)
+}
+
+// locationFromMetadata reads the location attached to a message by the channel
+func locationFromMetadata(msg *types.Message) (session.Location, bool) {
+	lat, okLat := msg.Metadata["latitude"].(float64)
+	lon, okLon := msg.Metadata["longitude"].(float64)
+	if !okLat || !okLon {
+		return session.Location{}, false
+	}
+
+	loc := session.Location{
+		Latitude:  lat,
+		Longitude: lon,
+		UpdatedAt: time.Now(),
+	}
+	loc.Accuracy, _ = msg.Metadata["location_accuracy"].(float64)
+	loc.Title, _ = msg.Metadata["venue_title"].(string)
+	loc.Address, _ = msg.Metadata["venue_address"].(string)
+	_, loc.Live = msg.Metadata["live_period"]
+	return loc, true
+}
+
+// formatLocation describes a stored location for the agent
+func formatLocation(loc *session.Location, now time.Time) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Latitude: %.6f\nLongitude: %.6f\n", loc.Latitude, loc.Longitude))
+	if loc.Title != "" {
+		sb.WriteString(fmt.Sprintf("Venue: %s\n", loc.Title))
+	}
+	if loc.Address != "" {
+		sb.WriteString(fmt.Sprintf("Address: %s\n", loc.Address))
+	}
+	if loc.Accuracy > 0 {
+		sb.WriteString(fmt.Sprintf("Accuracy: ±%.0f m\n", loc.Accuracy))
+	}
+
+	if age := now.Sub(loc.UpdatedAt); age < time.Minute {
+		sb.WriteString("Updated: just now")
+	} else {
+		sb.WriteString(fmt.Sprintf("Updated: %s ago", formatUptime(int64(age.Seconds()))))
+	}
+	if loc.Live {
+		sb.WriteString(" (live location)")
+	}
+	return sb.String()
+}
@@ -0,0 +1,410 @@
+package gateway
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"html/template"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/FeelPulse/feelpulse/internal/apikey"
+	"github.com/FeelPulse/feelpulse/internal/ratelimit"
+	"github.com/FeelPulse/feelpulse/internal/store"
+	"github.com/FeelPulse/feelpulse/internal/webauth"
+)
+
+// loginAttemptsPerMinute limits password guesses per client address
+const loginAttemptsPerMinute = 5
+
+// initializeDashboardLogin enables dashboard logins, persisted when the
+// database is available
+func (gw *Gateway) initializeDashboardLogin() {
+	gw.logins = webauth.NewManager()
+	gw.loginLimiter = ratelimit.New(loginAttemptsPerMinute)
+
+	if gw.db == nil {
+		return
+	}
+	if err := gw.db.EnsureDashboardSessionsTable(); err != nil {
+		gw.log.Warn("Failed to create dashboard_sessions table: %v", err)
+		return
+	}
+	if err := gw.logins.SetPersister(&dashboardSessionPersister{db: gw.db}); err != nil {
+		gw.log.Warn("Failed to load dashboard sessions: %v", err)
+	}
+}
+
+func (gw *Gateway) setupLoginRoutes() {
+	gw.mux.HandleFunc("GET /dashboard/login", gw.handleLoginPage)
+	gw.mux.HandleFunc("POST /dashboard/login", gw.handleLogin)
+	gw.mux.HandleFunc("POST /dashboard/logout", gw.handleLogout)
+}
+
+// passwordLoginConfigured reports whether a dashboard password is set
+func (gw *Gateway) passwordLoginConfigured() bool {
+	return gw.cfg.Dashboard.Password != "" || gw.cfg.Dashboard.ViewerPassword != ""
+}
+
+// authRequired reports whether requests for a scope need credentials. A
+// dashboard password only protects the dashboard and its APIs.
+func (gw *Gateway) authRequired(scope string) bool {
+	if gw.authConfigured() {
+		return true
+	}
+	return (scope == apikey.ScopeAdmin || scope == apikey.ScopeConfig) && gw.passwordLoginConfigured()
+}
+
+// dashboardSession returns the login of a request's session cookie, or nil
+func (gw *Gateway) dashboardSession(r *http.Request) *webauth.Session {
+	if gw.logins == nil {
+		return nil
+	}
+	cookie, err := r.Cookie(webauth.CookieName)
+	if err != nil {
+		return nil
+	}
+	return gw.logins.Get(cookie.Value)
+}
+
+// authorizeDashboardSession checks a request made with a session cookie.
+// Viewers may only read, and mutating requests must carry the session's
+// CSRF token.
+func (gw *Gateway) authorizeDashboardSession(w http.ResponseWriter, r *http.Request, sess *webauth.Session) (*http.Request, bool) {
+	if !isSafeMethod(r.Method) {
+		if sess.ReadOnly() {
+			http.Error(w, "Forbidden: read-only login", http.StatusForbidden)
+			return r, false
+		}
+		if !webauth.CheckCSRF(sess, r.Header.Get(webauth.CSRFHeader)) {
+			gw.log.Warn("🔒 Rejected %s %s without a valid CSRF token", r.Method, r.URL.Path)
+			http.Error(w, "Forbidden: invalid CSRF token", http.StatusForbidden)
+			return r, false
+		}
+	}
+	return r.WithContext(webauth.NewContext(r.Context(), sess)), true
+}
+
+// denyViewers wraps an admin endpoint that exposes secrets or whole
+// conversations, so viewer logins are refused even for reads. It runs after
+// authorize, which puts the login in the request context.
+func denyViewers(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if sess := webauth.FromContext(r.Context()); sess != nil && sess.ReadOnly() {
+			http.Error(w, "Forbidden: read-only login", http.StatusForbidden)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// redirectToLogin sends a browser without credentials to the login page
+func redirectToLogin(w http.ResponseWriter, r *http.Request) {
+	http.Redirect(w, r, "/dashboard/login?next="+url.QueryEscape(r.URL.Path), http.StatusSeeOther)
+}
+
+func isSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// isDashboardPage reports whether a request opens a dashboard page in a browser
+func isDashboardPage(r *http.Request) bool {
+	return r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/dashboard")
+}
+
+// LoginPageData holds data for the login page template
+type LoginPageData struct {
+	Code     string // Login link code to confirm
+	Next     string
+	Error    string
+	Password bool // Password login is configured
+}
+
+func (gw *Gateway) handleLoginPage(w http.ResponseWriter, r *http.Request) {
+	if !gw.authRequired(apikey.ScopeAdmin) || gw.dashboardSession(r) != nil {
+		http.Redirect(w, r, loginRedirect(r.URL.Query().Get("next")), http.StatusSeeOther)
+		return
+	}
+
+	// Login links only log in after a click on the page, so link previews
+	// can't use them up
+	gw.renderLoginPage(w, http.StatusOK, LoginPageData{
+		Code:     r.URL.Query().Get("code"),
+		Next:     r.URL.Query().Get("next"),
+		Password: gw.passwordLoginConfigured(),
+	})
+}
+
+func (gw *Gateway) handleLogin(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, 4096)
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form", http.StatusBadRequest)
+		return
+	}
+	data := LoginPageData{Next: r.PostFormValue("next"), Password: gw.passwordLoginConfigured()}
+
+	if !gw.loginLimiter.Allow(clientAddress(r)) {
+		gw.log.Warn("🔒 Dashboard login rate limited for %s", clientAddress(r))
+		data.Error = "Too many attempts. Try again in a minute."
+		gw.renderLoginPage(w, http.StatusTooManyRequests, data)
+		return
+	}
+
+	role, label := gw.checkLogin(r.PostFormValue("password"), r.PostFormValue("code"))
+	if role == "" {
+		gw.log.Warn("🔒 Dashboard login failed from %s", clientAddress(r))
+		data.Error = "Invalid password or login link."
+		if r.PostFormValue("code") != "" {
+			data.Error = "This login link is invalid or has expired. Send /admin login for a new one."
+		}
+		gw.renderLoginPage(w, http.StatusUnauthorized, data)
+		return
+	}
+
+	token, sess, err := gw.logins.Login(role, label, gw.loginTTL())
+	if err != nil {
+		gw.log.Error("Dashboard login failed: %v", err)
+		http.Error(w, "Login failed", http.StatusInternalServerError)
+		return
+	}
+	gw.log.Info("🔓 Dashboard login (%s, %s) from %s", sess.Role, sess.Label, clientAddress(r))
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     webauth.CookieName,
+		Value:    token,
+		Path:     "/",
+		Expires:  sess.ExpiresAt,
+		HttpOnly: true,
+		Secure:   gw.secureCookies(r),
+		SameSite: http.SameSiteStrictMode,
+	})
+	http.Redirect(w, r, loginRedirect(data.Next), http.StatusSeeOther)
+}
+
+// checkLogin returns the role and label of valid login credentials: a
+// dashboard password, a login link, or an admin API key or hooks token
+func (gw *Gateway) checkLogin(password, code string) (role, label string) {
+	if code != "" {
+		if gw.logins.RedeemLoginLink(code) {
+			return webauth.RoleAdmin, "login link"
+		}
+		return "", ""
+	}
+	if password == "" {
+		return "", ""
+	}
+
+	dash := gw.cfg.Dashboard
+	switch {
+	case secretEqual(password, dash.Password):
+		return webauth.RoleAdmin, "password"
+	case secretEqual(password, dash.ViewerPassword):
+		return webauth.RoleViewer, "viewer password"
+	case secretEqual(password, gw.cfg.Hooks.Token):
+		return webauth.RoleAdmin, "hooks token"
+	}
+
+	if gw.apiKeys != nil {
+		if key, err := gw.apiKeys.Authenticate(password); err == nil && key.HasScope(apikey.ScopeAdmin) {
+			return webauth.RoleAdmin, "API key " + key.Prefix
+		}
+	}
+	return "", ""
+}
+
+func (gw *Gateway) handleLogout(w http.ResponseWriter, r *http.Request) {
+	cookie, err := r.Cookie(webauth.CookieName)
+	if err == nil {
+		sess := gw.logins.Get(cookie.Value)
+		csrf := r.Header.Get(webauth.CSRFHeader)
+		if csrf == "" {
+			csrf = r.PostFormValue("csrf_token")
+		}
+		if sess != nil && !webauth.CheckCSRF(sess, csrf) {
+			http.Error(w, "Forbidden: invalid CSRF token", http.StatusForbidden)
+			return
+		}
+		gw.logins.Logout(cookie.Value)
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     webauth.CookieName,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   gw.secureCookies(r),
+		SameSite: http.SameSiteStrictMode,
+	})
+	http.Redirect(w, r, "/dashboard/login", http.StatusSeeOther)
+}
+
+// DashboardLoginLink creates a single-use admin login link for /admin login
+func (gw *Gateway) DashboardLoginLink() (string, time.Time, error) {
+	code, err := gw.logins.NewLoginLink()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	gw.log.Info("🔗 Dashboard login link created")
+	return gw.dashboardURL() + "/dashboard/login?code=" + code, time.Now().Add(webauth.LoginLinkTTL), nil
+}
+
+// dashboardURL returns the address the dashboard is reached at
+func (gw *Gateway) dashboardURL() string {
+	if u := gw.cfg.Dashboard.URL; u != "" {
+		return strings.TrimSuffix(u, "/")
+	}
+	host := gw.cfg.Gateway.Bind
+	if host == "" || host == "0.0.0.0" {
+		host = "localhost"
+	}
+	return fmt.Sprintf("http://%s:%d", host, gw.cfg.Gateway.Port)
+}
+
+func (gw *Gateway) loginTTL() time.Duration {
+	if h := gw.cfg.Dashboard.SessionHours; h > 0 {
+		return time.Duration(h) * time.Hour
+	}
+	return webauth.DefaultSessionTTL
+}
+
+// secureCookies reports whether the dashboard is served over HTTPS
+func (gw *Gateway) secureCookies(r *http.Request) bool {
+	return r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" ||
+		strings.HasPrefix(gw.cfg.Dashboard.URL, "https://")
+}
+
+// loginRedirect returns where to go after logging in; only dashboard pages
+func loginRedirect(next string) string {
+	if next == "/dashboard" || (strings.HasPrefix(next, "/dashboard/") && !strings.HasPrefix(next, "/dashboard/login")) {
+		return next
+	}
+	return "/dashboard"
+}
+
+func clientAddress(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+func secretEqual(given, secret string) bool {
+	return secret != "" && subtle.ConstantTimeCompare([]byte(given), []byte(secret)) == 1
+}
+
+func (gw *Gateway) renderLoginPage(w http.ResponseWriter, status int, data LoginPageData) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	if err := loginPageTemplate.Execute(w, data); err != nil {
+		gw.log.Error("Failed to render login page: %v", err)
+	}
+}
+
+var loginPageTemplate = template.Must(template.New("login").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>FeelPulse Login</title>
+    <style>
+        * { box-sizing: border-box; margin: 0; padding: 0; }
+        body {
+            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif;
+            background: linear-gradient(135deg, #1a1a2e 0%, #16213e 100%);
+            color: #eee;
+            min-height: 100vh;
+            display: flex;
+            align-items: center;
+            justify-content: center;
+        }
+        .card {
+            background: rgba(255,255,255,0.05);
+            border-radius: 12px;
+            padding: 2rem;
+            border: 1px solid rgba(255,255,255,0.1);
+            width: 340px;
+        }
+        h1 { font-size: 1.5rem; font-weight: 600; margin-bottom: 1.5rem; }
+        input {
+            width: 100%;
+            background: rgba(0,0,0,0.3);
+            border: 1px solid rgba(255,255,255,0.2);
+            border-radius: 6px;
+            color: #eee;
+            padding: 0.6rem;
+            margin-bottom: 1rem;
+        }
+        button {
+            width: 100%;
+            background: #166534;
+            border: none;
+            border-radius: 6px;
+            color: #fff;
+            padding: 0.6rem;
+            font-size: 0.95rem;
+            cursor: pointer;
+        }
+        .error { color: #f87171; font-size: 0.85rem; margin-bottom: 1rem; }
+        .hint { color: #888; font-size: 0.8rem; margin-top: 1rem; }
+    </style>
+</head>
+<body>
+    <div class="card">
+        <h1>🫀 FeelPulse</h1>
+        {{if .Error}}<div class="error">{{.Error}}</div>{{end}}
+        <form method="POST" action="/dashboard/login">
+            <input type="hidden" name="next" value="{{.Next}}">
+            {{if .Code}}
+            <input type="hidden" name="code" value="{{.Code}}">
+            <button type="submit">Sign in with login link</button>
+            {{else}}
+            <input type="password" name="password" placeholder="{{if .Password}}Password or admin API key{{else}}Admin API key{{end}}" autofocus required>
+            <button type="submit">Sign in</button>
+            {{end}}
+        </form>
+        <div class="hint">Or send /admin login to the bot for a single-use link.</div>
+    </div>
+</body>
+</html>`))
+
+// dashboardSessionPersister wraps SQLiteStore to implement webauth.Persister
+type dashboardSessionPersister struct {
+	db *store.SQLiteStore
+}
+
+func (p *dashboardSessionPersister) SaveSession(s *webauth.Session) error {
+	return p.db.SaveDashboardSession(&store.DashboardSessionData{
+		ID:        s.ID,
+		Role:      s.Role,
+		Label:     s.Label,
+		CSRFToken: s.CSRFToken,
+		CreatedAt: s.CreatedAt,
+		ExpiresAt: s.ExpiresAt,
+	})
+}
+
+func (p *dashboardSessionPersister) LoadSessions(now time.Time) ([]*webauth.Session, error) {
+	data, err := p.db.LoadDashboardSessions(now)
+	if err != nil {
+		return nil, err
+	}
+	sessions := make([]*webauth.Session, 0, len(data))
+	for _, d := range data {
+		sessions = append(sessions, &webauth.Session{
+			ID:        d.ID,
+			Role:      d.Role,
+			Label:     d.Label,
+			CSRFToken: d.CSRFToken,
+			CreatedAt: d.CreatedAt,
+			ExpiresAt: d.ExpiresAt,
+		})
+	}
+	return sessions, nil
+}
+
+func (p *dashboardSessionPersister) DeleteSession(id string) error {
+	return p.db.DeleteDashboardSession(id)
+}
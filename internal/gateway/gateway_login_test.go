@@ -0,0 +1,172 @@
+package gateway
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/FeelPulse/feelpulse/internal/session"
+	"github.com/FeelPulse/feelpulse/internal/webauth"
+)
+
+func newLoginGateway(t *testing.T) *Gateway {
+	t.Helper()
+
+	gw := newAPIKeyGateway(t)
+	gw.sessions = session.NewStore()
+	gw.cfg.Dashboard.Password = "admin-pass"
+	gw.cfg.Dashboard.ViewerPassword = "viewer-pass"
+	gw.initializeDashboardLogin()
+	gw.setupLoginRoutes()
+	gw.setupSessionRoutes()
+	gw.mux.HandleFunc("/dashboard", gw.handleDashboard)
+	return gw
+}
+
+// login posts a login form and returns the session cookie, nil on failure
+func login(t *testing.T, gw *Gateway, form url.Values) (*http.Cookie, int) {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/dashboard/login", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	gw.mux.ServeHTTP(rec, req)
+	for _, c := range rec.Result().Cookies() {
+		if c.Name == webauth.CookieName {
+			return c, rec.Code
+		}
+	}
+	return nil, rec.Code
+}
+
+func TestDashboardLogin(t *testing.T) {
+	gw := newLoginGateway(t)
+
+	if cookie, code := login(t, gw, url.Values{"password": {"wrong"}}); cookie != nil || code != http.StatusUnauthorized {
+		t.Errorf("wrong password: cookie = %v, status = %d", cookie, code)
+	}
+
+	admin, code := login(t, gw, url.Values{"password": {"admin-pass"}, "next": {"/dashboard/config"}})
+	if admin == nil || code != http.StatusSeeOther {
+		t.Fatalf("admin login failed: status = %d", code)
+	}
+	if !admin.HttpOnly || admin.SameSite != http.SameSiteStrictMode {
+		t.Errorf("session cookie should be HttpOnly and SameSite=Strict: %+v", admin)
+	}
+	viewer, _ := login(t, gw, url.Values{"password": {"viewer-pass"}})
+	if viewer == nil {
+		t.Fatal("viewer login failed")
+	}
+	adminCSRF := gw.logins.Get(admin.Value).CSRFToken
+	viewerCSRF := gw.logins.Get(viewer.Value).CSRFToken
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		cookie *http.Cookie
+		csrf   string
+		want   int
+	}{
+		{"dashboard without login", "GET", "/dashboard", nil, "", 303},
+		{"password leaves chat open", "GET", "/v1/models", nil, "", 200},
+		{"dashboard as admin", "GET", "/dashboard", admin, "", 200},
+		{"dashboard as viewer", "GET", "/dashboard", viewer, "", 200},
+		{"list keys as viewer", "GET", "/api/keys", viewer, "", 403},
+		{"list keys as admin", "GET", "/api/keys", admin, "", 200},
+		{"export session as viewer", "GET", "/api/sessions/telegram:1/export", viewer, "", 403},
+		{"session messages as viewer", "GET", "/api/sessions/telegram:1/messages", viewer, "", 403},
+		{"list sessions as viewer", "GET", "/api/sessions", viewer, "", 200},
+		{"create key without CSRF", "POST", "/api/keys", admin, "", 403},
+		{"create key with wrong CSRF", "POST", "/api/keys", admin, viewerCSRF, 403},
+		{"create key as viewer", "POST", "/api/keys", viewer, viewerCSRF, 403},
+		{"create key as admin", "POST", "/api/keys", admin, adminCSRF, 201},
+		{"chat API ignores the cookie", "GET", "/v1/models", admin, "", 401},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(`{"name": "ci", "scopes": ["chat"]}`))
+			if tt.cookie != nil {
+				req.AddCookie(tt.cookie)
+			}
+			if tt.csrf != "" {
+				req.Header.Set(webauth.CSRFHeader, tt.csrf)
+			}
+			rec := httptest.NewRecorder()
+			gw.mux.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestDashboardLogin_LinkAndLogout(t *testing.T) {
+	gw := newLoginGateway(t)
+
+	link, _, err := gw.DashboardLoginLink()
+	if err != nil {
+		t.Fatalf("DashboardLoginLink error: %v", err)
+	}
+	if !strings.HasPrefix(link, "http://localhost:18789/dashboard/login?code=") {
+		t.Errorf("unexpected link: %s", link)
+	}
+	code := strings.TrimPrefix(link, "http://localhost:18789/dashboard/login?code=")
+
+	// Opening the link only shows a confirmation, so previews can't use it up
+	rec := httptest.NewRecorder()
+	gw.mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/dashboard/login?code="+code, nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), code) {
+		t.Errorf("link page: status = %d", rec.Code)
+	}
+
+	cookie, _ := login(t, gw, url.Values{"code": {code}})
+	if cookie == nil {
+		t.Fatal("login link rejected")
+	}
+	if again, status := login(t, gw, url.Values{"code": {code}}); again != nil || status != http.StatusUnauthorized {
+		t.Error("login link should be single-use")
+	}
+	sess := gw.logins.Get(cookie.Value)
+	if sess == nil || sess.ReadOnly() {
+		t.Fatalf("login link should log in as admin: %+v", sess)
+	}
+
+	logout := func(csrf string) int {
+		req := httptest.NewRequest(http.MethodPost, "/dashboard/logout", strings.NewReader("csrf_token="+csrf))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.AddCookie(cookie)
+		rec := httptest.NewRecorder()
+		gw.mux.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	if status := logout("forged"); status != http.StatusForbidden || gw.logins.Get(cookie.Value) == nil {
+		t.Error("logout without the CSRF token should be rejected")
+	}
+	if status := logout(sess.CSRFToken); status != http.StatusSeeOther || gw.logins.Get(cookie.Value) != nil {
+		t.Error("logout should end the session")
+	}
+}
+
+func TestLoginRedirect(t *testing.T) {
+	tests := []struct {
+		next string
+		want string
+	}{
+		{"", "/dashboard"},
+		{"/dashboard/config", "/dashboard/config"},
+		{"https://evil.example/dashboard", "/dashboard"},
+		{"//evil.example", "/dashboard"},
+		{"/dashboard/login", "/dashboard"},
+		{"/dashboardx", "/dashboard"},
+	}
+
+	for _, tt := range tests {
+		if got := loginRedirect(tt.next); got != tt.want {
+			t.Errorf("loginRedirect(%q) = %q, want %q", tt.next, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package gateway
+
+import (
+	"fmt"
+
+	"github.com/FeelPulse/feelpulse/internal/channel"
+	"github.com/FeelPulse/feelpulse/internal/events"
+	"github.com/FeelPulse/feelpulse/internal/scheduler"
+	"github.com/FeelPulse/feelpulse/internal/store"
+)
+
+// notify sends a proactive message (reminder, heartbeat, sub-agent result) to
+// a session's chat. Telegram messages go through the bot's outbound queue, so
+// they are rate limited and ordered with regular replies.
+func (gw *Gateway) notify(ch, userID, text string) error {
+	if ch != "telegram" {
+		return fmt.Errorf("notifications are not supported on %s", ch)
+	}
+
+	gw.mu.RLock()
+	telegram := gw.telegram
+	gw.mu.RUnlock()
+	if telegram == nil {
+		return fmt.Errorf("telegram bot is not running")
+	}
+
+	target, ok := channel.ParseTarget(userID)
+	if !ok {
+		return fmt.Errorf("invalid telegram chat: %s", userID)
+	}
+
+	for _, part := range channel.SplitLongMessage(text, channel.SafeMessageLength) {
+		if _, err := telegram.SendMessageTo(target, part, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// initializeScheduler sets up reminders, persisted in SQLite when available
+func (gw *Gateway) initializeScheduler() {
+	gw.scheduler = scheduler.New()
+
+	if gw.db != nil {
+		if err := gw.db.EnsureRemindersTable(); err != nil {
+			gw.log.Warn("Failed to create reminders table: %v", err)
+		} else if err := gw.scheduler.SetPersister(&reminderPersisterAdapter{db: gw.db}); err != nil {
+			gw.log.Warn("Failed to load reminders: %v", err)
+		}
+	}
+
+	gw.scheduler.SetHandler(func(r *scheduler.Reminder) {
+		gw.publish(events.ReminderFired{ID: r.ID, Channel: r.Channel, UserID: r.UserID, Message: r.Message})
+		if err := gw.notify(r.Channel, r.UserID, "⏰ *Reminder:* "+r.Message); err != nil {
+			gw.log.Warn("Failed to send reminder %s: %v", r.ID, err)
+			return
+		}
+		gw.log.Debug("⏰ Reminder %s sent to %s:%s", r.ID, r.Channel, r.UserID)
+	})
+
+	gw.scheduler.Start()
+}
+
+// reminderPersisterAdapter wraps SQLiteStore to implement scheduler.ReminderPersister
+type reminderPersisterAdapter struct {
+	db *store.SQLiteStore
+}
+
+func (a *reminderPersisterAdapter) SaveReminder(r *scheduler.ReminderData) error {
+	return a.db.SaveReminder(&store.ReminderData{
+		ID:      r.ID,
+		Channel: r.Channel,
+		UserID:  r.UserID,
+		Message: r.Message,
+		FireAt:  r.FireAt,
+		Created: r.Created,
+	})
+}
+
+func (a *reminderPersisterAdapter) DeleteReminder(id string) error {
+	return a.db.DeleteReminder(id)
+}
+
+func (a *reminderPersisterAdapter) LoadReminders() ([]*scheduler.ReminderData, error) {
+	dbReminders, err := a.db.LoadReminders()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*scheduler.ReminderData, len(dbReminders))
+	for i, r := range dbReminders {
+		result[i] = &scheduler.ReminderData{
+			ID:      r.ID,
+			Channel: r.Channel,
+			UserID:  r.UserID,
+			Message: r.Message,
+			FireAt:  r.FireAt,
+			Created: r.Created,
+		}
+	}
+	return result, nil
+}
@@ -8,12 +8,34 @@ import (
 	"time"
 
 	"github.com/FeelPulse/feelpulse/internal/agent"
+	"github.com/FeelPulse/feelpulse/internal/events"
 	"github.com/FeelPulse/feelpulse/internal/store"
 	"github.com/FeelPulse/feelpulse/internal/subagent"
 	"github.com/FeelPulse/feelpulse/internal/tools"
 	"github.com/FeelPulse/feelpulse/pkg/types"
 )
 
+// defaultSubAgentPrompt is the system prompt for sub-agents spawned without one
+const defaultSubAgentPrompt = `You are a sub-agent executing a focused task.
+
+AVAILABLE TOOLS:
+- bash: Run ANY shell command (git, python3, npm, grep, etc.)
+- file_read/file_write/file_list: File operations
+- web_search: Search the web (use sparingly, prefer reading actual files)
+- read_skill: Load skill documentation (github, clawhub, weather, etc.)
+
+BE DECISIVE AND ACTION-ORIENTED:
+- To clone a repo: use bash git clone (or check if github skill provides better commands)
+- To run a script: use bash python3 script.py or bash ./script.sh
+- To explore code: use bash grep -r "keyword" instead of reading files one by one
+- When you see platform-specific tasks (GitHub, weather, etc.): use read_skill FIRST
+- Don't web search for basic commands - just use bash
+- Don't create workaround scripts - use tools directly
+- Execute first, analyze results after
+- Complete the task in 5-10 tool calls, not 20
+
+Follow the same guidelines as the main agent, but stay laser-focused on your assigned task.`
+
 func (gw *Gateway) initializeSubAgents() {
 	if gw.subagentManager == nil {
 		return
@@ -26,6 +48,9 @@ func (gw *Gateway) initializeSubAgents() {
 
 	// Create a new manager with the callback (replace the placeholder one)
 	gw.subagentManager = subagent.NewManager(onComplete)
+	gw.subagentManager.SetOnStart(func(agentID, label, task, parentSessionKey string) {
+		gw.publish(events.SubAgentStarted{ID: agentID, Label: label, Session: parentSessionKey, Task: task})
+	})
 
 	// Wire up persistence via adapter
 	if gw.db != nil {
@@ -84,25 +109,7 @@ func (gw *Gateway) registerSubAgentTools() {
 
 			// Use default sub-agent system prompt if not provided
 			if systemPrompt == "" {
-				systemPrompt = `You are a sub-agent executing a focused task.
-
-AVAILABLE TOOLS:
-- bash: Run ANY shell command (git, python3, npm, grep, etc.)
-- file_read/file_write/file_list: File operations
-- web_search: Search the web (use sparingly, prefer reading actual files)
-- read_skill: Load skill documentation (github, clawhub, weather, etc.)
-
-BE DECISIVE AND ACTION-ORIENTED:
-- To clone a repo: use bash git clone (or check if github skill provides better commands)
-- To run a script: use bash python3 script.py or bash ./script.sh
-- To explore code: use bash grep -r "keyword" instead of reading files one by one
-- When you see platform-specific tasks (GitHub, weather, etc.): use read_skill FIRST
-- Don't web search for basic commands - just use bash
-- Don't create workaround scripts - use tools directly
-- Execute first, analyze results after
-- Complete the task in 5-10 tool calls, not 20
-
-Follow the same guidelines as the main agent, but stay laser-focused on your assigned task.`
+				systemPrompt = defaultSubAgentPrompt
 			}
 
 			// Get parent session key from context if available, otherwise use default
@@ -259,7 +266,9 @@ func (gw *Gateway) createSubAgentChatFunc() subagent.ChatWithToolsFunc {
 			ctx = context.WithValue(ctx, "session_key", sessionKey)
 			gw.log.Debug("🤖 Executing sub-agent tool '%s' with context session_key=%s", name, sessionKey)
 			
+			gw.publish(events.ToolStarted{Session: sessionKey, Tool: name, Summary: agent.DescribeToolCall(name, input)})
 			result, err := tool.Handler(ctx, input)
+			gw.publishToolCalled(sessionKey, name, input, err)
 			if err != nil {
 				gw.log.Debug("🤖 Sub-agent tool '%s' failed: %v", name, err)
 			} else {
@@ -278,6 +287,20 @@ func (gw *Gateway) handleSubAgentComplete(agentID, label, result, parentSessionK
 	gw.log.Info("🤖 Sub-agent '%s' (%s) completed in %s", label, agentID, formatDuration(duration))
 	gw.log.Debug("🤖 Parent session key: %s", parentSessionKey)
 
+	// Webhook deliveries handled by this sub-agent finish via the event bus
+	event := events.SubAgentCompleted{
+		ID:         agentID,
+		Label:      label,
+		Session:    parentSessionKey,
+		Result:     result,
+		DurationMS: duration.Milliseconds(),
+		Duration:   duration,
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	gw.publish(event)
+
 	if err != nil {
 		gw.log.Debug("🤖 Sub-agent failed with error: %v", err)
 	} else {
@@ -292,8 +315,21 @@ func (gw *Gateway) handleSubAgentComplete(agentID, label, result, parentSessionK
 		gw.log.Warn("⚠️ No parent session key - result will not be injected")
 	}
 
-	// Result is injected into session history - bot will see it on next user message
-	// No proactive notification sent to avoid interrupting user
+	// Result is injected into session history - bot will see it on next user message.
+	// Let the user know it is there.
+	parts := parseSessionKey(parentSessionKey)
+	if len(parts) != 2 {
+		return
+	}
+	var notice string
+	if err != nil {
+		notice = fmt.Sprintf("❌ Sub-agent *%s* failed after %s: %v", label, formatDuration(duration), err)
+	} else {
+		notice = fmt.Sprintf("✅ Sub-agent *%s* finished in %s.\n\n%s\n\nAsk me about it to continue.", label, formatDuration(duration), truncateForDisplay(result, 500))
+	}
+	if err := gw.notify(parts[0], parts[1], notice); err != nil {
+		gw.log.Debug("🤖 Sub-agent notification not sent: %v", err)
+	}
 }
 
 // injectSubAgentResult adds the sub-agent result to the parent session history
@@ -442,4 +478,4 @@ func formatSubAgentStatus(status string) string {
 	default:
 		return status
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,155 @@
+package gateway
+
+import (
+	"time"
+
+	"github.com/FeelPulse/feelpulse/internal/command"
+	"github.com/FeelPulse/feelpulse/internal/config"
+	"github.com/FeelPulse/feelpulse/internal/store"
+	"github.com/FeelPulse/feelpulse/internal/webhook"
+)
+
+// webhookOutboxMaxAge is how long finished deliveries stay in the outbox
+const webhookOutboxMaxAge = 30 * 24 * time.Hour
+
+// initializeWebhooks sets up outbound event webhooks with a SQLite outbox
+func (gw *Gateway) initializeWebhooks() {
+	gw.webhooks = webhook.New()
+
+	if gw.db != nil {
+		if err := gw.db.EnsureWebhookOutboxTable(); err != nil {
+			gw.log.Warn("Failed to create webhook outbox table: %v", err)
+		} else {
+			gw.webhooks.SetPersister(&webhookOutboxAdapter{db: gw.db})
+			if removed, err := gw.db.CleanOldWebhookDeliveries(webhookOutboxMaxAge); err == nil && removed > 0 {
+				gw.log.Debug("🧹 Removed %d old webhook deliveries", removed)
+			}
+		}
+	}
+
+	gw.applyWebhookConfig(gw.cfg)
+	gw.webhooks.Start()
+}
+
+// applyWebhookConfig updates the webhook subscriptions from config
+func (gw *Gateway) applyWebhookConfig(cfg *config.Config) {
+	subs := make([]webhook.Subscription, 0, len(cfg.Webhooks.Subscriptions))
+	for _, s := range cfg.Webhooks.Subscriptions {
+		name := s.Name
+		if name == "" {
+			name = s.URL
+		}
+		for _, event := range s.Events {
+			if !webhook.IsEvent(event) {
+				gw.log.Warn("⚠️ Webhook %s subscribes to unknown event '%s'", name, event)
+			}
+		}
+		subs = append(subs, webhook.Subscription{Name: name, URL: s.URL, Secret: s.Secret, Events: s.Events})
+	}
+
+	gw.webhooks.SetSubscriptions(subs)
+	gw.webhooks.SetMaxAttempts(cfg.Webhooks.MaxAttempts)
+	if len(subs) > 0 {
+		gw.log.Info("📤 %d webhook subscription(s) configured", len(subs))
+	}
+}
+
+// ListWebhookDeliveries implements command.WebhookProvider
+func (gw *Gateway) ListWebhookDeliveries(status string, limit int) ([]command.WebhookDeliveryInfo, error) {
+	deliveries, err := gw.webhooks.List(status, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]command.WebhookDeliveryInfo, len(deliveries))
+	for i, d := range deliveries {
+		result[i] = command.WebhookDeliveryInfo{
+			ID:           d.ID,
+			Subscription: d.Subscription,
+			EventType:    d.EventType,
+			Status:       d.Status,
+			Attempts:     d.Attempts,
+			LastError:    d.LastError,
+			NextAttempt:  d.NextAttempt,
+			CreatedAt:    d.CreatedAt,
+		}
+	}
+	return result, nil
+}
+
+// ReplayWebhookDelivery implements command.WebhookProvider
+func (gw *Gateway) ReplayWebhookDelivery(id string) error {
+	_, err := gw.webhooks.Replay(id)
+	return err
+}
+
+// ReplayFailedWebhooks implements command.WebhookProvider
+func (gw *Gateway) ReplayFailedWebhooks() (int, error) {
+	return gw.webhooks.ReplayFailed()
+}
+
+// webhookOutboxAdapter wraps SQLiteStore to implement webhook.Persister
+type webhookOutboxAdapter struct {
+	db *store.SQLiteStore
+}
+
+func (a *webhookOutboxAdapter) SaveDelivery(d *webhook.DeliveryData) error {
+	return a.db.SaveWebhookDelivery(&store.WebhookDeliveryData{
+		ID:           d.ID,
+		Subscription: d.Subscription,
+		URL:          d.URL,
+		EventID:      d.EventID,
+		EventType:    d.EventType,
+		Payload:      d.Payload,
+		Status:       d.Status,
+		Attempts:     d.Attempts,
+		LastError:    d.LastError,
+		NextAttempt:  d.NextAttempt,
+		CreatedAt:    d.CreatedAt,
+		UpdatedAt:    d.UpdatedAt,
+	})
+}
+
+func (a *webhookOutboxAdapter) LoadDelivery(id string) (*webhook.DeliveryData, error) {
+	d, err := a.db.LoadWebhookDelivery(id)
+	if err != nil || d == nil {
+		return nil, err
+	}
+	return toWebhookDelivery(d), nil
+}
+
+func (a *webhookOutboxAdapter) LoadDueDeliveries(now time.Time, limit int) ([]*webhook.DeliveryData, error) {
+	return toWebhookDeliveries(a.db.LoadDueWebhookDeliveries(now, limit))
+}
+
+func (a *webhookOutboxAdapter) ListDeliveries(status string, limit int) ([]*webhook.DeliveryData, error) {
+	return toWebhookDeliveries(a.db.ListWebhookDeliveries(status, limit))
+}
+
+func toWebhookDeliveries(dbDeliveries []*store.WebhookDeliveryData, err error) ([]*webhook.DeliveryData, error) {
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*webhook.DeliveryData, len(dbDeliveries))
+	for i, d := range dbDeliveries {
+		result[i] = toWebhookDelivery(d)
+	}
+	return result, nil
+}
+
+func toWebhookDelivery(d *store.WebhookDeliveryData) *webhook.DeliveryData {
+	return &webhook.DeliveryData{
+		ID:           d.ID,
+		Subscription: d.Subscription,
+		URL:          d.URL,
+		EventID:      d.EventID,
+		EventType:    d.EventType,
+		Payload:      d.Payload,
+		Status:       d.Status,
+		Attempts:     d.Attempts,
+		LastError:    d.LastError,
+		NextAttempt:  d.NextAttempt,
+		CreatedAt:    d.CreatedAt,
+		UpdatedAt:    d.UpdatedAt,
+	}
+}
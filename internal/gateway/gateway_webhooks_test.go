@@ -0,0 +1,66 @@
+package gateway
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/FeelPulse/feelpulse/internal/config"
+	"github.com/FeelPulse/feelpulse/internal/events"
+	"github.com/FeelPulse/feelpulse/internal/logger"
+	"github.com/FeelPulse/feelpulse/internal/metrics"
+	"github.com/FeelPulse/feelpulse/internal/store"
+	"github.com/FeelPulse/feelpulse/internal/webhook"
+)
+
+func TestWebhooks_SQLiteOutbox(t *testing.T) {
+	db, err := store.NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer db.Close()
+
+	var status atomic.Int32
+	status.Store(http.StatusServiceUnavailable)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(int(status.Load()))
+	}))
+	defer server.Close()
+
+	cfg := config.Default()
+	cfg.Webhooks.MaxAttempts = 1
+	cfg.Webhooks.Subscriptions = []config.WebhookSubscription{
+		{URL: server.URL, Secret: "s3cret", Events: []string{webhook.EventReplySent}},
+	}
+	log := logger.New(&logger.Config{Level: "error"})
+	gw := &Gateway{cfg: cfg, db: db, log: log, metrics: metrics.NewCollector(), events: events.New(log)}
+	gw.subscribeEvents()
+	gw.initializeWebhooks()
+	defer gw.webhooks.Stop()
+
+	gw.publish(events.ReplySent{Text: "hi"})
+	gw.publishToolCalled("telegram:1", "exec", nil, nil) // Not subscribed
+	gw.events.Close()
+	gw.webhooks.SendDue()
+
+	failed, err := gw.ListWebhookDeliveries(webhook.StatusFailed, 10)
+	if err != nil || len(failed) != 1 {
+		t.Fatalf("expected 1 failed delivery, got %d (%v)", len(failed), err)
+	}
+	if d := failed[0]; d.Subscription != server.URL || d.EventType != webhook.EventReplySent || d.LastError != "HTTP 503" {
+		t.Errorf("unexpected delivery: %+v", d)
+	}
+
+	status.Store(http.StatusOK)
+	if err := gw.ReplayWebhookDelivery(failed[0].ID); err != nil {
+		t.Fatalf("ReplayWebhookDelivery error: %v", err)
+	}
+	gw.webhooks.SendDue()
+
+	all, _ := gw.ListWebhookDeliveries("", 10)
+	if len(all) != 1 || all[0].Status != webhook.StatusDelivered {
+		t.Errorf("replayed delivery should be delivered: %+v", all)
+	}
+}
@@ -0,0 +1,463 @@
+package gateway
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/FeelPulse/feelpulse/internal/agent"
+	"github.com/FeelPulse/feelpulse/internal/apikey"
+	"github.com/FeelPulse/feelpulse/pkg/types"
+)
+
+// Anthropic Messages API compatible types
+
+// MessagesRequest represents an Anthropic Messages API request
+type MessagesRequest struct {
+	Model         string                     `json:"model"`
+	Messages      []MessagesMessage          `json:"messages"`
+	System        MessagesContent            `json:"system,omitempty"` // A string or text blocks
+	MaxTokens     int                        `json:"max_tokens"`
+	Temperature   *float64                   `json:"temperature,omitempty"`
+	TopP          *float64                   `json:"top_p,omitempty"`
+	StopSequences []string                   `json:"stop_sequences,omitempty"`
+	Stream        bool                       `json:"stream,omitempty"`
+	Tools         []agent.AnthropicTool      `json:"tools,omitempty"`
+	ToolChoice    *agent.AnthropicToolChoice `json:"tool_choice,omitempty"`
+	Metadata      *MessagesMetadata          `json:"metadata,omitempty"`
+}
+
+// MessagesMetadata identifies the end user of a request
+type MessagesMetadata struct {
+	UserID string `json:"user_id,omitempty"`
+}
+
+// MessagesMessage is one turn of the conversation
+type MessagesMessage struct {
+	Role    string          `json:"role"`
+	Content MessagesContent `json:"content"`
+}
+
+// MessagesContent is message content: a plain string or a list of blocks
+type MessagesContent []MessagesBlock
+
+// UnmarshalJSON accepts both forms of message content
+func (c *MessagesContent) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*c = MessagesContent{{Type: "text", Text: text}}
+		return nil
+	}
+
+	var blocks []MessagesBlock
+	if err := json.Unmarshal(data, &blocks); err != nil {
+		return fmt.Errorf("content must be a string or an array of content blocks")
+	}
+	*c = blocks
+	return nil
+}
+
+// Text joins the text blocks of the content
+func (c MessagesContent) Text() string {
+	var parts []string
+	for _, block := range c {
+		if block.Type == "text" && block.Text != "" {
+			parts = append(parts, block.Text)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// MessagesBlock is a content block of a request message
+type MessagesBlock struct {
+	Type      string             `json:"type"`                  // "text", "image", "tool_use" or "tool_result"
+	Text      string             `json:"text,omitempty"`        // for type="text"
+	Source    *agent.ImageSource `json:"source,omitempty"`      // for type="image"
+	ID        string             `json:"id,omitempty"`          // for type="tool_use"
+	Name      string             `json:"name,omitempty"`        // for type="tool_use"
+	Input     json.RawMessage    `json:"input,omitempty"`       // for type="tool_use"
+	ToolUseID string             `json:"tool_use_id,omitempty"` // for type="tool_result"
+	Content   MessagesContent    `json:"content,omitempty"`     // for type="tool_result"
+	IsError   bool               `json:"is_error,omitempty"`    // for type="tool_result"
+}
+
+// MessagesResponse represents an Anthropic Messages API response
+type MessagesResponse struct {
+	ID           string        `json:"id"`
+	Type         string        `json:"type"`
+	Role         string        `json:"role"`
+	Model        string        `json:"model"`
+	Content      []any         `json:"content"` // MessagesTextBlock and MessagesToolUseBlock
+	StopReason   *string       `json:"stop_reason"`
+	StopSequence *string       `json:"stop_sequence"`
+	Usage        MessagesUsage `json:"usage"`
+}
+
+// MessagesTextBlock is a text block of a response
+type MessagesTextBlock struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// MessagesToolUseBlock is a client tool call of a response
+type MessagesToolUseBlock struct {
+	Type  string          `json:"type"`
+	ID    string          `json:"id"`
+	Name  string          `json:"name"`
+	Input json.RawMessage `json:"input"`
+}
+
+// MessagesUsage represents token usage in Anthropic format
+type MessagesUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+// MessagesStreamEvent is one server-sent event of a streamed message
+type MessagesStreamEvent struct {
+	Type         string            `json:"type"`
+	Message      *MessagesResponse `json:"message,omitempty"`
+	Index        *int              `json:"index,omitempty"`
+	ContentBlock any               `json:"content_block,omitempty"`
+	Delta        *MessagesDelta    `json:"delta,omitempty"`
+	Usage        *MessagesUsage    `json:"usage,omitempty"`
+}
+
+// MessagesDelta is incremental content or the final message state
+type MessagesDelta struct {
+	Type         string  `json:"type,omitempty"`
+	Text         string  `json:"text,omitempty"`
+	PartialJSON  string  `json:"partial_json,omitempty"`
+	StopReason   string  `json:"stop_reason,omitempty"`
+	StopSequence *string `json:"stop_sequence,omitempty"`
+}
+
+// MessagesErrorResponse represents an Anthropic API error
+type MessagesErrorResponse struct {
+	Type  string               `json:"type"` // "error"
+	Error agent.AnthropicError `json:"error"`
+}
+
+// handleMessages handles POST /v1/messages
+func (gw *Gateway) handleMessages(w http.ResponseWriter, r *http.Request) {
+	r, ok := gw.authorize(w, r, apikey.ScopeChat)
+	if !ok {
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		gw.writeMessagesError(w, http.StatusMethodNotAllowed, "Method not allowed", "invalid_request_error")
+		return
+	}
+
+	var req MessagesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		gw.writeMessagesError(w, http.StatusBadRequest, "Invalid JSON: "+err.Error(), "invalid_request_error")
+		return
+	}
+
+	messages, err := convertMessagesToInternal(&req)
+	if err != nil {
+		gw.writeMessagesError(w, http.StatusBadRequest, err.Error(), "invalid_request_error")
+		return
+	}
+	if len(messages) == 0 {
+		gw.writeMessagesError(w, http.StatusBadRequest, "messages is required", "invalid_request_error")
+		return
+	}
+	if len(req.Tools) > 0 && gw.cfg.Agent.Provider == "openai" {
+		gw.writeMessagesError(w, http.StatusBadRequest, "tools require the anthropic provider", "invalid_request_error")
+		return
+	}
+
+	var user string
+	if req.Metadata != nil {
+		user = req.Metadata.UserID
+	}
+	if !gw.allowAPIRequest(r, user) {
+		gw.writeMessagesError(w, http.StatusTooManyRequests, "Rate limit exceeded", "rate_limit_error")
+		return
+	}
+
+	gw.mu.RLock()
+	router := gw.router
+	gw.mu.RUnlock()
+
+	if router == nil {
+		gw.writeMessagesError(w, http.StatusServiceUnavailable, "AI agent not configured", "api_error")
+		return
+	}
+
+	model := gw.resolveAPIModel(req.Model)
+	if !allowAPIModel(r, model) {
+		gw.writeMessagesError(w, http.StatusForbidden, fmt.Sprintf("API key may not use model '%s'", model), "permission_error")
+		return
+	}
+	gw.log.Info("📡 Messages API: model=%s → %s, messages=%d, tools=%d, stream=%v", req.Model, model, len(messages), len(req.Tools), req.Stream)
+
+	// Continue a FeelPulse session if the client names one
+	system := req.System.Text()
+	sess, err := gw.apiSessionFor(r, user)
+	if errors.Is(err, errAPISessionForbidden) {
+		gw.writeMessagesError(w, http.StatusForbidden, err.Error(), "permission_error")
+		return
+	}
+	if err != nil {
+		gw.writeMessagesError(w, http.StatusBadRequest, err.Error(), "invalid_request_error")
+		return
+	}
+	replace := gw.replaceSystem(system)
+	if sess != nil {
+		var pins string
+		if messages, pins, err = gw.startAPITurn(sess, messages); err != nil {
+			gw.writeMessagesError(w, http.StatusBadRequest, err.Error(), "invalid_request_error")
+			return
+		}
+		system = joinSystem(pins, system)
+		w.Header().Set(APISessionHeader, sess.key())
+	}
+
+	// A client disconnect cancels the request
+	opts := agent.ProcessOptions{
+		Context: r.Context(),
+		Params: agent.RequestParams{
+			Model:       model,
+			MaxTokens:   req.MaxTokens,
+			Temperature: req.Temperature,
+			TopP:        req.TopP,
+			Stop:        req.StopSequences,
+		},
+		System:        system,
+		ReplaceSystem: replace,
+		ClientTools:   req.Tools,
+		ToolChoice:    req.ToolChoice,
+		NoServerTools: !gw.apiServerTools(r),
+	}
+
+	if req.Stream {
+		gw.streamMessages(w, r, router, sess, messages, model, opts)
+		return
+	}
+
+	reply, err := router.ProcessWithOptions(messages, opts)
+	if err != nil {
+		gw.log.Error("Messages API error: %v", err)
+		gw.writeMessagesError(w, http.StatusInternalServerError, "Failed to process request: "+err.Error(), "api_error")
+		return
+	}
+
+	inputTokens, outputTokens := gw.trackAPIUsage("anthropic-compat", reply)
+	gw.recordAPIKeyUsage(r, inputTokens, outputTokens)
+	if sess != nil {
+		gw.finishAPITurn(sess, reply)
+	}
+
+	var content []any
+	if reply.Text != "" {
+		content = append(content, MessagesTextBlock{Type: "text", Text: reply.Text})
+	}
+	calls, _ := reply.Metadata["tool_calls"].([]types.ToolCall)
+	for _, call := range calls {
+		content = append(content, MessagesToolUseBlock{Type: "tool_use", ID: call.ID, Name: call.Name, Input: call.Input})
+	}
+	if content == nil {
+		content = []any{}
+	}
+
+	stopReason := messagesStopReason(reply)
+	resp := MessagesResponse{
+		ID:         generateMessageID(),
+		Type:       "message",
+		Role:       "assistant",
+		Model:      model,
+		Content:    content,
+		StopReason: &stopReason,
+		Usage: MessagesUsage{
+			InputTokens:  inputTokens,
+			OutputTokens: outputTokens,
+		},
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// streamMessages answers with Messages API server-sent events as the agent
+// produces text
+func (gw *Gateway) streamMessages(w http.ResponseWriter, r *http.Request, router *agent.Router, sess *apiSession, messages []types.Message, model string, opts agent.ProcessOptions) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		gw.writeMessagesError(w, http.StatusInternalServerError, "Streaming is not supported by the server", "api_error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	send := func(event MessagesStreamEvent) {
+		data, _ := json.Marshal(event)
+		fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+		flusher.Flush()
+	}
+
+	send(MessagesStreamEvent{Type: "message_start", Message: &MessagesResponse{
+		ID:      generateMessageID(),
+		Type:    "message",
+		Role:    "assistant",
+		Model:   model,
+		Content: []any{},
+	}})
+
+	// Content blocks are numbered in order; text streams into block 0
+	index := 0
+	textOpen := false
+	var blockEnded bool
+	opts.OnText = func(text string) {
+		if !textOpen {
+			send(MessagesStreamEvent{Type: "content_block_start", Index: &index, ContentBlock: MessagesTextBlock{Type: "text"}})
+			textOpen = true
+		}
+		// Separate the text of agentic loop iterations like the final reply does
+		if blockEnded {
+			text = "\n\n" + text
+			blockEnded = false
+		}
+		send(MessagesStreamEvent{Type: "content_block_delta", Index: &index, Delta: &MessagesDelta{Type: "text_delta", Text: text}})
+	}
+	opts.OnIterationText = func(string) { blockEnded = true }
+
+	reply, err := router.ProcessWithOptions(messages, opts)
+	if err != nil {
+		if r.Context().Err() != nil {
+			gw.log.Info("📡 Messages API: client disconnected, request cancelled")
+			return
+		}
+		gw.log.Error("Messages API error: %v", err)
+		data, _ := json.Marshal(MessagesErrorResponse{Type: "error", Error: agent.AnthropicError{Type: "api_error", Message: "Failed to process request: " + err.Error()}})
+		fmt.Fprintf(w, "event: error\ndata: %s\n\n", data)
+		flusher.Flush()
+		return
+	}
+
+	inputTokens, outputTokens := gw.trackAPIUsage("anthropic-compat", reply)
+	gw.recordAPIKeyUsage(r, inputTokens, outputTokens)
+	if sess != nil {
+		gw.finishAPITurn(sess, reply)
+	}
+
+	if textOpen {
+		send(MessagesStreamEvent{Type: "content_block_stop", Index: &index})
+		index++
+	}
+
+	calls, _ := reply.Metadata["tool_calls"].([]types.ToolCall)
+	for _, call := range calls {
+		send(MessagesStreamEvent{Type: "content_block_start", Index: &index, ContentBlock: MessagesToolUseBlock{
+			Type:  "tool_use",
+			ID:    call.ID,
+			Name:  call.Name,
+			Input: json.RawMessage("{}"),
+		}})
+		send(MessagesStreamEvent{Type: "content_block_delta", Index: &index, Delta: &MessagesDelta{Type: "input_json_delta", PartialJSON: string(call.Input)}})
+		send(MessagesStreamEvent{Type: "content_block_stop", Index: &index})
+		index++
+	}
+
+	send(MessagesStreamEvent{
+		Type:  "message_delta",
+		Delta: &MessagesDelta{StopReason: messagesStopReason(reply)},
+		Usage: &MessagesUsage{InputTokens: inputTokens, OutputTokens: outputTokens},
+	})
+	send(MessagesStreamEvent{Type: "message_stop"})
+}
+
+// messagesStopReason returns "tool_use" when the reply calls client tools
+func messagesStopReason(reply *types.Message) string {
+	if calls, _ := reply.Metadata["tool_calls"].([]types.ToolCall); len(calls) > 0 {
+		return "tool_use"
+	}
+	return "end_turn"
+}
+
+// convertMessagesToInternal converts Messages API turns to internal messages.
+// Tool results become separate messages answering the client's tool calls.
+func convertMessagesToInternal(req *MessagesRequest) ([]types.Message, error) {
+	var messages []types.Message
+	now := time.Now()
+
+	for _, msg := range req.Messages {
+		if msg.Role != "user" && msg.Role != "assistant" {
+			return nil, fmt.Errorf("unsupported role '%s'", msg.Role)
+		}
+
+		internal := types.Message{
+			Text:      msg.Content.Text(),
+			Channel:   "api",
+			IsBot:     msg.Role == "assistant",
+			Timestamp: now,
+		}
+
+		var calls []types.ToolCall
+		for _, block := range msg.Content {
+			switch block.Type {
+			case "tool_use":
+				calls = append(calls, types.ToolCall{ID: block.ID, Name: block.Name, Input: block.Input})
+			case "tool_result":
+				result := block.Content.Text()
+				if block.IsError {
+					result = "Error: " + result
+				}
+				messages = append(messages, types.Message{
+					Text:      result,
+					Channel:   "api",
+					Timestamp: now,
+					Metadata:  map[string]any{"tool_call_id": block.ToolUseID},
+				})
+			case "image":
+				if internal.Metadata != nil {
+					return nil, fmt.Errorf("at most one image per message is supported")
+				}
+				if block.Source == nil || block.Source.Type != "base64" {
+					return nil, fmt.Errorf("only base64 image sources are supported")
+				}
+				internal.Metadata = map[string]any{"image": map[string]string{
+					"data":       block.Source.Data,
+					"media_type": block.Source.MediaType,
+				}}
+			}
+		}
+
+		if len(calls) > 0 && internal.IsBot {
+			internal.Metadata = map[string]any{"tool_calls": calls}
+		}
+		if internal.Text != "" || internal.Metadata != nil {
+			messages = append(messages, internal)
+		}
+	}
+
+	return messages, nil
+}
+
+// writeMessagesError writes an error response in Anthropic format
+func (gw *Gateway) writeMessagesError(w http.ResponseWriter, status int, message, errType string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(MessagesErrorResponse{
+		Type:  "error",
+		Error: agent.AnthropicError{Type: errType, Message: message},
+	})
+}
+
+// generateMessageID generates a unique message ID
+func generateMessageID() string {
+	b := make([]byte, 12)
+	rand.Read(b)
+	return "msg_" + hex.EncodeToString(b)
+}
@@ -0,0 +1,206 @@
+package gateway
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/FeelPulse/feelpulse/internal/apikey"
+	"github.com/FeelPulse/feelpulse/internal/config"
+	"github.com/FeelPulse/feelpulse/internal/logger"
+	"github.com/FeelPulse/feelpulse/internal/ratelimit"
+	"github.com/FeelPulse/feelpulse/pkg/types"
+)
+
+func TestMessagesRequest_Parse(t *testing.T) {
+	data := `{
+		"model": "claude-sonnet-4-20250514",
+		"max_tokens": 1024,
+		"system": [{"type": "text", "text": "Be brief."}, {"type": "text", "text": "Answer in French."}],
+		"messages": [
+			{"role": "user", "content": "Hello!"},
+			{"role": "assistant", "content": [{"type": "text", "text": "Bonjour !"}]}
+		],
+		"tools": [{"name": "get_weather", "description": "Weather", "input_schema": {"type": "object"}}],
+		"tool_choice": {"type": "auto"},
+		"metadata": {"user_id": "alice"}
+	}`
+
+	var req MessagesRequest
+	if err := json.Unmarshal([]byte(data), &req); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+
+	if req.System.Text() != "Be brief.\n\nAnswer in French." {
+		t.Errorf("system = %q", req.System.Text())
+	}
+	if len(req.Messages) != 2 || req.Messages[0].Content.Text() != "Hello!" || req.Messages[1].Content.Text() != "Bonjour !" {
+		t.Errorf("unexpected messages: %+v", req.Messages)
+	}
+	if len(req.Tools) != 1 || req.ToolChoice == nil || req.ToolChoice.Type != "auto" || req.Metadata.UserID != "alice" {
+		t.Errorf("unexpected request: %+v", req)
+	}
+
+	if err := json.Unmarshal([]byte(`{"messages": [{"role": "user", "content": 42}]}`), &req); err == nil {
+		t.Error("expected an error for numeric content")
+	}
+}
+
+func TestConvertMessagesToInternal(t *testing.T) {
+	data := `{"messages": [
+		{"role": "user", "content": [
+			{"type": "image", "source": {"type": "base64", "media_type": "image/png", "data": "aGk="}},
+			{"type": "text", "text": "Where is this?"}
+		]},
+		{"role": "assistant", "content": [
+			{"type": "text", "text": "Let me check."},
+			{"type": "tool_use", "id": "toolu_1", "name": "locate", "input": {"q": "photo"}}
+		]},
+		{"role": "user", "content": [
+			{"type": "tool_result", "tool_use_id": "toolu_1", "content": "timeout", "is_error": true},
+			{"type": "text", "text": "Try again"}
+		]}
+	]}`
+
+	var req MessagesRequest
+	if err := json.Unmarshal([]byte(data), &req); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+
+	messages, err := convertMessagesToInternal(&req)
+	if err != nil {
+		t.Fatalf("convertMessagesToInternal error: %v", err)
+	}
+	if len(messages) != 4 {
+		t.Fatalf("expected 4 messages, got %d", len(messages))
+	}
+
+	image, ok := messages[0].Metadata["image"].(map[string]string)
+	if !ok || image["media_type"] != "image/png" || messages[0].Text != "Where is this?" {
+		t.Errorf("unexpected image message: %+v", messages[0])
+	}
+	calls, ok := messages[1].Metadata["tool_calls"].([]types.ToolCall)
+	if !ok || !messages[1].IsBot || len(calls) != 1 || calls[0].Name != "locate" {
+		t.Errorf("unexpected assistant message: %+v", messages[1])
+	}
+	if messages[2].Metadata["tool_call_id"] != "toolu_1" || messages[2].Text != "Error: timeout" {
+		t.Errorf("unexpected tool result: %+v", messages[2])
+	}
+	if messages[3].Text != "Try again" || messages[3].Metadata != nil {
+		t.Errorf("unexpected follow-up: %+v", messages[3])
+	}
+}
+
+func TestConvertMessagesToInternal_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+	}{
+		{"system role", `{"messages": [{"role": "system", "content": "hi"}]}`},
+		{"url image", `{"messages": [{"role": "user", "content": [{"type": "image", "source": {"type": "url", "url": "https://example.com/a.png"}}]}]}`},
+		{"two images", `{"messages": [{"role": "user", "content": [
+			{"type": "image", "source": {"type": "base64", "media_type": "image/png", "data": "aGk="}},
+			{"type": "image", "source": {"type": "base64", "media_type": "image/png", "data": "aGk="}}
+		]}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req MessagesRequest
+			if err := json.Unmarshal([]byte(tt.json), &req); err != nil {
+				t.Fatalf("Unmarshal error: %v", err)
+			}
+			if _, err := convertMessagesToInternal(&req); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestHandleMessages_Errors(t *testing.T) {
+	cfg := config.Default()
+	gw := &Gateway{cfg: cfg, limiter: ratelimit.New(0), log: logger.New(&logger.Config{Level: "error"})}
+
+	tests := []struct {
+		name    string
+		method  string
+		body    string
+		status  int
+		errType string
+	}{
+		{"wrong method", http.MethodGet, "", http.StatusMethodNotAllowed, "invalid_request_error"},
+		{"invalid JSON", http.MethodPost, "{", http.StatusBadRequest, "invalid_request_error"},
+		{"no messages", http.MethodPost, `{"messages": []}`, http.StatusBadRequest, "invalid_request_error"},
+		{"no agent", http.MethodPost, `{"messages": [{"role": "user", "content": "hi"}]}`, http.StatusServiceUnavailable, "api_error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			gw.handleMessages(rec, httptest.NewRequest(tt.method, "/v1/messages", strings.NewReader(tt.body)))
+
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+			var resp MessagesErrorResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Unmarshal error: %v", err)
+			}
+			if resp.Type != "error" || resp.Error.Type != tt.errType {
+				t.Errorf("unexpected error: %+v", resp)
+			}
+		})
+	}
+}
+
+func TestMessagesStreamEvent_Marshal(t *testing.T) {
+	index := 0
+	data, _ := json.Marshal(MessagesStreamEvent{Type: "content_block_start", Index: &index, ContentBlock: MessagesTextBlock{Type: "text"}})
+	if string(data) != `{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}` {
+		t.Errorf("unexpected event: %s", data)
+	}
+
+	data, _ = json.Marshal(MessagesStreamEvent{Type: "message_start", Message: &MessagesResponse{ID: "msg_1", Type: "message", Role: "assistant", Content: []any{}}})
+	if !strings.Contains(string(data), `"stop_reason":null`) || !strings.Contains(string(data), `"content":[]`) {
+		t.Errorf("unexpected message_start: %s", data)
+	}
+}
+
+func TestGenerateMessageID(t *testing.T) {
+	id := generateMessageID()
+	if !strings.HasPrefix(id, "msg_") || id == generateMessageID() {
+		t.Errorf("unexpected message ID %q", id)
+	}
+}
+
+func TestCheckAuth_APIKeyHeader(t *testing.T) {
+	cfg := config.Default()
+	cfg.Hooks.Token = "secret"
+	gw := &Gateway{cfg: cfg}
+
+	tests := []struct {
+		name   string
+		header string
+		value  string
+		want   bool
+	}{
+		{"bearer", "Authorization", "Bearer secret", true},
+		{"x-api-key", "X-Api-Key", "secret", true},
+		{"wrong key", "X-Api-Key", "guess", false},
+		{"none", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/v1/messages", nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			if _, got := gw.authorize(httptest.NewRecorder(), req, apikey.ScopeChat); got != tt.want {
+				t.Errorf("authorize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,10 +4,17 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
 	"net/http"
 	"strings"
 	"time"
 
+	"github.com/FeelPulse/feelpulse/internal/agent"
+	"github.com/FeelPulse/feelpulse/internal/apikey"
+	"github.com/FeelPulse/feelpulse/internal/events"
+	"github.com/FeelPulse/feelpulse/internal/session"
 	"github.com/FeelPulse/feelpulse/pkg/types"
 )
 
@@ -18,16 +25,79 @@ type OpenAIRequest struct {
 	Model       string          `json:"model"`
 	Messages    []OpenAIMessage `json:"messages"`
 	MaxTokens   int             `json:"max_tokens,omitempty"`
-	Temperature float64         `json:"temperature,omitempty"`
-	TopP        float64         `json:"top_p,omitempty"`
+	Temperature *float64        `json:"temperature,omitempty"`
+	TopP        *float64        `json:"top_p,omitempty"`
+	Stop        OpenAIStop      `json:"stop,omitempty"`
 	Stream      bool            `json:"stream,omitempty"`
 	User        string          `json:"user,omitempty"`
+	Tools       []OpenAITool    `json:"tools,omitempty"`
+	ToolChoice  json.RawMessage `json:"tool_choice,omitempty"` // "auto", "none", "required" or a named function
+
+	MaxCompletionTokens int `json:"max_completion_tokens,omitempty"` // Newer name for max_tokens
+
+	StreamOptions *OpenAIStreamOptions `json:"stream_options,omitempty"`
+}
+
+// OpenAIStreamOptions controls streamed responses
+type OpenAIStreamOptions struct {
+	IncludeUsage bool `json:"include_usage"` // Send a final chunk with token usage
+}
+
+// OpenAIStop is the "stop" parameter: a single string or a list of strings
+type OpenAIStop []string
+
+// UnmarshalJSON accepts both forms of the stop parameter
+func (s *OpenAIStop) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*s = nil
+		if one != "" {
+			*s = OpenAIStop{one}
+		}
+		return nil
+	}
+
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return fmt.Errorf("stop must be a string or an array of strings")
+	}
+	*s = many
+	return nil
 }
 
 // OpenAIMessage represents a message in OpenAI format
 type OpenAIMessage struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
+	Role       string           `json:"role"`
+	Content    string           `json:"content"`
+	ToolCalls  []OpenAIToolCall `json:"tool_calls,omitempty"`   // Assistant messages calling tools
+	ToolCallID string           `json:"tool_call_id,omitempty"` // Tool messages answering a call
+}
+
+// OpenAITool is a client-defined function the model may call
+type OpenAITool struct {
+	Type     string         `json:"type"` // "function"
+	Function OpenAIFunction `json:"function"`
+}
+
+// OpenAIFunction describes a client-defined function
+type OpenAIFunction struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters,omitempty"` // JSON Schema of the arguments
+}
+
+// OpenAIToolCall is a function call made by the model
+type OpenAIToolCall struct {
+	Index    *int               `json:"index,omitempty"` // Set in stream chunks only
+	ID       string             `json:"id"`
+	Type     string             `json:"type"`
+	Function OpenAIFunctionCall `json:"function"`
+}
+
+// OpenAIFunctionCall is the function and arguments of a tool call
+type OpenAIFunctionCall struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"` // JSON-encoded
 }
 
 // OpenAIResponse represents an OpenAI chat completion response
@@ -47,6 +117,30 @@ type OpenAIChoice struct {
 	FinishReason string        `json:"finish_reason"`
 }
 
+// OpenAIStreamChunk is one server-sent event of a streamed completion
+type OpenAIStreamChunk struct {
+	ID      string               `json:"id"`
+	Object  string               `json:"object"`
+	Created int64                `json:"created"`
+	Model   string               `json:"model"`
+	Choices []OpenAIStreamChoice `json:"choices"`
+	Usage   *OpenAIUsage         `json:"usage,omitempty"`
+}
+
+// OpenAIStreamChoice is the part of a choice sent in one chunk
+type OpenAIStreamChoice struct {
+	Index        int         `json:"index"`
+	Delta        OpenAIDelta `json:"delta"`
+	FinishReason *string     `json:"finish_reason"`
+}
+
+// OpenAIDelta is incremental message content
+type OpenAIDelta struct {
+	Role      string           `json:"role,omitempty"`
+	Content   string           `json:"content,omitempty"`
+	ToolCalls []OpenAIToolCall `json:"tool_calls,omitempty"`
+}
+
 // OpenAIUsage represents token usage in OpenAI format
 type OpenAIUsage struct {
 	PromptTokens     int `json:"prompt_tokens"`
@@ -54,6 +148,20 @@ type OpenAIUsage struct {
 	TotalTokens      int `json:"total_tokens"`
 }
 
+// OpenAIModel describes a model in the /v1/models list
+type OpenAIModel struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"`
+	Created int64  `json:"created"`
+	OwnedBy string `json:"owned_by"`
+}
+
+// OpenAIModelList is the /v1/models response
+type OpenAIModelList struct {
+	Object string        `json:"object"`
+	Data   []OpenAIModel `json:"data"`
+}
+
 // OpenAIError represents an OpenAI API error
 type OpenAIError struct {
 	Message string `json:"message"`
@@ -68,7 +176,8 @@ type OpenAIErrorResponse struct {
 
 // handleOpenAIChatCompletion handles POST /v1/chat/completions
 func (gw *Gateway) handleOpenAIChatCompletion(w http.ResponseWriter, r *http.Request) {
-	if !gw.checkAuth(w, r) {
+	r, ok := gw.authorize(w, r, apikey.ScopeChat)
+	if !ok {
 		return
 	}
 
@@ -91,9 +200,8 @@ func (gw *Gateway) handleOpenAIChatCompletion(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	// Check if streaming is requested (not supported yet)
-	if req.Stream {
-		gw.writeOpenAIError(w, http.StatusBadRequest, "Streaming is not supported", "invalid_request_error")
+	if !gw.allowAPIRequest(r, req.User) {
+		gw.writeOpenAIError(w, http.StatusTooManyRequests, "Rate limit exceeded", "rate_limit_error")
 		return
 	}
 
@@ -110,39 +218,89 @@ func (gw *Gateway) handleOpenAIChatCompletion(w http.ResponseWriter, r *http.Req
 	// Convert OpenAI messages to internal format
 	messages, systemPrompt := convertOpenAIToInternal(&req)
 
-	// Map model name if it's an OpenAI model
-	model := mapToAnthropicModel(req.Model)
-	gw.log.Info("📡 OpenAI API: model=%s → %s, messages=%d", req.Model, model, len(messages))
+	if len(messages) == 0 {
+		gw.writeOpenAIError(w, http.StatusBadRequest, "messages must include a user message", "invalid_request_error")
+		return
+	}
 
-	// Process with the agent
-	reply, err := router.ProcessWithHistory(messages)
+	// Translate client tools
+	clientTools, err := openAIToolsToAnthropic(req.Tools)
 	if err != nil {
-		gw.log.Error("OpenAI API error: %v", err)
-		gw.writeOpenAIError(w, http.StatusInternalServerError, "Failed to process request: "+err.Error(), "server_error")
+		gw.writeOpenAIError(w, http.StatusBadRequest, err.Error(), "invalid_request_error")
+		return
+	}
+	toolChoice, err := openAIToolChoice(req.ToolChoice)
+	if err != nil {
+		gw.writeOpenAIError(w, http.StatusBadRequest, err.Error(), "invalid_request_error")
+		return
+	}
+	if len(clientTools) > 0 && gw.cfg.Agent.Provider == "openai" {
+		gw.writeOpenAIError(w, http.StatusBadRequest, "tools require the anthropic provider", "invalid_request_error")
 		return
 	}
 
-	// Track metrics for OpenAI-compat endpoint
-	gw.metrics.IncrementMessages("openai-compat")
+	// Route the requested model to one the configured provider serves
+	model := gw.resolveAPIModel(req.Model)
+	if !allowAPIModel(r, model) {
+		gw.writeOpenAIError(w, http.StatusForbidden, fmt.Sprintf("API key may not use model '%s'", model), "permission_error")
+		return
+	}
+	gw.log.Info("📡 OpenAI API: model=%s → %s, messages=%d, tools=%d, stream=%v", req.Model, model, len(messages), len(clientTools), req.Stream)
 
-	// Track token usage
-	if reply != nil && reply.Metadata != nil {
-		if input, ok := reply.Metadata["input_tokens"].(int); ok {
-			if output, ok := reply.Metadata["output_tokens"].(int); ok {
-				gw.metrics.AddTokens(input, output)
-			}
+	// Continue a FeelPulse session if the client names one
+	sess, err := gw.apiSessionFor(r, req.User)
+	if errors.Is(err, errAPISessionForbidden) {
+		gw.writeOpenAIError(w, http.StatusForbidden, err.Error(), "permission_error")
+		return
+	}
+	if err != nil {
+		gw.writeOpenAIError(w, http.StatusBadRequest, err.Error(), "invalid_request_error")
+		return
+	}
+	replace := gw.replaceSystem(systemPrompt)
+	if sess != nil {
+		var pins string
+		if messages, pins, err = gw.startAPITurn(sess, messages); err != nil {
+			gw.writeOpenAIError(w, http.StatusBadRequest, err.Error(), "invalid_request_error")
+			return
 		}
+		systemPrompt = joinSystem(pins, systemPrompt)
+		w.Header().Set(APISessionHeader, sess.key())
 	}
 
-	// Extract usage from reply metadata
-	var inputTokens, outputTokens int
-	if reply.Metadata != nil {
-		if v, ok := reply.Metadata["input_tokens"].(int); ok {
-			inputTokens = v
-		}
-		if v, ok := reply.Metadata["output_tokens"].(int); ok {
-			outputTokens = v
-		}
+	// A client disconnect cancels the request
+	opts := agent.ProcessOptions{
+		Context:       r.Context(),
+		Params:        openAIRequestParams(&req, model),
+		System:        systemPrompt,
+		ReplaceSystem: replace,
+		ClientTools:   clientTools,
+		ToolChoice:    toolChoice,
+		NoServerTools: !gw.apiServerTools(r),
+	}
+
+	if req.Stream {
+		gw.streamOpenAIChatCompletion(w, r, router, sess, messages, model, opts, req.StreamOptions != nil && req.StreamOptions.IncludeUsage)
+		return
+	}
+
+	// Process with the agent
+	reply, err := router.ProcessWithOptions(messages, opts)
+	if err != nil {
+		gw.log.Error("OpenAI API error: %v", err)
+		gw.writeOpenAIError(w, http.StatusInternalServerError, "Failed to process request: "+err.Error(), "server_error")
+		return
+	}
+
+	inputTokens, outputTokens := gw.trackAPIUsage("openai-compat", reply)
+	gw.recordAPIKeyUsage(r, inputTokens, outputTokens)
+	if sess != nil {
+		gw.finishAPITurn(sess, reply)
+	}
+	toolCalls := openAIToolCalls(reply, false)
+	finishReason := "stop"
+	if len(toolCalls) > 0 {
+		finishReason = "tool_calls"
 	}
 
 	// Build response
@@ -155,10 +313,11 @@ func (gw *Gateway) handleOpenAIChatCompletion(w http.ResponseWriter, r *http.Req
 			{
 				Index: 0,
 				Message: OpenAIMessage{
-					Role:    "assistant",
-					Content: reply.Text,
+					Role:      "assistant",
+					Content:   reply.Text,
+					ToolCalls: toolCalls,
 				},
-				FinishReason: "stop",
+				FinishReason: finishReason,
 			},
 		},
 		Usage: OpenAIUsage{
@@ -168,13 +327,309 @@ func (gw *Gateway) handleOpenAIChatCompletion(w http.ResponseWriter, r *http.Req
 		},
 	}
 
-	// Log for debugging
-	_ = systemPrompt // Used internally by router
-
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(resp)
 }
 
+// streamOpenAIChatCompletion answers with chat.completion.chunk server-sent
+// events as the agent produces text
+func (gw *Gateway) streamOpenAIChatCompletion(w http.ResponseWriter, r *http.Request, router *agent.Router, sess *apiSession, messages []types.Message, model string, opts agent.ProcessOptions, includeUsage bool) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		gw.writeOpenAIError(w, http.StatusInternalServerError, "Streaming is not supported by the server", "server_error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	chunk := OpenAIStreamChunk{
+		ID:      generateCompletionID(),
+		Object:  "chat.completion.chunk",
+		Created: time.Now().Unix(),
+		Model:   model,
+	}
+	send := func(choices []OpenAIStreamChoice, usage *OpenAIUsage) {
+		chunk.Choices = choices
+		chunk.Usage = usage
+		data, _ := json.Marshal(chunk)
+		fmt.Fprintf(w, "data: %s\n\n", data)
+		flusher.Flush()
+	}
+	delta := func(d OpenAIDelta) []OpenAIStreamChoice {
+		return []OpenAIStreamChoice{{Index: 0, Delta: d}}
+	}
+
+	send(delta(OpenAIDelta{Role: "assistant"}), nil)
+
+	// Separate the text of agentic loop iterations like the final reply does
+	var blockEnded bool
+	opts.OnText = func(text string) {
+		if blockEnded {
+			text = "\n\n" + text
+			blockEnded = false
+		}
+		send(delta(OpenAIDelta{Content: text}), nil)
+	}
+	opts.OnIterationText = func(string) { blockEnded = true }
+
+	reply, err := router.ProcessWithOptions(messages, opts)
+	if err != nil {
+		if r.Context().Err() != nil {
+			gw.log.Info("📡 OpenAI API: client disconnected, request cancelled")
+			return
+		}
+		gw.log.Error("OpenAI API error: %v", err)
+		data, _ := json.Marshal(OpenAIErrorResponse{Error: OpenAIError{Message: "Failed to process request: " + err.Error(), Type: "server_error"}})
+		fmt.Fprintf(w, "data: %s\n\ndata: [DONE]\n\n", data)
+		flusher.Flush()
+		return
+	}
+
+	inputTokens, outputTokens := gw.trackAPIUsage("openai-compat", reply)
+	gw.recordAPIKeyUsage(r, inputTokens, outputTokens)
+	if sess != nil {
+		gw.finishAPITurn(sess, reply)
+	}
+
+	stop := "stop"
+	if toolCalls := openAIToolCalls(reply, true); len(toolCalls) > 0 {
+		send(delta(OpenAIDelta{ToolCalls: toolCalls}), nil)
+		stop = "tool_calls"
+	}
+	send([]OpenAIStreamChoice{{Index: 0, FinishReason: &stop}}, nil)
+	if includeUsage {
+		send([]OpenAIStreamChoice{}, &OpenAIUsage{
+			PromptTokens:     inputTokens,
+			CompletionTokens: outputTokens,
+			TotalTokens:      inputTokens + outputTokens,
+		})
+	}
+	fmt.Fprint(w, "data: [DONE]\n\n")
+	flusher.Flush()
+}
+
+// trackAPIUsage publishes an api.replied event for a reply of a compatible
+// API endpoint ("openai-compat" or "anthropic-compat") and returns its
+// token usage
+func (gw *Gateway) trackAPIUsage(endpoint string, reply *types.Message) (inputTokens, outputTokens int) {
+	// Extract usage from reply metadata
+	if reply != nil && reply.Metadata != nil {
+		inputTokens, _ = reply.Metadata["input_tokens"].(int)
+		outputTokens, _ = reply.Metadata["output_tokens"].(int)
+	}
+	gw.publish(events.APIReplied{Endpoint: endpoint, InputTokens: inputTokens, OutputTokens: outputTokens})
+	return inputTokens, outputTokens
+}
+
+// allowAPIRequest applies the rate limit of the request's API key, then the
+// agent rate limit to an API client, identified by the user it names or else
+// its address
+func (gw *Gateway) allowAPIRequest(r *http.Request, user string) bool {
+	if user == "" {
+		user = r.RemoteAddr
+		if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+			user = host
+		}
+	}
+	if !gw.allowAPIKey(r) {
+		return false
+	}
+	if !gw.limiter.Allow("api:" + user) {
+		gw.log.Info("⏱️ API client %s rate limited", user)
+		return false
+	}
+	return true
+}
+
+// handleOpenAIModels handles GET /v1/models
+func (gw *Gateway) handleOpenAIModels(w http.ResponseWriter, r *http.Request) {
+	r, ok := gw.authorize(w, r, apikey.ScopeChat)
+	if !ok {
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		gw.writeOpenAIError(w, http.StatusMethodNotAllowed, "Method not allowed", "invalid_request_error")
+		return
+	}
+
+	list := OpenAIModelList{Object: "list", Data: []OpenAIModel{}}
+	owner := gw.cfg.Agent.Provider
+	if owner == "" {
+		owner = "anthropic"
+	}
+	for _, id := range gw.configuredModels() {
+		if !allowAPIModel(r, id) {
+			continue
+		}
+		list.Data = append(list.Data, OpenAIModel{ID: id, Object: "model", OwnedBy: owner})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// configuredModels lists the models the API serves: the configured model and
+// fallback, plus the supported Claude models for the Anthropic provider
+func (gw *Gateway) configuredModels() []string {
+	var models []string
+	seen := make(map[string]bool)
+	add := func(id string) {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			models = append(models, id)
+		}
+	}
+
+	add(gw.defaultModel())
+	if gw.cfg.Agent.FallbackProvider == "" || gw.cfg.Agent.FallbackProvider == gw.cfg.Agent.Provider {
+		add(gw.cfg.Agent.FallbackModel)
+	}
+	if gw.cfg.Agent.Provider == "" || gw.cfg.Agent.Provider == "anthropic" {
+		for _, id := range session.SupportedModels() {
+			add(id)
+		}
+	}
+	return models
+}
+
+// defaultModel returns the configured model, or the provider's default
+func (gw *Gateway) defaultModel() string {
+	if gw.cfg.Agent.Model != "" {
+		return gw.cfg.Agent.Model
+	}
+	if gw.cfg.Agent.Provider == "openai" {
+		return "gpt-4o"
+	}
+	return "claude-sonnet-4-20250514"
+}
+
+// resolveAPIModel maps a requested model to one the configured provider
+// serves. Empty or unknown names use the configured model.
+func (gw *Gateway) resolveAPIModel(requested string) string {
+	isClaude := strings.HasPrefix(requested, "claude")
+
+	switch {
+	case requested == "":
+		return gw.defaultModel()
+	case gw.cfg.Agent.Provider == "openai":
+		if isClaude {
+			return gw.defaultModel()
+		}
+		return requested
+	case isClaude || strings.HasPrefix(requested, "gpt-"):
+		return mapToAnthropicModel(requested)
+	default:
+		return gw.defaultModel()
+	}
+}
+
+// openAIRequestParams converts the request's sampling parameters for the router
+func openAIRequestParams(req *OpenAIRequest, model string) agent.RequestParams {
+	maxTokens := req.MaxTokens
+	if req.MaxCompletionTokens > 0 {
+		maxTokens = req.MaxCompletionTokens
+	}
+	return agent.RequestParams{
+		Model:       model,
+		MaxTokens:   maxTokens,
+		Temperature: req.Temperature,
+		TopP:        req.TopP,
+		Stop:        req.Stop,
+	}
+}
+
+// replaceSystem reports whether a client's system prompt replaces
+// FeelPulse's with gateway.api.systemPrompt "replace". Without one, session
+// pins extend the configured prompt rather than replacing it
+func (gw *Gateway) replaceSystem(system string) bool {
+	return system != "" && gw.cfg.Gateway.API.SystemPrompt == "replace"
+}
+
+// apiServerTools reports whether an API request is offered FeelPulse's
+// tools: they must be enabled for the gateway and for the request's API key
+func (gw *Gateway) apiServerTools(r *http.Request) bool {
+	enabled := gw.cfg.Gateway.API.ServerTools == nil || *gw.cfg.Gateway.API.ServerTools
+	return enabled && allowAPIServerTools(r)
+}
+
+// openAIToolsToAnthropic converts client function definitions to tools
+func openAIToolsToAnthropic(tools []OpenAITool) ([]agent.AnthropicTool, error) {
+	var converted []agent.AnthropicTool
+	for _, tool := range tools {
+		if tool.Type != "function" {
+			return nil, fmt.Errorf("unsupported tool type '%s'", tool.Type)
+		}
+		if tool.Function.Name == "" {
+			return nil, fmt.Errorf("tool function name is required")
+		}
+
+		schema := tool.Function.Parameters
+		if len(schema) == 0 || string(schema) == "null" {
+			schema = json.RawMessage(`{"type":"object","properties":{}}`)
+		}
+		converted = append(converted, agent.AnthropicTool{
+			Name:        tool.Function.Name,
+			Description: tool.Function.Description,
+			InputSchema: schema,
+		})
+	}
+	return converted, nil
+}
+
+// openAIToolChoice converts the tool_choice parameter
+func openAIToolChoice(raw json.RawMessage) (*agent.AnthropicToolChoice, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+
+	var mode string
+	if err := json.Unmarshal(raw, &mode); err == nil {
+		switch mode {
+		case "auto":
+			return &agent.AnthropicToolChoice{Type: "auto"}, nil
+		case "none":
+			return &agent.AnthropicToolChoice{Type: "none"}, nil
+		case "required":
+			return &agent.AnthropicToolChoice{Type: "any"}, nil
+		}
+		return nil, fmt.Errorf("unsupported tool_choice '%s'", mode)
+	}
+
+	var named OpenAITool
+	if err := json.Unmarshal(raw, &named); err != nil || named.Function.Name == "" {
+		return nil, fmt.Errorf("tool_choice must be a string or name a function")
+	}
+	return &agent.AnthropicToolChoice{Type: "tool", Name: named.Function.Name}, nil
+}
+
+// openAIToolCalls returns the client tool calls of a reply in OpenAI format;
+// stream chunks number them
+func openAIToolCalls(reply *types.Message, indexed bool) []OpenAIToolCall {
+	calls, _ := reply.Metadata["tool_calls"].([]types.ToolCall)
+
+	var converted []OpenAIToolCall
+	for i, call := range calls {
+		arguments := string(call.Input)
+		if arguments == "" {
+			arguments = "{}"
+		}
+		toolCall := OpenAIToolCall{
+			ID:       call.ID,
+			Type:     "function",
+			Function: OpenAIFunctionCall{Name: call.Name, Arguments: arguments},
+		}
+		if indexed {
+			toolCall.Index = &i
+		}
+		converted = append(converted, toolCall)
+	}
+	return converted
+}
+
 // writeOpenAIError writes an error response in OpenAI format
 func (gw *Gateway) writeOpenAIError(w http.ResponseWriter, status int, message, errType string) {
 	w.Header().Set("Content-Type", "application/json")
@@ -193,17 +648,35 @@ func convertOpenAIToInternal(req *OpenAIRequest) ([]types.Message, string) {
 	var messages []types.Message
 
 	for _, msg := range req.Messages {
-		if msg.Role == "system" {
-			systemPrompt = msg.Content
+		if msg.Role == "system" || msg.Role == "developer" {
+			if systemPrompt != "" {
+				systemPrompt += "\n\n"
+			}
+			systemPrompt += msg.Content
 			continue
 		}
 
-		messages = append(messages, types.Message{
+		internal := types.Message{
 			Text:      msg.Content,
 			Channel:   "api",
 			IsBot:     msg.Role == "assistant",
 			Timestamp: time.Now(),
-		})
+		}
+		switch {
+		case msg.Role == "tool":
+			internal.Metadata = map[string]any{"tool_call_id": msg.ToolCallID}
+		case msg.Role == "assistant" && len(msg.ToolCalls) > 0:
+			calls := make([]types.ToolCall, 0, len(msg.ToolCalls))
+			for _, call := range msg.ToolCalls {
+				input := json.RawMessage(call.Function.Arguments)
+				if !json.Valid(input) {
+					input = json.RawMessage("{}")
+				}
+				calls = append(calls, types.ToolCall{ID: call.ID, Name: call.Function.Name, Input: input})
+			}
+			internal.Metadata = map[string]any{"tool_calls": calls}
+		}
+		messages = append(messages, internal)
 	}
 
 	return messages, systemPrompt
@@ -2,8 +2,14 @@ package gateway
 
 import (
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/FeelPulse/feelpulse/internal/config"
+	"github.com/FeelPulse/feelpulse/pkg/types"
 )
 
 func TestOpenAIRequest_Parse(t *testing.T) {
@@ -39,8 +45,8 @@ func TestOpenAIRequest_Parse(t *testing.T) {
 		t.Errorf("Expected max_tokens 100, got %d", req.MaxTokens)
 	}
 
-	if req.Temperature != 0.7 {
-		t.Errorf("Expected temperature 0.7, got %f", req.Temperature)
+	if req.Temperature == nil || *req.Temperature != 0.7 {
+		t.Errorf("Expected temperature 0.7, got %v", req.Temperature)
 	}
 }
 
@@ -208,3 +214,302 @@ func TestMapAnthropicModel(t *testing.T) {
 		})
 	}
 }
+
+func TestOpenAIRequest_ParseStreamOptions(t *testing.T) {
+	var req OpenAIRequest
+	data := `{"model": "gpt-4", "messages": [{"role": "user", "content": "Hi"}], "stream": true, "stream_options": {"include_usage": true}}`
+	if err := json.Unmarshal([]byte(data), &req); err != nil {
+		t.Fatalf("Failed to parse request: %v", err)
+	}
+
+	if !req.Stream || req.StreamOptions == nil || !req.StreamOptions.IncludeUsage {
+		t.Errorf("Expected streaming with usage, got stream=%v options=%+v", req.Stream, req.StreamOptions)
+	}
+}
+
+func TestOpenAIStreamChunk_Marshal(t *testing.T) {
+	chunk := OpenAIStreamChunk{
+		ID:      "chatcmpl-123",
+		Object:  "chat.completion.chunk",
+		Created: 1700000000,
+		Model:   "claude-sonnet-4-20250514",
+		Choices: []OpenAIStreamChoice{{Index: 0, Delta: OpenAIDelta{Content: "Hel"}}},
+	}
+
+	data, err := json.Marshal(chunk)
+	if err != nil {
+		t.Fatalf("Failed to marshal chunk: %v", err)
+	}
+
+	want := `{"id":"chatcmpl-123","object":"chat.completion.chunk","created":1700000000,"model":"claude-sonnet-4-20250514","choices":[{"index":0,"delta":{"content":"Hel"},"finish_reason":null}]}`
+	if string(data) != want {
+		t.Errorf("Unexpected chunk JSON:\n got: %s\nwant: %s", data, want)
+	}
+
+	// The usage chunk has no choices
+	chunk.Choices = []OpenAIStreamChoice{}
+	chunk.Usage = &OpenAIUsage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15}
+	data, _ = json.Marshal(chunk)
+	if !strings.Contains(string(data), `"choices":[],"usage":{"prompt_tokens":10,"completion_tokens":5,"total_tokens":15}`) {
+		t.Errorf("Unexpected usage chunk JSON: %s", data)
+	}
+}
+
+func TestOpenAIStop_Unmarshal(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want []string
+	}{
+		{"string", `{"stop": "END"}`, []string{"END"}},
+		{"array", `{"stop": ["END", "STOP"]}`, []string{"END", "STOP"}},
+		{"null", `{"stop": null}`, nil},
+		{"missing", `{}`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req OpenAIRequest
+			if err := json.Unmarshal([]byte(tt.json), &req); err != nil {
+				t.Fatalf("Unmarshal error: %v", err)
+			}
+			if strings.Join(req.Stop, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Stop = %v, want %v", req.Stop, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenAIRequestParams(t *testing.T) {
+	var req OpenAIRequest
+	data := `{"model": "gpt-4", "max_tokens": 100, "max_completion_tokens": 200, "temperature": 0, "top_p": 0.9, "stop": "END"}`
+	if err := json.Unmarshal([]byte(data), &req); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+
+	params := openAIRequestParams(&req, "claude-sonnet-4-20250514")
+	if params.Model != "claude-sonnet-4-20250514" || params.MaxTokens != 200 {
+		t.Errorf("unexpected params: %+v", params)
+	}
+	if params.Temperature == nil || *params.Temperature != 0 {
+		t.Error("explicit zero temperature should be kept")
+	}
+	if params.TopP == nil || *params.TopP != 0.9 || len(params.Stop) != 1 {
+		t.Errorf("unexpected params: %+v", params)
+	}
+}
+
+func TestResolveAPIModel(t *testing.T) {
+	tests := []struct {
+		name      string
+		provider  string
+		model     string
+		requested string
+		want      string
+	}{
+		{"empty uses configured", "anthropic", "claude-3-haiku-20240307", "", "claude-3-haiku-20240307"},
+		{"claude model passes through", "anthropic", "", "claude-opus-4-20250514", "claude-opus-4-20250514"},
+		{"gpt alias maps to claude", "anthropic", "", "gpt-4", "claude-sonnet-4-20250514"},
+		{"unknown uses configured", "anthropic", "claude-3-haiku-20240307", "llama-3", "claude-3-haiku-20240307"},
+		{"openai passes through", "openai", "gpt-4o", "gpt-4o-mini", "gpt-4o-mini"},
+		{"openai ignores claude", "openai", "gpt-4o", "claude-sonnet-4", "gpt-4o"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.Agent.Provider = tt.provider
+			cfg.Agent.Model = tt.model
+			gw := &Gateway{cfg: cfg}
+
+			if got := gw.resolveAPIModel(tt.requested); got != tt.want {
+				t.Errorf("resolveAPIModel(%q) = %q, want %q", tt.requested, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReplaceSystem(t *testing.T) {
+	tests := []struct {
+		name   string
+		mode   string
+		system string
+		want   bool
+	}{
+		{"extend", "extend", "Be terse.", false},
+		{"replace with client prompt", "replace", "Be terse.", true},
+		{"replace without client prompt", "replace", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.Gateway.API.SystemPrompt = tt.mode
+			gw := &Gateway{cfg: cfg}
+
+			// Session pins are joined later; they must not replace the prompt alone
+			if got := gw.replaceSystem(tt.system); got != tt.want {
+				t.Errorf("replaceSystem(%q) = %v, want %v", tt.system, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleOpenAIModels(t *testing.T) {
+	cfg := config.Default()
+	cfg.Agent.Provider = "openai"
+	cfg.Agent.Model = "gpt-4o"
+	cfg.Agent.FallbackModel = "gpt-4o-mini"
+	gw := &Gateway{cfg: cfg}
+
+	rec := httptest.NewRecorder()
+	gw.handleOpenAIModels(rec, httptest.NewRequest(http.MethodGet, "/v1/models", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+
+	var list OpenAIModelList
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if list.Object != "list" || len(list.Data) != 2 {
+		t.Fatalf("unexpected list: %+v", list)
+	}
+	if list.Data[0].ID != "gpt-4o" || list.Data[0].OwnedBy != "openai" || list.Data[1].ID != "gpt-4o-mini" {
+		t.Errorf("unexpected models: %+v", list.Data)
+	}
+
+	rec = httptest.NewRecorder()
+	gw.handleOpenAIModels(rec, httptest.NewRequest(http.MethodPost, "/v1/models", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST status = %d, want 405", rec.Code)
+	}
+}
+
+func TestConvertOpenAIToInternal_MultipleSystem(t *testing.T) {
+	req := &OpenAIRequest{Messages: []OpenAIMessage{
+		{Role: "system", Content: "Be brief."},
+		{Role: "developer", Content: "Answer in French."},
+		{Role: "user", Content: "Hello"},
+	}}
+
+	messages, system := convertOpenAIToInternal(req)
+	if system != "Be brief.\n\nAnswer in French." {
+		t.Errorf("system = %q", system)
+	}
+	if len(messages) != 1 {
+		t.Errorf("expected 1 message, got %d", len(messages))
+	}
+}
+
+func TestOpenAIToolsToAnthropic(t *testing.T) {
+	var req OpenAIRequest
+	data := `{"tools": [
+		{"type": "function", "function": {"name": "get_weather", "description": "Weather for a city", "parameters": {"type": "object", "properties": {"city": {"type": "string"}}}}},
+		{"type": "function", "function": {"name": "now"}}
+	]}`
+	if err := json.Unmarshal([]byte(data), &req); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+
+	converted, err := openAIToolsToAnthropic(req.Tools)
+	if err != nil {
+		t.Fatalf("openAIToolsToAnthropic error: %v", err)
+	}
+	if len(converted) != 2 || converted[0].Name != "get_weather" || converted[0].Description != "Weather for a city" {
+		t.Fatalf("unexpected tools: %+v", converted)
+	}
+	if !strings.Contains(string(converted[0].InputSchema), "city") {
+		t.Errorf("parameters should become the input schema, got %s", converted[0].InputSchema)
+	}
+	if string(converted[1].InputSchema) != `{"type":"object","properties":{}}` {
+		t.Errorf("missing parameters should get an empty object schema, got %s", converted[1].InputSchema)
+	}
+
+	if _, err := openAIToolsToAnthropic([]OpenAITool{{Type: "code_interpreter"}}); err == nil {
+		t.Error("expected an error for a non-function tool")
+	}
+}
+
+func TestOpenAIToolChoice(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		wantType string
+		wantName string
+		wantErr  bool
+	}{
+		{"unset", ``, "", "", false},
+		{"auto", `"auto"`, "auto", "", false},
+		{"none", `"none"`, "none", "", false},
+		{"required", `"required"`, "any", "", false},
+		{"named", `{"type": "function", "function": {"name": "get_weather"}}`, "tool", "get_weather", false},
+		{"unknown", `"sometimes"`, "", "", true},
+		{"unnamed", `{"type": "function"}`, "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			choice, err := openAIToolChoice(json.RawMessage(tt.raw))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantType == "" {
+				if choice != nil {
+					t.Errorf("expected no choice, got %+v", choice)
+				}
+				return
+			}
+			if choice == nil || choice.Type != tt.wantType || choice.Name != tt.wantName {
+				t.Errorf("choice = %+v, want %s/%s", choice, tt.wantType, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestConvertOpenAIToInternal_ToolMessages(t *testing.T) {
+	req := &OpenAIRequest{Messages: []OpenAIMessage{
+		{Role: "user", Content: "Weather in Paris?"},
+		{Role: "assistant", ToolCalls: []OpenAIToolCall{
+			{ID: "call_1", Type: "function", Function: OpenAIFunctionCall{Name: "get_weather", Arguments: `{"city":"Paris"}`}},
+			{ID: "call_2", Type: "function", Function: OpenAIFunctionCall{Name: "now", Arguments: ""}},
+		}},
+		{Role: "tool", ToolCallID: "call_1", Content: "18°C"},
+	}}
+
+	messages, _ := convertOpenAIToInternal(req)
+	if len(messages) != 3 {
+		t.Fatalf("expected 3 messages, got %d", len(messages))
+	}
+
+	calls, ok := messages[1].Metadata["tool_calls"].([]types.ToolCall)
+	if !ok || !messages[1].IsBot || len(calls) != 2 {
+		t.Fatalf("unexpected assistant message: %+v", messages[1])
+	}
+	if calls[0].Name != "get_weather" || string(calls[1].Input) != "{}" {
+		t.Errorf("unexpected tool calls: %+v", calls)
+	}
+	if messages[2].IsBot || messages[2].Metadata["tool_call_id"] != "call_1" {
+		t.Errorf("unexpected tool message: %+v", messages[2])
+	}
+}
+
+func TestOpenAIToolCalls(t *testing.T) {
+	reply := &types.Message{Metadata: map[string]any{"tool_calls": []types.ToolCall{
+		{ID: "toolu_1", Name: "get_weather", Input: json.RawMessage(`{"city":"Paris"}`)},
+	}}}
+
+	calls := openAIToolCalls(reply, false)
+	if len(calls) != 1 || calls[0].Type != "function" || calls[0].Function.Arguments != `{"city":"Paris"}` || calls[0].Index != nil {
+		t.Fatalf("unexpected calls: %+v", calls)
+	}
+
+	data, _ := json.Marshal(OpenAIDelta{ToolCalls: openAIToolCalls(reply, true)})
+	if !strings.Contains(string(data), `"index":0`) {
+		t.Errorf("stream tool calls should be indexed: %s", data)
+	}
+
+	if calls := openAIToolCalls(&types.Message{}, false); len(calls) != 0 {
+		t.Errorf("expected no calls, got %+v", calls)
+	}
+}
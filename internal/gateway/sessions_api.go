@@ -0,0 +1,674 @@
+package gateway
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/FeelPulse/feelpulse/internal/apikey"
+	"github.com/FeelPulse/feelpulse/internal/command"
+	"github.com/FeelPulse/feelpulse/internal/scheduler"
+	"github.com/FeelPulse/feelpulse/internal/session"
+	"github.com/FeelPulse/feelpulse/internal/subagent"
+	"github.com/FeelPulse/feelpulse/pkg/types"
+)
+
+const (
+	defaultSessionPageSize = 50
+	maxSessionPageSize     = 500
+	maxSessionAPIBodySize  = 64 * 1024
+)
+
+// SessionInfo describes a session in the session API
+type SessionInfo struct {
+	Key       string    `json:"key"`
+	Channel   string    `json:"channel"`
+	UserID    string    `json:"user_id"`
+	Messages  int       `json:"messages"`
+	Model     string    `json:"model,omitempty"`
+	Profile   string    `json:"profile,omitempty"`
+	TTS       *bool     `json:"tts,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SessionMessage is a message in the session API
+type SessionMessage struct {
+	ID        string    `json:"id,omitempty"`
+	Role      string    `json:"role"` // "user" or "assistant"
+	From      string    `json:"from,omitempty"`
+	Text      string    `json:"text"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// SessionReminder is a reminder in the session API
+type SessionReminder struct {
+	ID        string    `json:"id"`
+	Message   string    `json:"message"`
+	FireAt    time.Time `json:"fire_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SessionPin is a pinned note in the session API
+type SessionPin struct {
+	ID        string    `json:"id"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SessionSubAgent is a sub-agent in the session API
+type SessionSubAgent struct {
+	ID     string `json:"id"`
+	Label  string `json:"label"`
+	Task   string `json:"task"`
+	Status string `json:"status"`
+	Result string `json:"result,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// setupSessionRoutes registers the session management API
+func (gw *Gateway) setupSessionRoutes() {
+	routes := map[string]http.HandlerFunc{
+		"GET /api/sessions":                         gw.handleListSessions,
+		"GET /api/sessions/{key}":                   gw.handleGetSession,
+		"PATCH /api/sessions/{key}":                 gw.handleUpdateSession,
+		"DELETE /api/sessions/{key}":                gw.handleDeleteSession,
+		"GET /api/sessions/{key}/messages":          denyViewers(gw.handleSessionMessages),
+		"POST /api/sessions/{key}/clear":            gw.handleClearSession,
+		"POST /api/sessions/{key}/fork":             gw.handleForkSession,
+		"GET /api/sessions/{key}/export":            denyViewers(gw.handleExportSession),
+		"GET /api/sessions/{key}/reminders":         gw.handleListReminders,
+		"POST /api/sessions/{key}/reminders":        gw.handleAddReminder,
+		"DELETE /api/sessions/{key}/reminders/{id}": gw.handleCancelReminder,
+		"GET /api/sessions/{key}/pins":              gw.handleListPins,
+		"POST /api/sessions/{key}/pins":             gw.handleAddPin,
+		"DELETE /api/sessions/{key}/pins/{id}":      gw.handleRemovePin,
+		"GET /api/sessions/{key}/subagents":         gw.handleListSessionSubAgents,
+		"POST /api/sessions/{key}/subagents":        gw.handleSpawnSessionSubAgent,
+		"DELETE /api/sessions/{key}/subagents/{id}": gw.handleCancelSessionSubAgent,
+	}
+	for pattern, handler := range routes {
+		gw.mux.HandleFunc(pattern, gw.requireSessionAPIAuth(handler))
+	}
+}
+
+// requireSessionAPIAuth guards the session API, which needs the admin
+// scope. Unlike the chat endpoints it is never open: without an API key or
+// hooks.token or dashboard password it is disabled.
+func (gw *Gateway) requireSessionAPIAuth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !gw.authRequired(apikey.ScopeAdmin) {
+			writeAPIError(w, http.StatusForbidden, "session API requires an API key, hooks.token or dashboard password")
+			return
+		}
+		r, ok := gw.authorize(w, r, apikey.ScopeAdmin)
+		if !ok {
+			return
+		}
+		next(w, r)
+	}
+}
+
+// sessionFromRequest looks up the session named in the path. Writes 404
+// and returns nil if it does not exist.
+func (gw *Gateway) sessionFromRequest(w http.ResponseWriter, r *http.Request) *session.Session {
+	key := r.PathValue("key")
+	sess, ok := gw.sessions.GetSession(key)
+	if !ok {
+		writeAPIError(w, http.StatusNotFound, fmt.Sprintf("session '%s' not found", key))
+		return nil
+	}
+	return sess
+}
+
+func (gw *Gateway) handleListSessions(w http.ResponseWriter, r *http.Request) {
+	channel := r.URL.Query().Get("channel")
+
+	sessions := gw.sessions.GetRecent(gw.sessions.Count())
+	result := make([]SessionInfo, 0, len(sessions))
+	for _, sess := range sessions {
+		if channel != "" && sess.Channel() != channel {
+			continue
+		}
+		result = append(result, sessionInfo(sess))
+	}
+
+	offset, limit, err := pageParams(r)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	total := len(result)
+	result = page(result, offset, limit)
+
+	writeAPIJSON(w, http.StatusOK, map[string]any{
+		"sessions": result,
+		"total":    total,
+		"offset":   offset,
+		"limit":    limit,
+	})
+}
+
+func (gw *Gateway) handleGetSession(w http.ResponseWriter, r *http.Request) {
+	if sess := gw.sessionFromRequest(w, r); sess != nil {
+		writeAPIJSON(w, http.StatusOK, sessionInfo(sess))
+	}
+}
+
+// sessionUpdate changes a session's settings; "" resets to the default
+type sessionUpdate struct {
+	Model   *string `json:"model"`
+	Profile *string `json:"profile"`
+}
+
+func (gw *Gateway) handleUpdateSession(w http.ResponseWriter, r *http.Request) {
+	sess := gw.sessionFromRequest(w, r)
+	if sess == nil {
+		return
+	}
+
+	var req sessionUpdate
+	if !decodeAPIRequest(w, r, &req) {
+		return
+	}
+
+	if req.Model != nil && *req.Model != "" && !gw.isKnownModel(*req.Model) {
+		writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("unknown model '%s'", *req.Model))
+		return
+	}
+	if req.Profile != nil && *req.Profile != "" {
+		if _, ok := gw.cfg.Workspace.Profiles[*req.Profile]; !ok {
+			writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("unknown profile '%s'", *req.Profile))
+			return
+		}
+	}
+
+	if req.Model != nil {
+		sess.SetModel(*req.Model)
+	}
+	if req.Profile != nil {
+		sess.SetProfile(*req.Profile)
+	}
+	gw.sessions.Persist(sess.Channel(), sess.UserID())
+
+	gw.log.Info("🗂️ Session %s updated via API", sess.Key)
+	writeAPIJSON(w, http.StatusOK, sessionInfo(sess))
+}
+
+func (gw *Gateway) handleDeleteSession(w http.ResponseWriter, r *http.Request) {
+	sess := gw.sessionFromRequest(w, r)
+	if sess == nil {
+		return
+	}
+	gw.sessions.Delete(sess.Channel(), sess.UserID())
+	gw.log.Info("🗑️ Session %s deleted via API", sess.Key)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (gw *Gateway) handleSessionMessages(w http.ResponseWriter, r *http.Request) {
+	sess := gw.sessionFromRequest(w, r)
+	if sess == nil {
+		return
+	}
+
+	offset, limit, err := pageParams(r)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	messages := sess.GetAllMessages()
+	writeAPIJSON(w, http.StatusOK, map[string]any{
+		"messages": sessionMessages(page(messages, offset, limit)),
+		"total":    len(messages),
+		"offset":   offset,
+		"limit":    limit,
+	})
+}
+
+func (gw *Gateway) handleClearSession(w http.ResponseWriter, r *http.Request) {
+	sess := gw.sessionFromRequest(w, r)
+	if sess == nil {
+		return
+	}
+	gw.sessions.ClearAndPersist(sess.Channel(), sess.UserID())
+	gw.log.Info("🧹 Session %s cleared via API", sess.Key)
+	writeAPIJSON(w, http.StatusOK, sessionInfo(sess))
+}
+
+func (gw *Gateway) handleForkSession(w http.ResponseWriter, r *http.Request) {
+	sess := gw.sessionFromRequest(w, r)
+	if sess == nil {
+		return
+	}
+
+	var req struct {
+		Name string `json:"name"`
+	}
+	if r.ContentLength != 0 && !decodeAPIRequest(w, r, &req) {
+		return
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		b := make([]byte, 4)
+		rand.Read(b)
+		name = hex.EncodeToString(b)
+	}
+	if strings.ContainsAny(name, ":/ ") {
+		writeAPIError(w, http.StatusBadRequest, "invalid fork name: use alphanumeric characters only")
+		return
+	}
+
+	channel, userID := sess.Channel(), sess.UserID()
+	if _, exists := gw.sessions.GetSession(sess.Key + ":" + name); exists {
+		writeAPIError(w, http.StatusConflict, fmt.Sprintf("session '%s:%s' already exists", sess.Key, name))
+		return
+	}
+	fork, err := gw.sessions.Fork(channel, userID, name)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	gw.sessions.Persist(fork.Channel(), fork.UserID())
+
+	gw.log.Info("🍴 Session %s forked to %s via API", sess.Key, fork.Key)
+	writeAPIJSON(w, http.StatusCreated, sessionInfo(fork))
+}
+
+func (gw *Gateway) handleExportSession(w http.ResponseWriter, r *http.Request) {
+	sess := gw.sessionFromRequest(w, r)
+	if sess == nil {
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "text"
+	}
+
+	messages := sess.GetAllMessages()
+	var body []byte
+	var contentType, ext string
+	switch format {
+	case "text":
+		body, contentType, ext = []byte(command.FormatExport(messages)), "text/plain; charset=utf-8", "txt"
+	case "markdown":
+		body, contentType, ext = []byte(formatMarkdownExport(sess, messages)), "text/markdown; charset=utf-8", "md"
+	case "json":
+		var err error
+		body, err = json.MarshalIndent(map[string]any{
+			"session":  sessionInfo(sess),
+			"messages": sessionMessages(messages),
+		}, "", "  ")
+		if err != nil {
+			writeAPIError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		contentType, ext = "application/json", "json"
+	default:
+		writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("unknown format '%s': use text, markdown or json", format))
+		return
+	}
+
+	filename := fmt.Sprintf("feelpulse-%s-%s.%s", strings.ReplaceAll(sess.Key, ":", "-"), time.Now().Format("2006-01-02"), ext)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	w.Write(body)
+}
+
+func (gw *Gateway) handleListReminders(w http.ResponseWriter, r *http.Request) {
+	sess := gw.sessionFromRequest(w, r)
+	if sess == nil || !gw.requireScheduler(w) {
+		return
+	}
+
+	reminders := gw.scheduler.List(sess.Channel(), sess.UserID())
+	sort.Slice(reminders, func(i, j int) bool { return reminders[i].FireAt.Before(reminders[j].FireAt) })
+
+	result := make([]SessionReminder, len(reminders))
+	for i, rem := range reminders {
+		result[i] = sessionReminder(rem)
+	}
+	writeAPIJSON(w, http.StatusOK, map[string]any{"reminders": result})
+}
+
+func (gw *Gateway) handleAddReminder(w http.ResponseWriter, r *http.Request) {
+	sess := gw.sessionFromRequest(w, r)
+	if sess == nil || !gw.requireScheduler(w) {
+		return
+	}
+
+	var req struct {
+		In      string    `json:"in"` // e.g. "30m", "2h", "1d"
+		At      time.Time `json:"at"` // RFC 3339; used if "in" is empty
+		Message string    `json:"message"`
+	}
+	if !decodeAPIRequest(w, r, &req) {
+		return
+	}
+
+	var in time.Duration
+	switch {
+	case req.In != "":
+		d, err := scheduler.ParseDuration(req.In)
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("invalid duration: %v", err))
+			return
+		}
+		in = d
+	case !req.At.IsZero():
+		in = time.Until(req.At)
+	default:
+		writeAPIError(w, http.StatusBadRequest, "either in or at is required")
+		return
+	}
+
+	id, err := gw.scheduler.AddReminder(sess.Channel(), sess.UserID(), in, strings.TrimSpace(req.Message))
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	for _, rem := range gw.scheduler.List(sess.Channel(), sess.UserID()) {
+		if rem.ID == id {
+			writeAPIJSON(w, http.StatusCreated, sessionReminder(rem))
+			return
+		}
+	}
+	writeAPIJSON(w, http.StatusCreated, SessionReminder{ID: id})
+}
+
+func (gw *Gateway) handleCancelReminder(w http.ResponseWriter, r *http.Request) {
+	sess := gw.sessionFromRequest(w, r)
+	if sess == nil || !gw.requireScheduler(w) {
+		return
+	}
+
+	id := r.PathValue("id")
+	for _, rem := range gw.scheduler.List(sess.Channel(), sess.UserID()) {
+		if rem.ID == id && gw.scheduler.Cancel(id) {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+	writeAPIError(w, http.StatusNotFound, fmt.Sprintf("reminder '%s' not found", id))
+}
+
+func (gw *Gateway) handleListPins(w http.ResponseWriter, r *http.Request) {
+	sess := gw.sessionFromRequest(w, r)
+	if sess == nil || !gw.requirePins(w) {
+		return
+	}
+
+	pins := gw.pinManager.ListPins(sess.Key)
+	result := make([]SessionPin, len(pins))
+	for i, p := range pins {
+		result[i] = SessionPin{ID: p.ID, Text: p.Text, CreatedAt: p.CreatedAt}
+	}
+	writeAPIJSON(w, http.StatusOK, map[string]any{"pins": result})
+}
+
+func (gw *Gateway) handleAddPin(w http.ResponseWriter, r *http.Request) {
+	sess := gw.sessionFromRequest(w, r)
+	if sess == nil || !gw.requirePins(w) {
+		return
+	}
+
+	var req struct {
+		Text string `json:"text"`
+	}
+	if !decodeAPIRequest(w, r, &req) {
+		return
+	}
+	text := strings.TrimSpace(req.Text)
+	if text == "" {
+		writeAPIError(w, http.StatusBadRequest, "text is required")
+		return
+	}
+
+	id, err := gw.pinManager.AddPin(sess.Key, text)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, fmt.Sprintf("failed to add pin: %v", err))
+		return
+	}
+	writeAPIJSON(w, http.StatusCreated, SessionPin{ID: id, Text: text, CreatedAt: time.Now()})
+}
+
+func (gw *Gateway) handleRemovePin(w http.ResponseWriter, r *http.Request) {
+	sess := gw.sessionFromRequest(w, r)
+	if sess == nil || !gw.requirePins(w) {
+		return
+	}
+
+	id := r.PathValue("id")
+	for _, p := range gw.pinManager.ListPins(sess.Key) {
+		if p.ID != id {
+			continue
+		}
+		if err := gw.pinManager.RemovePin(id); err != nil {
+			writeAPIError(w, http.StatusInternalServerError, fmt.Sprintf("failed to remove pin: %v", err))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeAPIError(w, http.StatusNotFound, fmt.Sprintf("pin '%s' not found", id))
+}
+
+func (gw *Gateway) handleListSessionSubAgents(w http.ResponseWriter, r *http.Request) {
+	sess := gw.sessionFromRequest(w, r)
+	if sess == nil || !gw.requireSubAgents(w) {
+		return
+	}
+
+	result := []SessionSubAgent{}
+	for _, sa := range gw.subagentManager.List() {
+		if sa.ParentSessionKey == sess.Key {
+			result = append(result, sessionSubAgent(sa))
+		}
+	}
+	writeAPIJSON(w, http.StatusOK, map[string]any{"subagents": result})
+}
+
+func (gw *Gateway) handleSpawnSessionSubAgent(w http.ResponseWriter, r *http.Request) {
+	sess := gw.sessionFromRequest(w, r)
+	if sess == nil || !gw.requireSubAgents(w) {
+		return
+	}
+
+	var req struct {
+		Task         string `json:"task"`
+		Label        string `json:"label"`
+		SystemPrompt string `json:"system_prompt"`
+	}
+	if !decodeAPIRequest(w, r, &req) {
+		return
+	}
+	if strings.TrimSpace(req.Task) == "" || strings.TrimSpace(req.Label) == "" {
+		writeAPIError(w, http.StatusBadRequest, "task and label are required")
+		return
+	}
+	if req.SystemPrompt == "" {
+		req.SystemPrompt = defaultSubAgentPrompt
+	}
+
+	runner := subagent.NewSimpleRunner(gw.createSubAgentChatFunc(), subagent.DefaultMaxIterations)
+	id := gw.subagentManager.Spawn(req.Task, req.Label, req.SystemPrompt, sess.Key, runner, gw.toolRegistry)
+	gw.log.Info("🤖 Sub-agent '%s' (%s) spawned via API for %s", req.Label, id, sess.Key)
+
+	sa, _ := gw.subagentManager.Get(id)
+	writeAPIJSON(w, http.StatusCreated, sessionSubAgent(sa))
+}
+
+func (gw *Gateway) handleCancelSessionSubAgent(w http.ResponseWriter, r *http.Request) {
+	sess := gw.sessionFromRequest(w, r)
+	if sess == nil || !gw.requireSubAgents(w) {
+		return
+	}
+
+	id := r.PathValue("id")
+	sa, ok := gw.subagentManager.Get(id)
+	if !ok || sa.ParentSessionKey != sess.Key {
+		writeAPIError(w, http.StatusNotFound, fmt.Sprintf("sub-agent '%s' not found", id))
+		return
+	}
+	if err := gw.subagentManager.Cancel(id); err != nil {
+		writeAPIError(w, http.StatusConflict, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// requireScheduler writes 503 and returns false if reminders are unavailable
+func (gw *Gateway) requireScheduler(w http.ResponseWriter) bool {
+	if gw.scheduler == nil {
+		writeAPIError(w, http.StatusServiceUnavailable, "reminders are not available")
+		return false
+	}
+	return true
+}
+
+// requirePins writes 503 and returns false if pins are unavailable
+func (gw *Gateway) requirePins(w http.ResponseWriter) bool {
+	if gw.pinManager == nil {
+		writeAPIError(w, http.StatusServiceUnavailable, "pins are not available (no database)")
+		return false
+	}
+	return true
+}
+
+// requireSubAgents writes 503 and returns false if sub-agents are unavailable
+func (gw *Gateway) requireSubAgents(w http.ResponseWriter) bool {
+	if gw.subagentManager == nil {
+		writeAPIError(w, http.StatusServiceUnavailable, "sub-agents are not available")
+		return false
+	}
+	return true
+}
+
+// isKnownModel reports whether a model can be set on a session
+func (gw *Gateway) isKnownModel(model string) bool {
+	if session.ValidateModel(model) {
+		return true
+	}
+	for _, m := range gw.configuredModels() {
+		if m == model {
+			return true
+		}
+	}
+	return false
+}
+
+func sessionInfo(sess *session.Session) SessionInfo {
+	return SessionInfo{
+		Key:       sess.Key,
+		Channel:   sess.Channel(),
+		UserID:    sess.UserID(),
+		Messages:  sess.Len(),
+		Model:     sess.GetModel(),
+		Profile:   sess.GetProfile(),
+		TTS:       sess.GetTTS(),
+		CreatedAt: sess.CreatedAt,
+		UpdatedAt: sess.UpdatedAt,
+	}
+}
+
+func sessionMessages(messages []types.Message) []SessionMessage {
+	result := make([]SessionMessage, len(messages))
+	for i, m := range messages {
+		role := "user"
+		if m.IsBot {
+			role = "assistant"
+		}
+		result[i] = SessionMessage{ID: m.ID, Role: role, From: m.From, Text: m.Text, Timestamp: m.Timestamp}
+	}
+	return result
+}
+
+func sessionReminder(r *scheduler.Reminder) SessionReminder {
+	return SessionReminder{ID: r.ID, Message: r.Message, FireAt: r.FireAt, CreatedAt: r.Created}
+}
+
+func sessionSubAgent(sa *subagent.SubAgent) SessionSubAgent {
+	if sa == nil {
+		return SessionSubAgent{}
+	}
+	status, label, task, result, errMsg := sa.GetInfo()
+	return SessionSubAgent{ID: sa.ID, Label: label, Task: task, Status: status, Result: result, Error: errMsg}
+}
+
+// formatMarkdownExport formats a conversation as a Markdown document
+func formatMarkdownExport(sess *session.Session, messages []types.Message) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# Conversation %s\n\n", sess.Key))
+	sb.WriteString(fmt.Sprintf("_Exported %s · %d messages_\n\n", time.Now().Format(time.RFC3339), len(messages)))
+
+	for _, m := range messages {
+		role := "User"
+		if m.IsBot {
+			role = "Assistant"
+		}
+		sb.WriteString(fmt.Sprintf("## %s · %s\n\n%s\n\n", role, m.Timestamp.Format("2006-01-02 15:04:05"), m.Text))
+	}
+	return sb.String()
+}
+
+// pageParams parses the offset and limit query parameters
+func pageParams(r *http.Request) (offset, limit int, err error) {
+	limit = defaultSessionPageSize
+	q := r.URL.Query()
+	if v := q.Get("offset"); v != "" {
+		if offset, err = strconv.Atoi(v); err != nil || offset < 0 {
+			return 0, 0, fmt.Errorf("invalid offset '%s'", v)
+		}
+	}
+	if v := q.Get("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil || limit <= 0 {
+			return 0, 0, fmt.Errorf("invalid limit '%s'", v)
+		}
+	}
+	if limit > maxSessionPageSize {
+		limit = maxSessionPageSize
+	}
+	return offset, limit, nil
+}
+
+// page returns items[offset:offset+limit], clamped to the slice
+func page[T any](items []T, offset, limit int) []T {
+	if offset >= len(items) {
+		return []T{}
+	}
+	end := offset + limit
+	if end > len(items) {
+		end = len(items)
+	}
+	return items[offset:end]
+}
+
+// decodeAPIRequest decodes a JSON request body. Writes 400 and returns
+// false if it is invalid.
+func decodeAPIRequest(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxSessionAPIBodySize)).Decode(v); err != nil {
+		writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+		return false
+	}
+	return true
+}
+
+// writeAPIJSON writes a JSON response
+func writeAPIJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeAPIError writes a JSON error response
+func writeAPIError(w http.ResponseWriter, status int, message string) {
+	writeAPIJSON(w, status, map[string]string{"error": message})
+}
@@ -0,0 +1,188 @@
+package gateway
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FeelPulse/feelpulse/internal/config"
+	"github.com/FeelPulse/feelpulse/internal/logger"
+	"github.com/FeelPulse/feelpulse/internal/scheduler"
+	"github.com/FeelPulse/feelpulse/internal/session"
+	"github.com/FeelPulse/feelpulse/internal/store"
+	"github.com/FeelPulse/feelpulse/internal/subagent"
+	"github.com/FeelPulse/feelpulse/pkg/types"
+)
+
+func newSessionAPIGateway(t *testing.T) *Gateway {
+	t.Helper()
+
+	db, err := store.NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	log := logger.New(&logger.Config{Level: "error"})
+	pins, err := newPinManager(db, log)
+	if err != nil {
+		t.Fatalf("newPinManager error: %v", err)
+	}
+
+	cfg := config.Default()
+	cfg.Hooks.Token = "token"
+	cfg.Workspace.Profiles = map[string]string{"formal": "/tmp/formal.md"}
+	gw := &Gateway{
+		cfg:             cfg,
+		mux:             http.NewServeMux(),
+		sessions:        session.NewStore(),
+		db:              db,
+		log:             log,
+		pinManager:      pins,
+		scheduler:       scheduler.New(),
+		subagentManager: subagent.NewManager(nil),
+	}
+	gw.setupSessionRoutes()
+
+	sess := gw.sessions.GetOrCreate("telegram", "42")
+	for i, text := range []string{"Hello", "Hi there!", "What's up?"} {
+		sess.AddMessage(types.Message{ID: string(rune('a' + i)), Text: text, IsBot: i%2 == 1, Timestamp: time.Now()})
+	}
+	gw.sessions.GetOrCreate("api", "alice")
+	return gw
+}
+
+// doSessionAPI sends an authenticated request to the session API
+func doSessionAPI(gw *Gateway, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+	gw.mux.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestSessionAPI_Auth(t *testing.T) {
+	gw := newSessionAPIGateway(t)
+
+	rec := httptest.NewRecorder()
+	gw.mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/sessions", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("without token: status = %d, want 401", rec.Code)
+	}
+
+	gw.cfg.Hooks.Token = ""
+	rec = httptest.NewRecorder()
+	gw.mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/sessions", nil))
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("without hooks.token: status = %d, want 403", rec.Code)
+	}
+}
+
+func TestSessionAPI_Sessions(t *testing.T) {
+	gw := newSessionAPIGateway(t)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+		want   string // Substring of the response body
+	}{
+		{"list", "GET", "/api/sessions", "", 200, `"total":2`},
+		{"list by channel", "GET", "/api/sessions?channel=api", "", 200, `"key":"api:alice"`},
+		{"get", "GET", "/api/sessions/telegram:42", "", 200, `"messages":3`},
+		{"get missing", "GET", "/api/sessions/telegram:1", "", 404, "not found"},
+		{"messages page", "GET", "/api/sessions/telegram:42/messages?offset=1&limit=1", "", 200, `"role":"assistant","text":"Hi there!"`},
+		{"messages bad limit", "GET", "/api/sessions/telegram:42/messages?limit=x", "", 400, "invalid limit"},
+		{"set model", "PATCH", "/api/sessions/telegram:42", `{"model": "claude-3-haiku-20240307"}`, 200, `"model":"claude-3-haiku-20240307"`},
+		{"unknown model", "PATCH", "/api/sessions/telegram:42", `{"model": "gpt-2"}`, 400, "unknown model"},
+		{"set profile", "PATCH", "/api/sessions/telegram:42", `{"profile": "formal"}`, 200, `"profile":"formal"`},
+		{"unknown profile", "PATCH", "/api/sessions/telegram:42", `{"profile": "pirate"}`, 400, "unknown profile"},
+		{"export text", "GET", "/api/sessions/telegram:42/export", "", 200, "AI: Hi there!"},
+		{"export markdown", "GET", "/api/sessions/telegram:42/export?format=markdown", "", 200, "## Assistant"},
+		{"export json", "GET", "/api/sessions/telegram:42/export?format=json", "", 200, `"text": "What's up?"`},
+		{"export unknown", "GET", "/api/sessions/telegram:42/export?format=pdf", "", 400, "unknown format"},
+		{"fork", "POST", "/api/sessions/telegram:42/fork", `{"name": "work"}`, 201, `"key":"telegram:42:work"`},
+		{"fork exists", "POST", "/api/sessions/telegram:42/fork", `{"name": "work"}`, 409, "already exists"},
+		{"fork invalid name", "POST", "/api/sessions/telegram:42/fork", `{"name": "a b"}`, 400, "invalid fork name"},
+		{"get fork", "GET", "/api/sessions/telegram:42:work", "", 200, `"messages":3`},
+		{"clear", "POST", "/api/sessions/telegram:42/clear", "", 200, `"messages":0`},
+		{"delete", "DELETE", "/api/sessions/api:alice", "", 204, ""},
+		{"deleted", "GET", "/api/sessions/api:alice", "", 404, "not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := doSessionAPI(gw, tt.method, tt.path, tt.body)
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body.String())
+			}
+			if !strings.Contains(rec.Body.String(), tt.want) {
+				t.Errorf("body %s does not contain %s", rec.Body.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestSessionAPI_SubResources(t *testing.T) {
+	gw := newSessionAPIGateway(t)
+
+	// Reminders
+	rec := doSessionAPI(gw, "POST", "/api/sessions/telegram:42/reminders", `{"in": "2h", "message": "Stretch"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("add reminder: status = %d: %s", rec.Code, rec.Body.String())
+	}
+	var reminder SessionReminder
+	_ = json.Unmarshal(rec.Body.Bytes(), &reminder)
+	if reminder.Message != "Stretch" || time.Until(reminder.FireAt) < time.Hour {
+		t.Errorf("unexpected reminder: %+v", reminder)
+	}
+	if rec := doSessionAPI(gw, "POST", "/api/sessions/telegram:42/reminders", `{"message": "Stretch"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("reminder without time: status = %d, want 400", rec.Code)
+	}
+	if rec := doSessionAPI(gw, "GET", "/api/sessions/telegram:42/reminders", ""); !strings.Contains(rec.Body.String(), reminder.ID) {
+		t.Errorf("reminder not listed: %s", rec.Body.String())
+	}
+	if rec := doSessionAPI(gw, "DELETE", "/api/sessions/api:alice/reminders/"+reminder.ID, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("cancel from another session: status = %d, want 404", rec.Code)
+	}
+	if rec := doSessionAPI(gw, "DELETE", "/api/sessions/telegram:42/reminders/"+reminder.ID, ""); rec.Code != http.StatusNoContent {
+		t.Errorf("cancel reminder: status = %d, want 204", rec.Code)
+	}
+
+	// Pins
+	rec = doSessionAPI(gw, "POST", "/api/sessions/telegram:42/pins", `{"text": "Prefers metric units"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("add pin: status = %d: %s", rec.Code, rec.Body.String())
+	}
+	var pin SessionPin
+	_ = json.Unmarshal(rec.Body.Bytes(), &pin)
+	if rec := doSessionAPI(gw, "GET", "/api/sessions/telegram:42/pins", ""); !strings.Contains(rec.Body.String(), "Prefers metric units") {
+		t.Errorf("pin not listed: %s", rec.Body.String())
+	}
+	if rec := doSessionAPI(gw, "POST", "/api/sessions/telegram:42/pins", `{"text": " "}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("empty pin: status = %d, want 400", rec.Code)
+	}
+	if rec := doSessionAPI(gw, "DELETE", "/api/sessions/telegram:42/pins/"+pin.ID, ""); rec.Code != http.StatusNoContent {
+		t.Errorf("remove pin: status = %d, want 204", rec.Code)
+	}
+	if rec := doSessionAPI(gw, "DELETE", "/api/sessions/telegram:42/pins/"+pin.ID, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("remove removed pin: status = %d, want 404", rec.Code)
+	}
+
+	// Sub-agents
+	if rec := doSessionAPI(gw, "GET", "/api/sessions/telegram:42/subagents", ""); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"subagents":[]`) {
+		t.Errorf("list sub-agents: %d %s", rec.Code, rec.Body.String())
+	}
+	if rec := doSessionAPI(gw, "POST", "/api/sessions/telegram:42/subagents", `{"task": "Research"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("spawn without label: status = %d, want 400", rec.Code)
+	}
+	if rec := doSessionAPI(gw, "DELETE", "/api/sessions/telegram:42/subagents/sa-missing", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("cancel unknown sub-agent: status = %d, want 404", rec.Code)
+	}
+}
@@ -0,0 +1,295 @@
+// Package inbox stores files uploaded through chat channels in the workspace
+// and extracts their text so the agent can work with them
+package inbox
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// DirName is the workspace subdirectory holding uploaded files
+const DirName = "inbox"
+
+// PreviewLength is the number of characters of extracted text shown to the agent
+const PreviewLength = 1500
+
+// maxZipEntries limits the archive listing included in the description
+const maxZipEntries = 50
+
+// DefaultAllowedTypes lists the MIME types and extensions accepted by default
+var DefaultAllowedTypes = []string{
+	"text/*",
+	"application/pdf",
+	"application/json",
+	"application/xml",
+	"application/zip",
+	"application/x-yaml",
+	".md", ".csv", ".txt", ".json", ".yaml", ".yml", ".toml", ".xml",
+	".go", ".py", ".js", ".ts", ".rs", ".java", ".c", ".h", ".cpp", ".rb", ".sh", ".sql",
+	".pdf", ".zip",
+}
+
+// textExtensions are treated as text regardless of the reported MIME type
+var textExtensions = map[string]bool{
+	".md": true, ".csv": true, ".txt": true, ".json": true, ".yaml": true, ".yml": true,
+	".toml": true, ".xml": true, ".html": true, ".go": true, ".py": true, ".js": true,
+	".ts": true, ".rs": true, ".java": true, ".c": true, ".h": true, ".cpp": true,
+	".rb": true, ".sh": true, ".sql": true, ".log": true, ".ini": true, ".env": true,
+}
+
+// Inbox saves uploaded files under <workspace>/inbox/<owner>/
+type Inbox struct {
+	workspace    string
+	maxSize      int64
+	allowedTypes []string
+}
+
+// File describes a stored upload
+type File struct {
+	Name      string // Original filename
+	Path      string // Absolute path on disk
+	RelPath   string // Path relative to the workspace (for file_read)
+	TextPath  string // Relative path of extracted text sidecar, if any
+	MimeType  string
+	Size      int64
+	Text      string // Extracted text or archive listing (may be empty)
+	Truncated bool   // Text was cut to PreviewLength
+}
+
+// New creates an inbox in the given workspace. maxSize of 0 means no limit;
+// an empty allowlist accepts DefaultAllowedTypes.
+func New(workspace string, maxSize int64, allowedTypes []string) *Inbox {
+	if len(allowedTypes) == 0 {
+		allowedTypes = DefaultAllowedTypes
+	}
+	return &Inbox{
+		workspace:    workspace,
+		maxSize:      maxSize,
+		allowedTypes: allowedTypes,
+	}
+}
+
+// Allowed reports whether a file matches the allowlist by MIME type
+// (exact or "type/*" wildcard) or by extension (".csv")
+func (in *Inbox) Allowed(filename, mimeType string) bool {
+	ext := strings.ToLower(filepath.Ext(filename))
+	mimeType = strings.ToLower(mimeType)
+
+	for _, allowed := range in.allowedTypes {
+		allowed = strings.ToLower(strings.TrimSpace(allowed))
+		switch {
+		case strings.HasPrefix(allowed, "."):
+			if ext == allowed {
+				return true
+			}
+		case strings.HasSuffix(allowed, "/*"):
+			if mimeType != "" && strings.HasPrefix(mimeType, strings.TrimSuffix(allowed, "*")) {
+				return true
+			}
+		case allowed == mimeType:
+			return true
+		}
+	}
+	return false
+}
+
+// Check validates an upload before it is downloaded.
+// The returned error is suitable for showing to the user.
+func (in *Inbox) Check(filename, mimeType string, size int64) error {
+	if in.maxSize > 0 && size > in.maxSize {
+		return fmt.Errorf("file is too large (%s, max %s)", FormatSize(size), FormatSize(in.maxSize))
+	}
+	if !in.Allowed(filename, mimeType) {
+		return fmt.Errorf("file type not accepted (%s)", describeType(filename, mimeType))
+	}
+	return nil
+}
+
+// Save writes the file to the owner's inbox and extracts its text
+func (in *Inbox) Save(owner, filename, mimeType string, data []byte) (*File, error) {
+	if err := in.Check(filename, mimeType, int64(len(data))); err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Join(in.workspace, DirName, sanitizeName(owner))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create inbox: %w", err)
+	}
+
+	name := sanitizeName(filepath.Base(filename))
+	if name == "" {
+		name = "upload"
+	}
+	stored := time.Now().Format("20060102-150405") + "-" + name
+	path := filepath.Join(dir, stored)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to save file: %w", err)
+	}
+
+	rel, _ := filepath.Rel(in.workspace, path)
+	f := &File{
+		Name:     filename,
+		Path:     path,
+		RelPath:  filepath.ToSlash(rel),
+		MimeType: mimeType,
+		Size:     int64(len(data)),
+	}
+
+	text, sidecar := extractText(path, mimeType, data)
+	if sidecar && text != "" {
+		// Keep the full extracted text next to the original for file_read
+		if err := os.WriteFile(path+".txt", []byte(text), 0644); err == nil {
+			f.TextPath = f.RelPath + ".txt"
+		}
+	}
+	if utf8.RuneCountInString(text) > PreviewLength {
+		text = string([]rune(text)[:PreviewLength])
+		f.Truncated = true
+	}
+	f.Text = text
+
+	return f, nil
+}
+
+// Ingest saves an upload and returns a description for the agent
+func (in *Inbox) Ingest(owner, filename, mimeType string, data []byte) (string, error) {
+	f, err := in.Save(owner, filename, mimeType, data)
+	if err != nil {
+		return "", err
+	}
+	return f.Describe(), nil
+}
+
+// Describe formats the upload as a message telling the agent where the file
+// is and what it contains
+func (f *File) Describe() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("📎 Uploaded file: %s (%s", f.Name, FormatSize(f.Size)))
+	if f.MimeType != "" {
+		sb.WriteString(", " + f.MimeType)
+	}
+	sb.WriteString(")\n")
+	sb.WriteString(fmt.Sprintf("Saved to workspace: %s\n", f.RelPath))
+	if f.TextPath != "" {
+		sb.WriteString(fmt.Sprintf("Extracted text: %s\n", f.TextPath))
+	}
+
+	if f.Text != "" {
+		sb.WriteString("\nPreview:\n```\n")
+		sb.WriteString(f.Text)
+		if f.Truncated {
+			sb.WriteString("\n…")
+		}
+		sb.WriteString("\n```\n")
+	} else {
+		sb.WriteString("\n(No text preview available for this file type.)\n")
+	}
+
+	sb.WriteString("\nUse file_read to see the full contents.")
+	return sb.String()
+}
+
+// extractText returns the readable text of a file and whether it should be
+// stored as a sidecar (i.e. it is not the file itself)
+func extractText(path, mimeType string, data []byte) (string, bool) {
+	ext := strings.ToLower(filepath.Ext(path))
+
+	switch {
+	case ext == ".pdf" || mimeType == "application/pdf":
+		return extractPDF(path), true
+	case ext == ".zip" || mimeType == "application/zip":
+		return listZip(data), false
+	case isText(ext, mimeType) && utf8.Valid(data):
+		return string(data), false
+	}
+	return "", false
+}
+
+// isText reports whether a file should be read as plain text
+func isText(ext, mimeType string) bool {
+	if textExtensions[ext] || strings.HasPrefix(mimeType, "text/") {
+		return true
+	}
+	switch mimeType {
+	case "application/json", "application/xml", "application/x-yaml", "application/javascript":
+		return true
+	}
+	return false
+}
+
+// extractPDF converts a PDF to text with pdftotext (poppler-utils), if installed
+func extractPDF(path string) string {
+	if _, err := exec.LookPath("pdftotext"); err != nil {
+		return ""
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, "pdftotext", "-layout", "-q", path, "-").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// listZip returns a listing of the archive's entries
+func listZip(data []byte) string {
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Archive with %d entries:\n", len(r.File)))
+	for i, f := range r.File {
+		if i == maxZipEntries {
+			sb.WriteString(fmt.Sprintf("… and %d more\n", len(r.File)-maxZipEntries))
+			break
+		}
+		sb.WriteString(fmt.Sprintf("%s (%s)\n", f.Name, FormatSize(int64(f.UncompressedSize64))))
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// unsafeNameRe matches characters not allowed in stored filenames
+var unsafeNameRe = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// sanitizeName makes a string safe to use as a single path element
+func sanitizeName(name string) string {
+	name = unsafeNameRe.ReplaceAllString(name, "_")
+	name = strings.Trim(name, "._")
+	return name
+}
+
+// describeType returns the MIME type or extension for error messages
+func describeType(filename, mimeType string) string {
+	if mimeType != "" {
+		return mimeType
+	}
+	if ext := filepath.Ext(filename); ext != "" {
+		return ext
+	}
+	return "unknown"
+}
+
+// FormatSize formats a byte count for display (e.g. "1.5 MB")
+func FormatSize(n int64) string {
+	switch {
+	case n >= 1024*1024:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1024*1024))
+	case n >= 1024:
+		return fmt.Sprintf("%.1f KB", float64(n)/1024)
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
@@ -0,0 +1,133 @@
+package inbox
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAllowed(t *testing.T) {
+	in := New(t.TempDir(), 0, []string{"text/*", "application/pdf", ".go"})
+
+	tests := []struct {
+		name     string
+		filename string
+		mimeType string
+		expected bool
+	}{
+		{"mime wildcard", "notes.txt", "text/plain", true},
+		{"exact mime", "report.pdf", "application/pdf", true},
+		{"extension", "main.go", "application/octet-stream", true},
+		{"extension case-insensitive", "MAIN.GO", "", true},
+		{"rejected", "photo.exe", "application/x-msdownload", false},
+		{"no mime no extension", "README", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := in.Allowed(tt.filename, tt.mimeType); got != tt.expected {
+				t.Errorf("Allowed(%q, %q) = %v, want %v", tt.filename, tt.mimeType, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCheck_Size(t *testing.T) {
+	in := New(t.TempDir(), 1024, nil)
+	if err := in.Check("a.txt", "text/plain", 2048); err == nil || !strings.Contains(err.Error(), "too large") {
+		t.Errorf("expected size error, got %v", err)
+	}
+	if err := in.Check("a.txt", "text/plain", 512); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestSave_TextFile(t *testing.T) {
+	workspace := t.TempDir()
+	in := New(workspace, 0, nil)
+
+	f, err := in.Save("12345", "../../etc/notes.md", "text/markdown", []byte("# Notes\nhello"))
+	if err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	if !strings.HasPrefix(f.RelPath, "inbox/12345/") || !strings.HasSuffix(f.RelPath, "-notes.md") {
+		t.Errorf("unexpected RelPath %q", f.RelPath)
+	}
+	if !strings.HasPrefix(f.Path, filepath.Join(workspace, DirName)) {
+		t.Errorf("file saved outside inbox: %s", f.Path)
+	}
+	data, err := os.ReadFile(f.Path)
+	if err != nil || string(data) != "# Notes\nhello" {
+		t.Errorf("stored content = %q, %v", data, err)
+	}
+	if f.Text != "# Notes\nhello" {
+		t.Errorf("Text = %q", f.Text)
+	}
+	if f.TextPath != "" {
+		t.Errorf("text files should not get a sidecar, got %q", f.TextPath)
+	}
+
+	desc := f.Describe()
+	for _, want := range []string{"notes.md", f.RelPath, "Preview:", "file_read"} {
+		if !strings.Contains(desc, want) {
+			t.Errorf("description missing %q:\n%s", want, desc)
+		}
+	}
+}
+
+func TestSave_TruncatesPreview(t *testing.T) {
+	in := New(t.TempDir(), 0, nil)
+	f, err := in.Save("u", "big.txt", "text/plain", []byte(strings.Repeat("a", PreviewLength+10)))
+	if err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if !f.Truncated || len(f.Text) != PreviewLength {
+		t.Errorf("expected preview truncated to %d, got %d (truncated=%v)", PreviewLength, len(f.Text), f.Truncated)
+	}
+}
+
+func TestSave_Zip(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range []string{"src/main.go", "README.md"} {
+		w, _ := zw.Create(name)
+		_, _ = w.Write([]byte("content"))
+	}
+	_ = zw.Close()
+
+	in := New(t.TempDir(), 0, nil)
+	f, err := in.Save("u", "project.zip", "application/zip", buf.Bytes())
+	if err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if !strings.Contains(f.Text, "2 entries") || !strings.Contains(f.Text, "src/main.go") {
+		t.Errorf("unexpected zip listing: %q", f.Text)
+	}
+}
+
+func TestSave_Rejected(t *testing.T) {
+	in := New(t.TempDir(), 0, nil)
+	if _, err := in.Save("u", "virus.exe", "application/x-msdownload", []byte("MZ")); err == nil {
+		t.Error("expected disallowed type to be rejected")
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{512, "512 B"},
+		{1536, "1.5 KB"},
+		{5 * 1024 * 1024, "5.0 MB"},
+	}
+	for _, tt := range tests {
+		if got := FormatSize(tt.n); got != tt.want {
+			t.Errorf("FormatSize(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
@@ -17,6 +17,8 @@ type Collector struct {
 	activeSessions atomic.Int64
 	toolCalls      map[string]*atomic.Int64 // by tool name
 	toolErrors     map[string]*atomic.Int64 // by tool name
+	sendRetries    map[string]*atomic.Int64 // by channel
+	sendFailures   map[string]*atomic.Int64 // by channel
 	mu             sync.RWMutex
 }
 
@@ -26,6 +28,8 @@ func NewCollector() *Collector {
 		messagesTotal: make(map[string]*atomic.Int64),
 		toolCalls:     make(map[string]*atomic.Int64),
 		toolErrors:    make(map[string]*atomic.Int64),
+		sendRetries:   make(map[string]*atomic.Int64),
+		sendFailures:  make(map[string]*atomic.Int64),
 	}
 }
 
@@ -76,6 +80,32 @@ func (c *Collector) IncrementToolError(toolName string) {
 	counter.Add(1)
 }
 
+// IncrementSendRetry increments the counter of outbound messages retried
+// after a rate limit
+func (c *Collector) IncrementSendRetry(channel string) {
+	c.mu.Lock()
+	counter, ok := c.sendRetries[channel]
+	if !ok {
+		counter = &atomic.Int64{}
+		c.sendRetries[channel] = counter
+	}
+	c.mu.Unlock()
+	counter.Add(1)
+}
+
+// IncrementSendFailure increments the counter of outbound messages that
+// could not be delivered
+func (c *Collector) IncrementSendFailure(channel string) {
+	c.mu.Lock()
+	counter, ok := c.sendFailures[channel]
+	if !ok {
+		counter = &atomic.Int64{}
+		c.sendFailures[channel] = counter
+	}
+	c.mu.Unlock()
+	counter.Add(1)
+}
+
 // GetMessagesTotal returns messages total by channel
 func (c *Collector) GetMessagesTotal() map[string]int64 {
 	c.mu.RLock()
@@ -119,6 +149,28 @@ func (c *Collector) GetToolErrors() map[string]int64 {
 	return result
 }
 
+// GetSendRetries returns rate-limit retry counts by channel
+func (c *Collector) GetSendRetries() map[string]int64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	result := make(map[string]int64)
+	for ch, counter := range c.sendRetries {
+		result[ch] = counter.Load()
+	}
+	return result
+}
+
+// GetSendFailures returns send failure counts by channel
+func (c *Collector) GetSendFailures() map[string]int64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	result := make(map[string]int64)
+	for ch, counter := range c.sendFailures {
+		result[ch] = counter.Load()
+	}
+	return result
+}
+
 // WritePrometheus writes metrics in Prometheus text format
 func (c *Collector) WritePrometheus(w io.Writer) {
 	// Messages total
@@ -167,6 +219,26 @@ func (c *Collector) WritePrometheus(w io.Writer) {
 	for _, name := range errorNames {
 		fmt.Fprintf(w, "feelpulse_tool_errors_total{tool=%q} %d\n", name, toolErrors[name])
 	}
+
+	fmt.Fprintln(w)
+
+	// Send retries
+	fmt.Fprintln(w, "# HELP feelpulse_send_retries_total Outbound messages retried after a rate limit")
+	fmt.Fprintln(w, "# TYPE feelpulse_send_retries_total counter")
+	retries := c.GetSendRetries()
+	for _, ch := range sortedKeys(retries) {
+		fmt.Fprintf(w, "feelpulse_send_retries_total{channel=%q} %d\n", ch, retries[ch])
+	}
+
+	fmt.Fprintln(w)
+
+	// Send failures
+	fmt.Fprintln(w, "# HELP feelpulse_send_failures_total Outbound messages that failed to send")
+	fmt.Fprintln(w, "# TYPE feelpulse_send_failures_total counter")
+	failures := c.GetSendFailures()
+	for _, ch := range sortedKeys(failures) {
+		fmt.Fprintf(w, "feelpulse_send_failures_total{channel=%q} %d\n", ch, failures[ch])
+	}
 }
 
 // sortedKeys returns sorted keys of a map
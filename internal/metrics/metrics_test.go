@@ -75,6 +75,27 @@ func TestCollectorToolCalls(t *testing.T) {
 	}
 }
 
+func TestCollectorSendFailures(t *testing.T) {
+	c := NewCollector()
+
+	c.IncrementSendRetry("telegram")
+	c.IncrementSendFailure("telegram")
+	c.IncrementSendFailure("telegram")
+
+	if got := c.GetSendRetries()["telegram"]; got != 1 {
+		t.Errorf("Expected telegram retries=1, got %d", got)
+	}
+	if got := c.GetSendFailures()["telegram"]; got != 2 {
+		t.Errorf("Expected telegram failures=2, got %d", got)
+	}
+
+	var buf bytes.Buffer
+	c.WritePrometheus(&buf)
+	if !strings.Contains(buf.String(), `feelpulse_send_failures_total{channel="telegram"} 2`) {
+		t.Errorf("Missing send failures in output:\n%s", buf.String())
+	}
+}
+
 func TestPrometheusFormat(t *testing.T) {
 	c := NewCollector()
 
@@ -36,12 +36,24 @@ type Session struct {
 	CreatedAt  time.Time
 	UpdatedAt  time.Time
 	MaxHistory int
-	Model      string // Per-session model override
-	TTSEnabled *bool  // Per-session TTS toggle (nil = use global config)
-	Profile    string // Per-session personality profile name
+	Model      string    // Per-session model override
+	TTSEnabled *bool     // Per-session TTS toggle (nil = use global config)
+	Profile    string    // Per-session personality profile name
+	Location   *Location // Last location the user shared (nil = unknown)
 	mu         sync.Mutex
 }
 
+// Location is a place shared by the user
+type Location struct {
+	Latitude  float64
+	Longitude float64
+	Accuracy  float64 // Radius of uncertainty in meters (0 = unknown)
+	Title     string  // Venue name, if a venue was shared
+	Address   string  // Venue address
+	Live      bool    // Kept up to date by a live location
+	UpdatedAt time.Time
+}
+
 // Store manages conversation sessions in memory
 type Store struct {
 	sessions  map[string]*Session
@@ -281,6 +293,27 @@ func (sess *Session) ReplaceHistory(messages []types.Message) {
 	sess.UpdatedAt = time.Now()
 }
 
+// RemoveLastTurn drops the latest user message and everything after it
+// (the replies to it) when match accepts that message. It is used to replace
+// a turn whose message was edited; older messages are left untouched.
+func (sess *Session) RemoveLastTurn(match func(types.Message) bool) bool {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+
+	for i := len(sess.Messages) - 1; i >= 0; i-- {
+		if sess.Messages[i].IsBot {
+			continue
+		}
+		if !match(sess.Messages[i]) {
+			return false
+		}
+		sess.Messages = sess.Messages[:i]
+		sess.UpdatedAt = time.Now()
+		return true
+	}
+	return false
+}
+
 // Clear removes all messages from the session and resets model
 func (sess *Session) Clear() {
 	sess.mu.Lock()
@@ -370,6 +403,27 @@ func (sess *Session) GetProfile() string {
 	return sess.Profile
 }
 
+// SetLocation stores the user's last known location
+func (sess *Session) SetLocation(loc Location) {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+
+	sess.Location = &loc
+	sess.UpdatedAt = time.Now()
+}
+
+// GetLocation returns a copy of the user's last known location (nil = unknown)
+func (sess *Session) GetLocation() *Location {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+
+	if sess.Location == nil {
+		return nil
+	}
+	loc := *sess.Location
+	return &loc
+}
+
 // Len returns the number of messages in the session
 func (sess *Session) Len() int {
 	sess.mu.Lock()
@@ -496,6 +550,7 @@ func (s *Store) Fork(channel, userID string, newSessionID string) (*Session, err
 	model := oldSess.Model
 	profile := oldSess.Profile
 	ttsEnabled := oldSess.TTSEnabled
+	location := oldSess.Location
 	oldSess.mu.Unlock()
 
 	newSess := &Session{
@@ -507,6 +562,7 @@ func (s *Store) Fork(channel, userID string, newSessionID string) (*Session, err
 		Model:      model,
 		Profile:    profile,
 		TTSEnabled: ttsEnabled,
+		Location:   location,
 	}
 
 	s.mu.Lock()
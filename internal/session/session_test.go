@@ -151,6 +151,68 @@ func TestSessionClear(t *testing.T) {
 	}
 }
 
+func TestSessionLocation(t *testing.T) {
+	store := NewStore()
+	sess := store.GetOrCreate("telegram", "user123")
+
+	if sess.GetLocation() != nil {
+		t.Fatal("new session should have no location")
+	}
+
+	sess.SetLocation(Location{Latitude: 52.52, Longitude: 13.405, Title: "Alexanderplatz"})
+	loc := sess.GetLocation()
+	if loc == nil || loc.Latitude != 52.52 || loc.Title != "Alexanderplatz" {
+		t.Fatalf("unexpected location: %+v", loc)
+	}
+
+	// The returned location is a copy
+	loc.Latitude = 0
+	if sess.GetLocation().Latitude != 52.52 {
+		t.Error("modifying the returned location should not change the session")
+	}
+
+	forked, err := store.Fork("telegram", "user123", "trip")
+	if err != nil {
+		t.Fatalf("Fork error: %v", err)
+	}
+	if forked.GetLocation() == nil {
+		t.Error("forked session should keep the location")
+	}
+}
+
+func TestSessionRemoveLastTurn(t *testing.T) {
+	sess := &Session{Key: "telegram:user123"}
+	sess.AddMessage(types.Message{ID: "1", Text: "first"})
+	sess.AddMessage(types.Message{Text: "reply 1", IsBot: true})
+	sess.AddMessage(types.Message{ID: "2", Text: "second"})
+	sess.AddMessage(types.Message{Text: "reply 2", IsBot: true})
+
+	byID := func(id string) func(types.Message) bool {
+		return func(m types.Message) bool { return m.ID == id }
+	}
+
+	// Only the latest user message can be replaced
+	if sess.RemoveLastTurn(byID("1")) {
+		t.Error("editing an older message should not remove anything")
+	}
+	if sess.Len() != 4 {
+		t.Fatalf("Expected 4 messages, got %d", sess.Len())
+	}
+
+	if !sess.RemoveLastTurn(byID("2")) {
+		t.Fatal("expected the latest turn to be removed")
+	}
+	messages := sess.GetAllMessages()
+	if len(messages) != 2 || messages[1].Text != "reply 1" {
+		t.Errorf("unexpected history after removal: %+v", messages)
+	}
+
+	empty := &Session{}
+	if empty.RemoveLastTurn(byID("1")) {
+		t.Error("empty session has no turn to remove")
+	}
+}
+
 func TestStoreDelete(t *testing.T) {
 	store := NewStore()
 
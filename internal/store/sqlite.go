@@ -531,3 +531,790 @@ func (s *SQLiteStore) LoadPin(id string) (*PinData, error) {
 	p.CreatedAt = time.Unix(createdAtUnix, 0)
 	return &p, nil
 }
+
+// === Channel Update Tracking ===
+
+// EnsureUpdatesTables creates the tables used to resume polling and to skip
+// updates that were already processed
+func (s *SQLiteStore) EnsureUpdatesTables() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS channel_offsets (
+			channel TEXT PRIMARY KEY,
+			update_offset INTEGER NOT NULL,
+			updated_at INTEGER NOT NULL
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS processed_updates (
+			channel TEXT NOT NULL,
+			chat_id INTEGER NOT NULL,
+			message_id INTEGER NOT NULL,
+			processed_at INTEGER NOT NULL,
+			PRIMARY KEY (channel, chat_id, message_id)
+		)
+	`)
+	if err != nil {
+		return err
+	}
+	// Index for TTL cleanup
+	_, _ = s.db.Exec(`CREATE INDEX IF NOT EXISTS idx_processed_updates_at ON processed_updates(processed_at)`)
+	return nil
+}
+
+// SaveUpdateOffset stores the next update offset to request for a channel
+func (s *SQLiteStore) SaveUpdateOffset(channel string, offset int64) error {
+	_, err := s.db.Exec(`
+		INSERT OR REPLACE INTO channel_offsets (channel, update_offset, updated_at)
+		VALUES (?, ?, ?)
+	`, channel, offset, time.Now().Unix())
+	return err
+}
+
+// LoadUpdateOffset returns the stored update offset for a channel (0 if none)
+func (s *SQLiteStore) LoadUpdateOffset(channel string) (int64, error) {
+	var offset int64
+	err := s.db.QueryRow(`SELECT update_offset FROM channel_offsets WHERE channel = ?`, channel).Scan(&offset)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return offset, err
+}
+
+// MarkUpdateProcessed records a message as processed. Returns false if it
+// was already recorded, by this or another process.
+func (s *SQLiteStore) MarkUpdateProcessed(channel string, chatID, messageID int64) (bool, error) {
+	result, err := s.db.Exec(`
+		INSERT OR IGNORE INTO processed_updates (channel, chat_id, message_id, processed_at)
+		VALUES (?, ?, ?, ?)
+	`, channel, chatID, messageID, time.Now().Unix())
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// CleanProcessedUpdates removes processed-update records older than maxAge
+func (s *SQLiteStore) CleanProcessedUpdates(maxAge time.Duration) (int64, error) {
+	cutoff := time.Now().Add(-maxAge).Unix()
+	result, err := s.db.Exec(`DELETE FROM processed_updates WHERE processed_at < ?`, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// === User Access ===
+
+// UserData represents a channel user who requested or was granted access
+type UserData struct {
+	Channel   string    `json:"channel"`
+	UserID    int64     `json:"user_id"`
+	Username  string    `json:"username"`
+	Name      string    `json:"name"`
+	Status    string    `json:"status"` // pending, approved, denied, revoked
+	Role      string    `json:"role"`   // user, admin
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// InviteData represents a single-use invite code
+type InviteData struct {
+	Code      string    `json:"code"`
+	Role      string    `json:"role"`
+	CreatedBy int64     `json:"created_by"`
+	UsedBy    int64     `json:"used_by"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// EnsureUsersTables creates the users and invites tables if they don't exist
+func (s *SQLiteStore) EnsureUsersTables() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS users (
+			channel TEXT NOT NULL,
+			user_id INTEGER NOT NULL,
+			username TEXT NOT NULL DEFAULT '',
+			name TEXT NOT NULL DEFAULT '',
+			status TEXT NOT NULL,
+			role TEXT NOT NULL DEFAULT 'user',
+			created_at INTEGER NOT NULL,
+			updated_at INTEGER NOT NULL,
+			PRIMARY KEY (channel, user_id)
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS invites (
+			code TEXT PRIMARY KEY,
+			role TEXT NOT NULL DEFAULT 'user',
+			created_by INTEGER NOT NULL,
+			used_by INTEGER NOT NULL DEFAULT 0,
+			expires_at INTEGER NOT NULL,
+			created_at INTEGER NOT NULL
+		)
+	`)
+	return err
+}
+
+// SaveUser inserts or updates a user record
+func (s *SQLiteStore) SaveUser(u *UserData) error {
+	_, err := s.db.Exec(`
+		INSERT OR REPLACE INTO users (channel, user_id, username, name, status, role, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, u.Channel, u.UserID, u.Username, u.Name, u.Status, u.Role, u.CreatedAt.Unix(), u.UpdatedAt.Unix())
+	return err
+}
+
+// LoadUser retrieves a user record (nil if the user is unknown)
+func (s *SQLiteStore) LoadUser(channel string, userID int64) (*UserData, error) {
+	u := UserData{Channel: channel, UserID: userID}
+	var createdAtUnix, updatedAtUnix int64
+
+	err := s.db.QueryRow(`
+		SELECT username, name, status, role, created_at, updated_at
+		FROM users WHERE channel = ? AND user_id = ?
+	`, channel, userID).Scan(&u.Username, &u.Name, &u.Status, &u.Role, &createdAtUnix, &updatedAtUnix)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	u.CreatedAt = time.Unix(createdAtUnix, 0)
+	u.UpdatedAt = time.Unix(updatedAtUnix, 0)
+	return &u, nil
+}
+
+// ListUsers retrieves all user records for a channel, most recently updated first
+func (s *SQLiteStore) ListUsers(channel string) ([]*UserData, error) {
+	rows, err := s.db.Query(`
+		SELECT user_id, username, name, status, role, created_at, updated_at
+		FROM users
+		WHERE channel = ?
+		ORDER BY updated_at DESC
+	`, channel)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*UserData
+	for rows.Next() {
+		u := UserData{Channel: channel}
+		var createdAtUnix, updatedAtUnix int64
+		if err := rows.Scan(&u.UserID, &u.Username, &u.Name, &u.Status, &u.Role, &createdAtUnix, &updatedAtUnix); err != nil {
+			return nil, err
+		}
+		u.CreatedAt = time.Unix(createdAtUnix, 0)
+		u.UpdatedAt = time.Unix(updatedAtUnix, 0)
+		users = append(users, &u)
+	}
+	return users, rows.Err()
+}
+
+// SaveInvite persists a new invite code
+func (s *SQLiteStore) SaveInvite(inv *InviteData) error {
+	_, err := s.db.Exec(`
+		INSERT INTO invites (code, role, created_by, used_by, expires_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, inv.Code, inv.Role, inv.CreatedBy, inv.UsedBy, inv.ExpiresAt.Unix(), inv.CreatedAt.Unix())
+	return err
+}
+
+// RedeemInvite marks an unused, unexpired invite as used by userID and
+// returns it. Returns nil if the code is unknown, used or expired.
+func (s *SQLiteStore) RedeemInvite(code string, userID int64) (*InviteData, error) {
+	result, err := s.db.Exec(`
+		UPDATE invites SET used_by = ?
+		WHERE code = ? AND used_by = 0 AND expires_at > ?
+	`, userID, code, time.Now().Unix())
+	if err != nil {
+		return nil, err
+	}
+	if n, err := result.RowsAffected(); err != nil || n != 1 {
+		return nil, err
+	}
+
+	inv := InviteData{Code: code}
+	var expiresAtUnix, createdAtUnix int64
+	err = s.db.QueryRow(`
+		SELECT role, created_by, used_by, expires_at, created_at FROM invites WHERE code = ?
+	`, code).Scan(&inv.Role, &inv.CreatedBy, &inv.UsedBy, &expiresAtUnix, &createdAtUnix)
+	if err != nil {
+		return nil, err
+	}
+	inv.ExpiresAt = time.Unix(expiresAtUnix, 0)
+	inv.CreatedAt = time.Unix(createdAtUnix, 0)
+	return &inv, nil
+}
+
+// === Feedback ===
+
+// SentMessageData links a message sent to a chat to the stored reply
+type SentMessageData struct {
+	Channel    string    `json:"channel"`
+	ChatID     int64     `json:"chat_id"`
+	MessageID  int64     `json:"message_id"`
+	ReplyID    string    `json:"reply_id"`
+	SessionKey string    `json:"session_key"`
+	Model      string    `json:"model"`
+	Profile    string    `json:"profile"`
+	SentAt     time.Time `json:"sent_at"`
+}
+
+// FeedbackData is a user's reaction to a reply
+type FeedbackData struct {
+	SentMessageData
+	UserID    int64     `json:"user_id"`
+	Reaction  string    `json:"reaction"`
+	Score     int       `json:"score"` // 1 positive, -1 negative, 0 neutral
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// FeedbackStat aggregates feedback for one model and profile
+type FeedbackStat struct {
+	Model    string `json:"model"`
+	Profile  string `json:"profile"`
+	Positive int    `json:"positive"`
+	Negative int    `json:"negative"`
+	Total    int    `json:"total"`
+}
+
+// EnsureFeedbackTables creates the sent_messages and feedback tables
+func (s *SQLiteStore) EnsureFeedbackTables() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS sent_messages (
+			channel TEXT NOT NULL,
+			chat_id INTEGER NOT NULL,
+			message_id INTEGER NOT NULL,
+			reply_id TEXT NOT NULL,
+			session_key TEXT NOT NULL,
+			model TEXT NOT NULL DEFAULT '',
+			profile TEXT NOT NULL DEFAULT '',
+			sent_at INTEGER NOT NULL,
+			PRIMARY KEY (channel, chat_id, message_id)
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS feedback (
+			channel TEXT NOT NULL,
+			chat_id INTEGER NOT NULL,
+			message_id INTEGER NOT NULL,
+			user_id INTEGER NOT NULL,
+			reaction TEXT NOT NULL,
+			score INTEGER NOT NULL,
+			reply_id TEXT NOT NULL,
+			session_key TEXT NOT NULL,
+			model TEXT NOT NULL DEFAULT '',
+			profile TEXT NOT NULL DEFAULT '',
+			created_at INTEGER NOT NULL,
+			PRIMARY KEY (channel, chat_id, message_id, user_id)
+		)
+	`)
+	return err
+}
+
+// SaveSentMessage records a message sent as (part of) a reply
+func (s *SQLiteStore) SaveSentMessage(m *SentMessageData) error {
+	_, err := s.db.Exec(`
+		INSERT OR REPLACE INTO sent_messages (channel, chat_id, message_id, reply_id, session_key, model, profile, sent_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, m.Channel, m.ChatID, m.MessageID, m.ReplyID, m.SessionKey, m.Model, m.Profile, m.SentAt.Unix())
+	return err
+}
+
+// LoadSentMessage retrieves a sent message record (nil if unknown)
+func (s *SQLiteStore) LoadSentMessage(channel string, chatID, messageID int64) (*SentMessageData, error) {
+	m := SentMessageData{Channel: channel, ChatID: chatID, MessageID: messageID}
+	var sentAtUnix int64
+
+	err := s.db.QueryRow(`
+		SELECT reply_id, session_key, model, profile, sent_at
+		FROM sent_messages WHERE channel = ? AND chat_id = ? AND message_id = ?
+	`, channel, chatID, messageID).Scan(&m.ReplyID, &m.SessionKey, &m.Model, &m.Profile, &sentAtUnix)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	m.SentAt = time.Unix(sentAtUnix, 0)
+	return &m, nil
+}
+
+// SaveFeedback stores a reaction, replacing the user's previous one
+func (s *SQLiteStore) SaveFeedback(f *FeedbackData) error {
+	_, err := s.db.Exec(`
+		INSERT OR REPLACE INTO feedback (channel, chat_id, message_id, user_id, reaction, score, reply_id, session_key, model, profile, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, f.Channel, f.ChatID, f.MessageID, f.UserID, f.Reaction, f.Score, f.ReplyID, f.SessionKey, f.Model, f.Profile, f.CreatedAt.Unix())
+	return err
+}
+
+// DeleteFeedback removes a user's reaction to a message
+func (s *SQLiteStore) DeleteFeedback(channel string, chatID, messageID, userID int64) error {
+	_, err := s.db.Exec(`
+		DELETE FROM feedback WHERE channel = ? AND chat_id = ? AND message_id = ? AND user_id = ?
+	`, channel, chatID, messageID, userID)
+	return err
+}
+
+// FeedbackStats aggregates feedback by model and profile
+func (s *SQLiteStore) FeedbackStats() ([]*FeedbackStat, error) {
+	rows, err := s.db.Query(`
+		SELECT model, profile,
+			SUM(CASE WHEN score > 0 THEN 1 ELSE 0 END),
+			SUM(CASE WHEN score < 0 THEN 1 ELSE 0 END),
+			COUNT(*)
+		FROM feedback
+		GROUP BY model, profile
+		ORDER BY COUNT(*) DESC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []*FeedbackStat
+	for rows.Next() {
+		var st FeedbackStat
+		if err := rows.Scan(&st.Model, &st.Profile, &st.Positive, &st.Negative, &st.Total); err != nil {
+			return nil, err
+		}
+		stats = append(stats, &st)
+	}
+	return stats, rows.Err()
+}
+
+// === Webhook Deliveries ===
+
+// HookDeliveryData records one webhook request and what became of it
+type HookDeliveryData struct {
+	ID         string    `json:"id"`
+	Mapping    string    `json:"mapping"`
+	Path       string    `json:"path"`
+	Event      string    `json:"event"`
+	Status     string    `json:"status"`
+	Error      string    `json:"error"`
+	AgentID    string    `json:"agent_id"`
+	Target     string    `json:"target"`
+	ReceivedAt time.Time `json:"received_at"`
+	FinishedAt time.Time `json:"finished_at"` // Zero while in progress
+}
+
+// EnsureHookDeliveriesTable creates the hook_deliveries table if it doesn't exist
+func (s *SQLiteStore) EnsureHookDeliveriesTable() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS hook_deliveries (
+			id TEXT PRIMARY KEY,
+			mapping TEXT NOT NULL,
+			path TEXT NOT NULL,
+			event TEXT NOT NULL DEFAULT '',
+			status TEXT NOT NULL,
+			error TEXT NOT NULL DEFAULT '',
+			agent_id TEXT NOT NULL DEFAULT '',
+			target TEXT NOT NULL DEFAULT '',
+			received_at INTEGER NOT NULL,
+			finished_at INTEGER NOT NULL DEFAULT 0
+		)
+	`)
+	if err != nil {
+		return err
+	}
+	_, _ = s.db.Exec(`CREATE INDEX IF NOT EXISTS idx_hook_deliveries_received ON hook_deliveries(received_at)`)
+	return nil
+}
+
+// SaveHookDelivery inserts or updates a webhook delivery
+func (s *SQLiteStore) SaveHookDelivery(d *HookDeliveryData) error {
+	var finishedAt int64
+	if !d.FinishedAt.IsZero() {
+		finishedAt = d.FinishedAt.Unix()
+	}
+	_, err := s.db.Exec(`
+		INSERT OR REPLACE INTO hook_deliveries (id, mapping, path, event, status, error, agent_id, target, received_at, finished_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, d.ID, d.Mapping, d.Path, d.Event, d.Status, d.Error, d.AgentID, d.Target, d.ReceivedAt.Unix(), finishedAt)
+	return err
+}
+
+// LoadHookDeliveries returns the most recent webhook deliveries, newest first
+func (s *SQLiteStore) LoadHookDeliveries(limit int) ([]*HookDeliveryData, error) {
+	rows, err := s.db.Query(`
+		SELECT id, mapping, path, event, status, error, agent_id, target, received_at, finished_at
+		FROM hook_deliveries
+		ORDER BY received_at DESC, rowid DESC
+		LIMIT ?
+	`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*HookDeliveryData
+	for rows.Next() {
+		var d HookDeliveryData
+		var receivedAt, finishedAt int64
+		if err := rows.Scan(&d.ID, &d.Mapping, &d.Path, &d.Event, &d.Status, &d.Error, &d.AgentID, &d.Target, &receivedAt, &finishedAt); err != nil {
+			return nil, err
+		}
+		d.ReceivedAt = time.Unix(receivedAt, 0)
+		if finishedAt > 0 {
+			d.FinishedAt = time.Unix(finishedAt, 0)
+		}
+		deliveries = append(deliveries, &d)
+	}
+	return deliveries, rows.Err()
+}
+
+// CleanOldHookDeliveries removes deliveries received before maxAge ago
+func (s *SQLiteStore) CleanOldHookDeliveries(maxAge time.Duration) (int64, error) {
+	cutoff := time.Now().Add(-maxAge).Unix()
+	result, err := s.db.Exec(`DELETE FROM hook_deliveries WHERE received_at < ?`, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// === Webhook Outbox ===
+
+// WebhookDeliveryData is an outbound event queued for one subscription
+type WebhookDeliveryData struct {
+	ID           string    `json:"id"`
+	Subscription string    `json:"subscription"`
+	URL          string    `json:"url"`
+	EventID      string    `json:"event_id"`
+	EventType    string    `json:"event_type"`
+	Payload      []byte    `json:"payload"`
+	Status       string    `json:"status"` // pending, delivered, failed
+	Attempts     int       `json:"attempts"`
+	LastError    string    `json:"last_error"`
+	NextAttempt  time.Time `json:"next_attempt"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// EnsureWebhookOutboxTable creates the webhook_outbox table if it doesn't exist
+func (s *SQLiteStore) EnsureWebhookOutboxTable() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS webhook_outbox (
+			id TEXT PRIMARY KEY,
+			subscription TEXT NOT NULL,
+			url TEXT NOT NULL,
+			event_id TEXT NOT NULL,
+			event_type TEXT NOT NULL,
+			payload BLOB NOT NULL,
+			status TEXT NOT NULL,
+			attempts INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT NOT NULL DEFAULT '',
+			next_attempt INTEGER NOT NULL,
+			created_at INTEGER NOT NULL,
+			updated_at INTEGER NOT NULL
+		)
+	`)
+	if err != nil {
+		return err
+	}
+	_, _ = s.db.Exec(`CREATE INDEX IF NOT EXISTS idx_webhook_outbox_due ON webhook_outbox(status, next_attempt)`)
+	return nil
+}
+
+// SaveWebhookDelivery inserts or updates an outbox entry
+func (s *SQLiteStore) SaveWebhookDelivery(d *WebhookDeliveryData) error {
+	_, err := s.db.Exec(`
+		INSERT OR REPLACE INTO webhook_outbox (id, subscription, url, event_id, event_type, payload, status, attempts, last_error, next_attempt, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, d.ID, d.Subscription, d.URL, d.EventID, d.EventType, d.Payload, d.Status, d.Attempts, d.LastError,
+		d.NextAttempt.Unix(), d.CreatedAt.Unix(), d.UpdatedAt.Unix())
+	return err
+}
+
+// LoadWebhookDelivery retrieves an outbox entry by ID
+func (s *SQLiteStore) LoadWebhookDelivery(id string) (*WebhookDeliveryData, error) {
+	deliveries, err := s.queryWebhookDeliveries(`WHERE id = ?`, id)
+	if err != nil || len(deliveries) == 0 {
+		return nil, err
+	}
+	return deliveries[0], nil
+}
+
+// LoadDueWebhookDeliveries returns pending entries whose next attempt is due, oldest first
+func (s *SQLiteStore) LoadDueWebhookDeliveries(now time.Time, limit int) ([]*WebhookDeliveryData, error) {
+	return s.queryWebhookDeliveries(`WHERE status = 'pending' AND next_attempt <= ? ORDER BY next_attempt ASC LIMIT ?`, now.Unix(), limit)
+}
+
+// ListWebhookDeliveries returns recent outbox entries, newest first (empty status = all)
+func (s *SQLiteStore) ListWebhookDeliveries(status string, limit int) ([]*WebhookDeliveryData, error) {
+	if status == "" {
+		return s.queryWebhookDeliveries(`ORDER BY created_at DESC LIMIT ?`, limit)
+	}
+	return s.queryWebhookDeliveries(`WHERE status = ? ORDER BY created_at DESC LIMIT ?`, status, limit)
+}
+
+// CleanOldWebhookDeliveries removes delivered and failed entries not updated for maxAge
+func (s *SQLiteStore) CleanOldWebhookDeliveries(maxAge time.Duration) (int64, error) {
+	cutoff := time.Now().Add(-maxAge).Unix()
+	result, err := s.db.Exec(`DELETE FROM webhook_outbox WHERE status != 'pending' AND updated_at < ?`, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (s *SQLiteStore) queryWebhookDeliveries(where string, args ...any) ([]*WebhookDeliveryData, error) {
+	rows, err := s.db.Query(`
+		SELECT id, subscription, url, event_id, event_type, payload, status, attempts, last_error, next_attempt, created_at, updated_at
+		FROM webhook_outbox `+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*WebhookDeliveryData
+	for rows.Next() {
+		var d WebhookDeliveryData
+		var nextAttempt, createdAt, updatedAt int64
+		if err := rows.Scan(&d.ID, &d.Subscription, &d.URL, &d.EventID, &d.EventType, &d.Payload, &d.Status,
+			&d.Attempts, &d.LastError, &nextAttempt, &createdAt, &updatedAt); err != nil {
+			return nil, err
+		}
+		d.NextAttempt = time.Unix(nextAttempt, 0)
+		d.CreatedAt = time.Unix(createdAt, 0)
+		d.UpdatedAt = time.Unix(updatedAt, 0)
+		deliveries = append(deliveries, &d)
+	}
+	return deliveries, rows.Err()
+}
+
+// === API Keys ===
+
+// APIKeyData is an API key; only the SHA-256 hash of the secret is stored
+type APIKeyData struct {
+	ID           string    `json:"id"`
+	Name         string    `json:"name"`
+	Prefix       string    `json:"prefix"` // Start of the key, for display
+	Hash         string    `json:"hash"`
+	Scopes       string    `json:"scopes"` // Comma-separated
+	Models       string    `json:"models"` // Comma-separated, empty = all
+	RateLimit    int       `json:"rate_limit"`
+	ServerTools  bool      `json:"server_tools"`
+	ExpiresAt    time.Time `json:"expires_at"` // Zero = never
+	CreatedAt    time.Time `json:"created_at"`
+	LastUsedAt   time.Time `json:"last_used_at"`
+	RevokedAt    time.Time `json:"revoked_at"`
+	Requests     int64     `json:"requests"`
+	InputTokens  int64     `json:"input_tokens"`
+	OutputTokens int64     `json:"output_tokens"`
+}
+
+// EnsureAPIKeysTable creates the api_keys table if it doesn't exist
+func (s *SQLiteStore) EnsureAPIKeysTable() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS api_keys (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			prefix TEXT NOT NULL,
+			hash TEXT NOT NULL UNIQUE,
+			scopes TEXT NOT NULL,
+			models TEXT NOT NULL DEFAULT '',
+			rate_limit INTEGER NOT NULL DEFAULT 0,
+			server_tools INTEGER NOT NULL DEFAULT 0,
+			expires_at INTEGER NOT NULL DEFAULT 0,
+			created_at INTEGER NOT NULL,
+			last_used_at INTEGER NOT NULL DEFAULT 0,
+			revoked_at INTEGER NOT NULL DEFAULT 0,
+			requests INTEGER NOT NULL DEFAULT 0,
+			input_tokens INTEGER NOT NULL DEFAULT 0,
+			output_tokens INTEGER NOT NULL DEFAULT 0
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	// Add server_tools column if not exists (migration); existing keys stay without tools
+	_, _ = s.db.Exec(`ALTER TABLE api_keys ADD COLUMN server_tools INTEGER NOT NULL DEFAULT 0`)
+	return nil
+}
+
+// SaveAPIKey inserts or updates an API key
+func (s *SQLiteStore) SaveAPIKey(k *APIKeyData) error {
+	_, err := s.db.Exec(`
+		INSERT OR REPLACE INTO api_keys (id, name, prefix, hash, scopes, models, rate_limit, server_tools, expires_at, created_at,
+			last_used_at, revoked_at, requests, input_tokens, output_tokens)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, k.ID, k.Name, k.Prefix, k.Hash, k.Scopes, k.Models, k.RateLimit, k.ServerTools, unixOrZero(k.ExpiresAt), k.CreatedAt.Unix(),
+		unixOrZero(k.LastUsedAt), unixOrZero(k.RevokedAt), k.Requests, k.InputTokens, k.OutputTokens)
+	return err
+}
+
+// LoadAPIKeys returns all API keys, newest first
+func (s *SQLiteStore) LoadAPIKeys() ([]*APIKeyData, error) {
+	return s.queryAPIKeys(`ORDER BY created_at DESC, rowid DESC`)
+}
+
+// LoadAPIKeyByHash returns the API key with a secret's hash, or nil
+func (s *SQLiteStore) LoadAPIKeyByHash(hash string) (*APIKeyData, error) {
+	keys, err := s.queryAPIKeys(`WHERE hash = ?`, hash)
+	if err != nil || len(keys) == 0 {
+		return nil, err
+	}
+	return keys[0], nil
+}
+
+// CountAPIKeys counts all keys, including revoked and expired ones
+func (s *SQLiteStore) CountAPIKeys() (int, error) {
+	var count int
+	err := s.db.QueryRow(`SELECT COUNT(*) FROM api_keys`).Scan(&count)
+	return count, err
+}
+
+// TouchAPIKey records a request made with an API key
+func (s *SQLiteStore) TouchAPIKey(id string, at time.Time) error {
+	_, err := s.db.Exec(`UPDATE api_keys SET last_used_at = ?, requests = requests + 1 WHERE id = ?`, at.Unix(), id)
+	return err
+}
+
+// AddAPIKeyUsage adds token usage to an API key
+func (s *SQLiteStore) AddAPIKeyUsage(id string, inputTokens, outputTokens int) error {
+	_, err := s.db.Exec(`
+		UPDATE api_keys SET input_tokens = input_tokens + ?, output_tokens = output_tokens + ? WHERE id = ?
+	`, inputTokens, outputTokens, id)
+	return err
+}
+
+func (s *SQLiteStore) queryAPIKeys(where string, args ...any) ([]*APIKeyData, error) {
+	rows, err := s.db.Query(`
+		SELECT id, name, prefix, hash, scopes, models, rate_limit, server_tools, expires_at, created_at,
+			last_used_at, revoked_at, requests, input_tokens, output_tokens
+		FROM api_keys `+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []*APIKeyData
+	for rows.Next() {
+		var k APIKeyData
+		var expiresAt, createdAt, lastUsedAt, revokedAt int64
+		if err := rows.Scan(&k.ID, &k.Name, &k.Prefix, &k.Hash, &k.Scopes, &k.Models, &k.RateLimit, &k.ServerTools, &expiresAt, &createdAt,
+			&lastUsedAt, &revokedAt, &k.Requests, &k.InputTokens, &k.OutputTokens); err != nil {
+			return nil, err
+		}
+		k.ExpiresAt = timeOrZero(expiresAt)
+		k.CreatedAt = time.Unix(createdAt, 0)
+		k.LastUsedAt = timeOrZero(lastUsedAt)
+		k.RevokedAt = timeOrZero(revokedAt)
+		keys = append(keys, &k)
+	}
+	return keys, rows.Err()
+}
+
+// unixOrZero stores a zero time as 0
+func unixOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+// timeOrZero loads 0 as a zero time
+func timeOrZero(unix int64) time.Time {
+	if unix == 0 {
+		return time.Time{}
+	}
+	return time.Unix(unix, 0)
+}
+
+// === Dashboard Sessions ===
+
+// DashboardSessionData is a dashboard login; only the SHA-256 hash of the
+// session cookie is stored
+type DashboardSessionData struct {
+	ID        string    `json:"id"` // Hash of the cookie value
+	Role      string    `json:"role"`
+	Label     string    `json:"label"` // How the session logged in
+	CSRFToken string    `json:"csrf_token"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// EnsureDashboardSessionsTable creates the dashboard_sessions table if it doesn't exist
+func (s *SQLiteStore) EnsureDashboardSessionsTable() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS dashboard_sessions (
+			id TEXT PRIMARY KEY,
+			role TEXT NOT NULL,
+			label TEXT NOT NULL DEFAULT '',
+			csrf_token TEXT NOT NULL,
+			created_at INTEGER NOT NULL,
+			expires_at INTEGER NOT NULL
+		)
+	`)
+	return err
+}
+
+// SaveDashboardSession inserts or updates a dashboard session
+func (s *SQLiteStore) SaveDashboardSession(d *DashboardSessionData) error {
+	_, err := s.db.Exec(`
+		INSERT OR REPLACE INTO dashboard_sessions (id, role, label, csrf_token, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, d.ID, d.Role, d.Label, d.CSRFToken, d.CreatedAt.Unix(), d.ExpiresAt.Unix())
+	return err
+}
+
+// LoadDashboardSessions returns the sessions that expire after now and
+// deletes the others
+func (s *SQLiteStore) LoadDashboardSessions(now time.Time) ([]*DashboardSessionData, error) {
+	if _, err := s.db.Exec(`DELETE FROM dashboard_sessions WHERE expires_at <= ?`, now.Unix()); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(`
+		SELECT id, role, label, csrf_token, created_at, expires_at
+		FROM dashboard_sessions ORDER BY created_at
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []*DashboardSessionData
+	for rows.Next() {
+		var d DashboardSessionData
+		var createdAt, expiresAt int64
+		if err := rows.Scan(&d.ID, &d.Role, &d.Label, &d.CSRFToken, &createdAt, &expiresAt); err != nil {
+			return nil, err
+		}
+		d.CreatedAt = time.Unix(createdAt, 0)
+		d.ExpiresAt = time.Unix(expiresAt, 0)
+		sessions = append(sessions, &d)
+	}
+	return sessions, rows.Err()
+}
+
+// DeleteDashboardSession deletes a dashboard session
+func (s *SQLiteStore) DeleteDashboardSession(id string) error {
+	_, err := s.db.Exec(`DELETE FROM dashboard_sessions WHERE id = ?`, id)
+	return err
+}
@@ -534,3 +534,387 @@ func TestSQLiteStore_ClearAll(t *testing.T) {
 		t.Fatalf("Expected 0 sessions after ClearAll, got %d", len(keys))
 	}
 }
+
+func TestSQLiteStore_UpdateOffset(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	store, err := NewSQLiteStore(dbPath)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	if err := store.EnsureUpdatesTables(); err != nil {
+		t.Fatalf("failed to create update tables: %v", err)
+	}
+
+	offset, err := store.LoadUpdateOffset("telegram")
+	if err != nil || offset != 0 {
+		t.Fatalf("expected offset 0 for new store, got %d (%v)", offset, err)
+	}
+
+	if err := store.SaveUpdateOffset("telegram", 1001); err != nil {
+		t.Fatalf("failed to save offset: %v", err)
+	}
+	if err := store.SaveUpdateOffset("telegram", 1005); err != nil {
+		t.Fatalf("failed to update offset: %v", err)
+	}
+	store.Close()
+
+	// Offset survives a restart
+	store, err = NewSQLiteStore(dbPath)
+	if err != nil {
+		t.Fatalf("failed to reopen store: %v", err)
+	}
+	defer store.Close()
+	if err := store.EnsureUpdatesTables(); err != nil {
+		t.Fatalf("EnsureUpdatesTables should be idempotent: %v", err)
+	}
+
+	offset, err = store.LoadUpdateOffset("telegram")
+	if err != nil || offset != 1005 {
+		t.Errorf("expected offset 1005 after restart, got %d (%v)", offset, err)
+	}
+}
+
+func TestSQLiteStore_MarkUpdateProcessed(t *testing.T) {
+	store, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.EnsureUpdatesTables(); err != nil {
+		t.Fatalf("failed to create update tables: %v", err)
+	}
+
+	first, err := store.MarkUpdateProcessed("telegram", 42, 7)
+	if err != nil || !first {
+		t.Fatalf("first delivery should be new, got %v (%v)", first, err)
+	}
+	again, err := store.MarkUpdateProcessed("telegram", 42, 7)
+	if err != nil || again {
+		t.Errorf("duplicate delivery should be rejected, got %v (%v)", again, err)
+	}
+	other, _ := store.MarkUpdateProcessed("telegram", 43, 7)
+	if !other {
+		t.Error("same message ID in another chat should be new")
+	}
+
+	// Records older than the TTL are cleaned up
+	if _, err := store.db.Exec(`UPDATE processed_updates SET processed_at = ? WHERE chat_id = 42`, time.Now().Add(-72*time.Hour).Unix()); err != nil {
+		t.Fatalf("failed to age record: %v", err)
+	}
+	n, err := store.CleanProcessedUpdates(48 * time.Hour)
+	if err != nil || n != 1 {
+		t.Errorf("expected 1 record cleaned, got %d (%v)", n, err)
+	}
+	if fresh, _ := store.MarkUpdateProcessed("telegram", 42, 7); !fresh {
+		t.Error("message should be accepted again once its record expired")
+	}
+}
+
+func TestSQLiteStore_Users(t *testing.T) {
+	store, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.EnsureUsersTables(); err != nil {
+		t.Fatalf("failed to create users tables: %v", err)
+	}
+
+	if u, err := store.LoadUser("telegram", 42); err != nil || u != nil {
+		t.Fatalf("expected no user, got %+v (%v)", u, err)
+	}
+
+	now := time.Now()
+	u := &UserData{Channel: "telegram", UserID: 42, Username: "alice", Status: "pending", Role: "user", CreatedAt: now, UpdatedAt: now}
+	if err := store.SaveUser(u); err != nil {
+		t.Fatalf("failed to save user: %v", err)
+	}
+	u.Status = "approved"
+	u.Username = "alice_new"
+	if err := store.SaveUser(u); err != nil {
+		t.Fatalf("failed to update user: %v", err)
+	}
+
+	loaded, err := store.LoadUser("telegram", 42)
+	if err != nil || loaded == nil {
+		t.Fatalf("failed to load user: %v", err)
+	}
+	if loaded.Status != "approved" || loaded.Username != "alice_new" || loaded.Role != "user" {
+		t.Errorf("unexpected user: %+v", loaded)
+	}
+
+	users, err := store.ListUsers("telegram")
+	if err != nil || len(users) != 1 {
+		t.Errorf("expected 1 user, got %d (%v)", len(users), err)
+	}
+	if other, _ := store.ListUsers("discord"); len(other) != 0 {
+		t.Errorf("users should be scoped by channel, got %d", len(other))
+	}
+}
+
+func TestSQLiteStore_RedeemInvite(t *testing.T) {
+	store, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.EnsureUsersTables(); err != nil {
+		t.Fatalf("failed to create users tables: %v", err)
+	}
+
+	now := time.Now()
+	_ = store.SaveInvite(&InviteData{Code: "good", Role: "user", CreatedBy: 1, ExpiresAt: now.Add(time.Hour), CreatedAt: now})
+	_ = store.SaveInvite(&InviteData{Code: "old", Role: "user", CreatedBy: 1, ExpiresAt: now.Add(-time.Hour), CreatedAt: now})
+
+	inv, err := store.RedeemInvite("good", 42)
+	if err != nil || inv == nil {
+		t.Fatalf("expected invite to be redeemed, got %v (%v)", inv, err)
+	}
+	if inv.UsedBy != 42 || inv.CreatedBy != 1 {
+		t.Errorf("unexpected invite: %+v", inv)
+	}
+
+	if inv, _ := store.RedeemInvite("good", 43); inv != nil {
+		t.Error("invite should be single use")
+	}
+	if inv, _ := store.RedeemInvite("old", 43); inv != nil {
+		t.Error("expired invite should be rejected")
+	}
+	if inv, _ := store.RedeemInvite("missing", 43); inv != nil {
+		t.Error("unknown invite should be rejected")
+	}
+}
+
+func TestSQLiteStore_Feedback(t *testing.T) {
+	store, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.EnsureFeedbackTables(); err != nil {
+		t.Fatalf("failed to create feedback tables: %v", err)
+	}
+
+	sent := SentMessageData{Channel: "telegram", ChatID: 42, MessageID: 100, ReplyID: "msg-1", SessionKey: "telegram:42", Model: "claude-sonnet-4", SentAt: time.Now()}
+	if err := store.SaveSentMessage(&sent); err != nil {
+		t.Fatalf("failed to save sent message: %v", err)
+	}
+
+	loaded, err := store.LoadSentMessage("telegram", 42, 100)
+	if err != nil || loaded == nil || loaded.ReplyID != "msg-1" || loaded.Model != "claude-sonnet-4" {
+		t.Fatalf("unexpected sent message: %+v (%v)", loaded, err)
+	}
+	if m, _ := store.LoadSentMessage("telegram", 42, 101); m != nil {
+		t.Error("unknown message should not be found")
+	}
+
+	now := time.Now()
+	_ = store.SaveFeedback(&FeedbackData{SentMessageData: *loaded, UserID: 1, Reaction: "👎", Score: -1, CreatedAt: now})
+	// A new reaction from the same user replaces the old one
+	_ = store.SaveFeedback(&FeedbackData{SentMessageData: *loaded, UserID: 1, Reaction: "👍", Score: 1, CreatedAt: now})
+	_ = store.SaveFeedback(&FeedbackData{SentMessageData: *loaded, UserID: 2, Reaction: "👎", Score: -1, CreatedAt: now})
+	_ = store.SaveFeedback(&FeedbackData{SentMessageData: *loaded, UserID: 3, Reaction: "🤔", Score: 0, CreatedAt: now})
+
+	stats, err := store.FeedbackStats()
+	if err != nil || len(stats) != 1 {
+		t.Fatalf("expected 1 stat row, got %d (%v)", len(stats), err)
+	}
+	if st := stats[0]; st.Positive != 1 || st.Negative != 1 || st.Total != 3 {
+		t.Errorf("unexpected stats: %+v", st)
+	}
+
+	if err := store.DeleteFeedback("telegram", 42, 100, 2); err != nil {
+		t.Fatalf("failed to delete feedback: %v", err)
+	}
+	stats, _ = store.FeedbackStats()
+	if stats[0].Negative != 0 || stats[0].Total != 2 {
+		t.Errorf("removed reaction should not be counted: %+v", stats[0])
+	}
+}
+
+func TestSQLiteStore_HookDeliveries(t *testing.T) {
+	store, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.EnsureHookDeliveriesTable(); err != nil {
+		t.Fatalf("failed to create hook deliveries table: %v", err)
+	}
+
+	now := time.Now()
+	old := HookDeliveryData{ID: "hook-1", Mapping: "github", Path: "/hooks/github", Event: "push", Status: "accepted", ReceivedAt: now.Add(-48 * time.Hour)}
+	recent := HookDeliveryData{ID: "hook-2", Mapping: "ci", Path: "/hooks/ci", Status: "running", ReceivedAt: now}
+	for _, d := range []*HookDeliveryData{&old, &recent} {
+		if err := store.SaveHookDelivery(d); err != nil {
+			t.Fatalf("failed to save delivery: %v", err)
+		}
+	}
+
+	// Saving again updates the delivery
+	recent.Status = "delivered"
+	recent.Target = "telegram:42"
+	recent.FinishedAt = now
+	if err := store.SaveHookDelivery(&recent); err != nil {
+		t.Fatalf("failed to update delivery: %v", err)
+	}
+
+	deliveries, err := store.LoadHookDeliveries(10)
+	if err != nil || len(deliveries) != 2 {
+		t.Fatalf("expected 2 deliveries, got %d (%v)", len(deliveries), err)
+	}
+	if d := deliveries[0]; d.ID != "hook-2" || d.Status != "delivered" || d.Target != "telegram:42" || d.FinishedAt.IsZero() {
+		t.Errorf("unexpected newest delivery: %+v", d)
+	}
+	if d := deliveries[1]; d.Event != "push" || !d.FinishedAt.IsZero() {
+		t.Errorf("unexpected oldest delivery: %+v", d)
+	}
+
+	removed, err := store.CleanOldHookDeliveries(24 * time.Hour)
+	if err != nil || removed != 1 {
+		t.Errorf("expected 1 removed delivery, got %d (%v)", removed, err)
+	}
+}
+
+func TestSQLiteStore_WebhookOutbox(t *testing.T) {
+	store, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.EnsureWebhookOutboxTable(); err != nil {
+		t.Fatalf("failed to create webhook outbox table: %v", err)
+	}
+
+	now := time.Now()
+	due := WebhookDeliveryData{ID: "d-1", Subscription: "ci", URL: "https://example.com", EventID: "e-1", EventType: "reply.sent",
+		Payload: []byte(`{"type":"reply.sent"}`), Status: "pending", NextAttempt: now.Add(-time.Second), CreatedAt: now.Add(-time.Minute), UpdatedAt: now}
+	later := due
+	later.ID, later.NextAttempt, later.CreatedAt = "d-2", now.Add(time.Hour), now
+	failed := due
+	failed.ID, failed.Status, failed.Attempts, failed.LastError = "d-3", "failed", 8, "HTTP 500"
+	failed.UpdatedAt = now.Add(-10 * 24 * time.Hour)
+	for _, d := range []*WebhookDeliveryData{&due, &later, &failed} {
+		if err := store.SaveWebhookDelivery(d); err != nil {
+			t.Fatalf("failed to save delivery: %v", err)
+		}
+	}
+
+	dueList, err := store.LoadDueWebhookDeliveries(now, 10)
+	if err != nil || len(dueList) != 1 || dueList[0].ID != "d-1" || string(dueList[0].Payload) != `{"type":"reply.sent"}` {
+		t.Fatalf("unexpected due deliveries: %+v (%v)", dueList, err)
+	}
+
+	all, _ := store.ListWebhookDeliveries("", 10)
+	if len(all) != 3 || all[0].ID != "d-2" {
+		t.Errorf("expected 3 deliveries, newest first: %+v", all)
+	}
+	failedList, _ := store.ListWebhookDeliveries("failed", 10)
+	if len(failedList) != 1 || failedList[0].Attempts != 8 || failedList[0].LastError != "HTTP 500" {
+		t.Errorf("unexpected failed deliveries: %+v", failedList)
+	}
+
+	loaded, err := store.LoadWebhookDelivery("d-2")
+	if err != nil || loaded == nil || loaded.EventType != "reply.sent" {
+		t.Errorf("unexpected delivery: %+v (%v)", loaded, err)
+	}
+	if missing, _ := store.LoadWebhookDelivery("nope"); missing != nil {
+		t.Error("unknown delivery should not be found")
+	}
+
+	removed, err := store.CleanOldWebhookDeliveries(7 * 24 * time.Hour)
+	if err != nil || removed != 1 {
+		t.Errorf("expected 1 removed delivery, got %d (%v)", removed, err)
+	}
+}
+
+func TestSQLiteStore_APIKeys(t *testing.T) {
+	store, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.EnsureAPIKeysTable(); err != nil {
+		t.Fatalf("failed to create api_keys table: %v", err)
+	}
+
+	now := time.Now()
+	ci := APIKeyData{ID: "k-1", Name: "ci", Prefix: "fpk_1234", Hash: "h1", Scopes: "chat", Models: "claude-3-haiku-20240307",
+		RateLimit: 10, ServerTools: true, CreatedAt: now.Add(-time.Hour)}
+	expired := APIKeyData{ID: "k-2", Name: "old", Prefix: "fpk_5678", Hash: "h2", Scopes: "admin",
+		ExpiresAt: now.Add(-time.Minute), CreatedAt: now}
+	for _, k := range []*APIKeyData{&ci, &expired} {
+		if err := store.SaveAPIKey(k); err != nil {
+			t.Fatalf("failed to save key: %v", err)
+		}
+	}
+
+	all, err := store.LoadAPIKeys()
+	if err != nil || len(all) != 2 || all[0].ID != "k-2" {
+		t.Fatalf("expected 2 keys, newest first: %+v (%v)", all, err)
+	}
+	if n, _ := store.CountAPIKeys(); n != 2 {
+		t.Errorf("expected 2 keys, got %d", n)
+	}
+
+	if err := store.TouchAPIKey("k-1", now); err != nil {
+		t.Fatalf("TouchAPIKey error: %v", err)
+	}
+	if err := store.AddAPIKeyUsage("k-1", 100, 20); err != nil {
+		t.Fatalf("AddAPIKeyUsage error: %v", err)
+	}
+	loaded, err := store.LoadAPIKeyByHash("h1")
+	if err != nil || loaded == nil {
+		t.Fatalf("key not found by hash: %v", err)
+	}
+	if loaded.Requests != 1 || loaded.InputTokens != 100 || loaded.OutputTokens != 20 || loaded.LastUsedAt.Unix() != now.Unix() {
+		t.Errorf("unexpected usage: %+v", loaded)
+	}
+	if !loaded.ExpiresAt.IsZero() || !loaded.RevokedAt.IsZero() || loaded.Models != ci.Models || loaded.RateLimit != 10 || !loaded.ServerTools {
+		t.Errorf("unexpected key: %+v", loaded)
+	}
+	if missing, _ := store.LoadAPIKeyByHash("nope"); missing != nil {
+		t.Error("unknown hash should not be found")
+	}
+
+	loaded.RevokedAt = now
+	store.SaveAPIKey(loaded)
+	if n, _ := store.CountAPIKeys(); n != 2 {
+		t.Errorf("revoked keys should still be counted, got %d", n)
+	}
+}
+
+func TestSQLiteStore_DashboardSessions(t *testing.T) {
+	store, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+	if err := store.EnsureDashboardSessionsTable(); err != nil {
+		t.Fatalf("failed to create dashboard_sessions table: %v", err)
+	}
+
+	now := time.Now()
+	active := DashboardSessionData{ID: "s-1", Role: "admin", Label: "password", CSRFToken: "csrf",
+		CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+	expired := DashboardSessionData{ID: "s-2", Role: "viewer", CSRFToken: "csrf2",
+		CreatedAt: now.Add(-2 * time.Hour), ExpiresAt: now.Add(-time.Hour)}
+	for _, d := range []*DashboardSessionData{&active, &expired} {
+		if err := store.SaveDashboardSession(d); err != nil {
+			t.Fatalf("failed to save session: %v", err)
+		}
+	}
+
+	sessions, err := store.LoadDashboardSessions(now)
+	if err != nil || len(sessions) != 1 || sessions[0].ID != "s-1" || sessions[0].CSRFToken != "csrf" || sessions[0].Label != "password" {
+		t.Fatalf("expected only the active session: %+v (%v)", sessions, err)
+	}
+
+	if err := store.DeleteDashboardSession("s-1"); err != nil {
+		t.Fatalf("DeleteDashboardSession error: %v", err)
+	}
+	if sessions, _ := store.LoadDashboardSessions(now); len(sessions) != 0 {
+		t.Errorf("expected no sessions, got %d", len(sessions))
+	}
+}
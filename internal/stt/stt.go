@@ -0,0 +1,152 @@
+// Package stt provides speech-to-text transcription using local commands
+package stt
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ErrNoSTTCommand is returned when no STT command is configured
+var ErrNoSTTCommand = errors.New("no STT command configured")
+
+// DefaultTimeout is used when no transcription timeout is configured
+const DefaultTimeout = 120 * time.Second
+
+// Transcriber converts audio to text with an external command.
+// Audio is first converted to 16kHz mono WAV with ffmpeg, which is the
+// format whisper.cpp and most local engines expect.
+type Transcriber struct {
+	Command  string        // Command template, e.g. "whisper-cli -m ggml-base.bin -nt -f {input}"
+	FFmpeg   string        // ffmpeg binary for conversion (empty = pass audio through unchanged)
+	Language string        // Language hint substituted for {language} (default: auto)
+	Timeout  time.Duration // Max time for conversion plus transcription
+}
+
+// New creates a new Transcriber for the given command template
+func New(command, ffmpeg, language string, timeout time.Duration) *Transcriber {
+	if language == "" {
+		language = "auto"
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Transcriber{
+		Command:  command,
+		FFmpeg:   ffmpeg,
+		Language: language,
+		Timeout:  timeout,
+	}
+}
+
+// Available returns true if the STT command (and ffmpeg, if set) are installed
+func (t *Transcriber) Available() bool {
+	fields := strings.Fields(t.Command)
+	if len(fields) == 0 {
+		return false
+	}
+	if _, err := exec.LookPath(fields[0]); err != nil {
+		return false
+	}
+	if t.FFmpeg != "" {
+		if _, err := exec.LookPath(t.FFmpeg); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// BuildCommand expands the command template for the given input file.
+// {input} is replaced with the audio path and {language} with the language hint.
+// If the template has no {input} placeholder, the path is appended.
+func (t *Transcriber) BuildCommand(input string) (cmd string, args []string) {
+	fields := strings.Fields(t.Command)
+	if len(fields) == 0 {
+		return "", nil
+	}
+
+	hasInput := false
+	args = make([]string, 0, len(fields))
+	for _, f := range fields[1:] {
+		if strings.Contains(f, "{input}") {
+			hasInput = true
+		}
+		f = strings.ReplaceAll(f, "{input}", input)
+		f = strings.ReplaceAll(f, "{language}", t.Language)
+		args = append(args, f)
+	}
+	if !hasInput {
+		args = append(args, input)
+	}
+	return fields[0], args
+}
+
+// ConvertArgs returns the ffmpeg arguments converting input to 16kHz mono WAV
+func (t *Transcriber) ConvertArgs(input, output string) []string {
+	return []string{"-y", "-loglevel", "error", "-i", input, "-ar", "16000", "-ac", "1", "-c:a", "pcm_s16le", output}
+}
+
+// Transcribe converts the audio to text. ext is the source file extension
+// (e.g. ".oga" for Telegram voice notes).
+func (t *Transcriber) Transcribe(ctx context.Context, audio []byte, ext string) (string, error) {
+	if strings.TrimSpace(t.Command) == "" {
+		return "", ErrNoSTTCommand
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, t.Timeout)
+	defer cancel()
+
+	dir, err := os.MkdirTemp("", "feelpulse-stt-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if ext == "" {
+		ext = ".ogg"
+	}
+	input := filepath.Join(dir, "input"+ext)
+	if err := os.WriteFile(input, audio, 0600); err != nil {
+		return "", fmt.Errorf("failed to write audio: %w", err)
+	}
+
+	// Convert to WAV unless conversion is disabled
+	if t.FFmpeg != "" {
+		wav := filepath.Join(dir, "input.wav")
+		if out, err := exec.CommandContext(ctx, t.FFmpeg, t.ConvertArgs(input, wav)...).CombinedOutput(); err != nil {
+			return "", fmt.Errorf("ffmpeg conversion failed: %w: %s", err, strings.TrimSpace(string(out)))
+		}
+		input = wav
+	}
+
+	cmdName, args := t.BuildCommand(input)
+	cmd := exec.CommandContext(ctx, cmdName, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("transcription failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return CleanTranscript(stdout.String()), nil
+}
+
+// timestampRe matches whisper.cpp segment timestamps like "[00:00:00.000 --> 00:00:02.500]"
+var timestampRe = regexp.MustCompile(`\[\d{2}:\d{2}(:\d{2})?[.,]\d{3} --> \d{2}:\d{2}(:\d{2})?[.,]\d{3}\]`)
+
+// blankAudioRe matches markers engines emit for silence
+var blankAudioRe = regexp.MustCompile(`(?i)\[(BLANK_AUDIO|silence|music)\]`)
+
+// CleanTranscript strips timestamps and silence markers and collapses whitespace
+func CleanTranscript(text string) string {
+	text = timestampRe.ReplaceAllString(text, "")
+	text = blankAudioRe.ReplaceAllString(text, "")
+	return strings.Join(strings.Fields(text), " ")
+}
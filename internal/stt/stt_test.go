@@ -0,0 +1,119 @@
+package stt
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"reflect"
+	"testing"
+)
+
+func TestTranscriber_BuildCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		command  string
+		language string
+		wantCmd  string
+		wantArgs []string
+	}{
+		{
+			name:     "input placeholder",
+			command:  "whisper-cli -m model.bin -nt -f {input}",
+			wantCmd:  "whisper-cli",
+			wantArgs: []string{"-m", "model.bin", "-nt", "-f", "/tmp/a.wav"},
+		},
+		{
+			name:     "language placeholder",
+			command:  "whisper-cli -l {language} -f {input}",
+			language: "de",
+			wantCmd:  "whisper-cli",
+			wantArgs: []string{"-l", "de", "-f", "/tmp/a.wav"},
+		},
+		{
+			name:     "no placeholder appends input",
+			command:  "transcribe --plain",
+			wantCmd:  "transcribe",
+			wantArgs: []string{"--plain", "/tmp/a.wav"},
+		},
+		{
+			name:     "empty command",
+			command:  "",
+			wantCmd:  "",
+			wantArgs: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := New(tt.command, "", tt.language, 0)
+			cmd, args := tr.BuildCommand("/tmp/a.wav")
+			if cmd != tt.wantCmd {
+				t.Errorf("cmd = %q, want %q", cmd, tt.wantCmd)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestNew_Defaults(t *testing.T) {
+	tr := New("whisper", "ffmpeg", "", 0)
+	if tr.Language != "auto" {
+		t.Errorf("Language = %q, want auto", tr.Language)
+	}
+	if tr.Timeout != DefaultTimeout {
+		t.Errorf("Timeout = %v, want %v", tr.Timeout, DefaultTimeout)
+	}
+}
+
+func TestCleanTranscript(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"  hello   world \n", "hello world"},
+		{"[00:00:00.000 --> 00:00:02.500]  Remind me at five.\n[00:00:02.500 --> 00:00:04.000]  Thanks.", "Remind me at five. Thanks."},
+		{"[BLANK_AUDIO]", ""},
+		{"[00:00.000 --> 00:02.000] short form", "short form"},
+	}
+
+	for _, tt := range tests {
+		if got := CleanTranscript(tt.input); got != tt.expected {
+			t.Errorf("CleanTranscript(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestTranscribe_NoCommand(t *testing.T) {
+	tr := New("", "", "", 0)
+	_, err := tr.Transcribe(context.Background(), []byte("audio"), ".oga")
+	if !errors.Is(err, ErrNoSTTCommand) {
+		t.Errorf("expected ErrNoSTTCommand, got %v", err)
+	}
+}
+
+func TestTranscribe_RunsCommand(t *testing.T) {
+	if _, err := exec.LookPath("cat"); err != nil {
+		t.Skip("cat not available")
+	}
+
+	// "cat {input}" echoes the audio file back, standing in for a real engine
+	tr := New("cat {input}", "", "", 0)
+	text, err := tr.Transcribe(context.Background(), []byte("what's the weather\n"), ".txt")
+	if err != nil {
+		t.Fatalf("Transcribe failed: %v", err)
+	}
+	if text != "what's the weather" {
+		t.Errorf("text = %q, want %q", text, "what's the weather")
+	}
+}
+
+func TestAvailable(t *testing.T) {
+	if New("", "", "", 0).Available() {
+		t.Error("empty command should not be available")
+	}
+	if New("definitely-not-a-real-stt-binary", "", "", 0).Available() {
+		t.Error("missing binary should not be available")
+	}
+}
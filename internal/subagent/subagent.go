@@ -82,11 +82,15 @@ type AgentRunner interface {
 // duration is the execution time of the sub-agent
 type OnCompleteFunc func(agentID, label, result, parentSessionKey string, duration time.Duration, err error)
 
+// OnStartFunc is called when a sub-agent starts running
+type OnStartFunc func(agentID, label, task, parentSessionKey string)
+
 // Manager spawns and tracks sub-agents
 type Manager struct {
 	agents        map[string]*SubAgent
 	mu            sync.RWMutex
 	onComplete    OnCompleteFunc
+	onStart       OnStartFunc
 	maxRuntime    time.Duration
 	maxIterations int
 	persister     Persister
@@ -102,6 +106,11 @@ func NewManager(onComplete OnCompleteFunc) *Manager {
 	}
 }
 
+// SetOnStart sets a function called when a sub-agent starts running
+func (m *Manager) SetOnStart(onStart OnStartFunc) {
+	m.onStart = onStart
+}
+
 // SetMaxRuntime sets the maximum runtime for sub-agents
 func (m *Manager) SetMaxRuntime(d time.Duration) {
 	m.maxRuntime = d
@@ -273,6 +282,10 @@ func (m *Manager) runAgent(ctx context.Context, agent *SubAgent, runner AgentRun
 	// Persist running status
 	m.persist(agent)
 
+	if m.onStart != nil {
+		m.onStart(agent.ID, agent.Label, agent.Task, agent.ParentSessionKey)
+	}
+
 	// Default system prompt if not provided
 	systemPrompt := agent.SystemPrompt
 	if systemPrompt == "" {
@@ -105,6 +105,25 @@ func TestManagerSpawnAndComplete(t *testing.T) {
 	}
 }
 
+func TestManagerOnStart(t *testing.T) {
+	done := make(chan struct{})
+	manager := NewManager(func(agentID, label, result, parentKey string, duration time.Duration, err error) {
+		close(done)
+	})
+
+	var startedID, startedLabel, startedTask, startedParent string
+	manager.SetOnStart(func(agentID, label, task, parentKey string) {
+		startedID, startedLabel, startedTask, startedParent = agentID, label, task, parentKey
+	})
+
+	agentID := manager.Spawn("test task", "test-agent", "", "telegram:123", &MockAgentRunner{}, nil)
+	<-done
+
+	if startedID != agentID || startedLabel != "test-agent" || startedTask != "test task" || startedParent != "telegram:123" {
+		t.Errorf("unexpected start callback: %s %s %s %s", startedID, startedLabel, startedTask, startedParent)
+	}
+}
+
 func TestManagerSpawnWithError(t *testing.T) {
 	var completedErr error
 	var wg sync.WaitGroup
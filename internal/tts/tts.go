@@ -2,18 +2,34 @@
 package tts
 
 import (
+	"bytes"
+	"context"
 	"errors"
+	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
 // ErrNoTTSCommand is returned when no TTS command is configured or available
 var ErrNoTTSCommand = errors.New("no TTS command available")
 
+// ErrNoFFmpeg is returned when synthesis needs ffmpeg but none is configured
+var ErrNoFFmpeg = errors.New("ffmpeg is required to encode voice notes")
+
+// defaultWordsPerMinute is the normal speaking rate of espeak and say
+const defaultWordsPerMinute = 175
+
 // Speaker handles text-to-speech output
 type Speaker struct {
-	Command string // The TTS command to use (espeak, say, festival, etc.)
+	Command   string  // The TTS command to use (espeak, say, festival, piper, etc.)
+	Voice     string  // Voice name (espeak/say) or model path (piper); empty = engine default
+	Speed     float64 // Speaking rate multiplier (1.0 = normal, 0 = engine default)
+	FFmpeg    string  // ffmpeg binary used to encode OGG/Opus voice notes
+	MaxLength int     // Max characters to synthesize (0 = unlimited)
 }
 
 // New creates a new Speaker with the given command.
@@ -148,3 +164,116 @@ func (s *Speaker) SanitizeText(text string) string {
 
 	return strings.TrimSpace(text)
 }
+
+// BuildSynthesizeCommand builds the command that renders text to an audio
+// file in dir. Returns the command, arguments, whether the text is passed on
+// stdin, and the path of the file the engine will write. cmd is empty when
+// the engine cannot write to a file.
+func (s *Speaker) BuildSynthesizeCommand(text, dir string) (cmd string, args []string, needsStdin bool, output string) {
+	output = filepath.Join(dir, "speech.wav")
+
+	switch s.Command {
+	case "espeak", "espeak-ng":
+		// Text goes on stdin so a leading "-" isn't taken for an option
+		args = []string{"--stdin", "-w", output}
+		if s.Voice != "" {
+			args = append(args, "-v", s.Voice)
+		}
+		if s.Speed > 0 {
+			args = append(args, "-s", strconv.Itoa(int(defaultWordsPerMinute*s.Speed)))
+		}
+		return s.Command, args, true, output
+	case "say":
+		// macOS say writes AIFF; ffmpeg converts it like any other input.
+		// "-f -" reads the text from stdin
+		output = filepath.Join(dir, "speech.aiff")
+		args = []string{"-f", "-", "-o", output}
+		if s.Voice != "" {
+			args = append(args, "-v", s.Voice)
+		}
+		if s.Speed > 0 {
+			args = append(args, "-r", strconv.Itoa(int(defaultWordsPerMinute*s.Speed)))
+		}
+		return s.Command, args, true, output
+	case "piper":
+		// Piper reads text from stdin; length_scale > 1 is slower
+		args = []string{"--output_file", output}
+		if s.Voice != "" {
+			args = append(args, "--model", s.Voice)
+		}
+		if s.Speed > 0 {
+			args = append(args, "--length_scale", strconv.FormatFloat(1/s.Speed, 'f', 2, 64))
+		}
+		return s.Command, args, true, output
+	case "festival", "text2wave":
+		// Festival ships text2wave for file output
+		return "text2wave", []string{"-o", output}, true, output
+	default:
+		return "", nil, false, ""
+	}
+}
+
+// Synthesize renders text to an OGG/Opus voice note and returns its contents.
+// The text is sanitized and truncated to MaxLength first. Returns nil audio
+// when there is nothing to say.
+func (s *Speaker) Synthesize(ctx context.Context, text string) ([]byte, error) {
+	if s.Command == "" {
+		return nil, ErrNoTTSCommand
+	}
+	if s.FFmpeg == "" {
+		return nil, ErrNoFFmpeg
+	}
+
+	text = TruncateText(s.SanitizeText(text), s.MaxLength)
+	if text == "" {
+		return nil, nil
+	}
+
+	dir, err := os.MkdirTemp("", "feelpulse-tts-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	cmdName, args, needsStdin, rendered := s.BuildSynthesizeCommand(text, dir)
+	if cmdName == "" {
+		return nil, fmt.Errorf("TTS command %q cannot write audio files", s.Command)
+	}
+
+	cmd := exec.CommandContext(ctx, cmdName, args...)
+	if needsStdin {
+		cmd.Stdin = strings.NewReader(text)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("speech synthesis failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	voice := filepath.Join(dir, "speech.ogg")
+	if out, err := exec.CommandContext(ctx, s.FFmpeg, EncodeArgs(rendered, voice)...).CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("ffmpeg encoding failed: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+
+	return os.ReadFile(voice)
+}
+
+// EncodeArgs returns the ffmpeg arguments encoding input as an OGG/Opus voice note
+func EncodeArgs(input, output string) []string {
+	return []string{"-y", "-loglevel", "error", "-i", input, "-ac", "1", "-c:a", "libopus", "-b:a", "32k", "-application", "voip", output}
+}
+
+// TruncateText shortens text to at most maxLen characters, cutting at a
+// word boundary when possible. maxLen <= 0 means no limit.
+func TruncateText(text string, maxLen int) string {
+	runes := []rune(text)
+	if maxLen <= 0 || len(runes) <= maxLen {
+		return text
+	}
+
+	cut := string(runes[:maxLen])
+	if i := strings.LastIndexAny(cut, " \n"); i > maxLen/2 {
+		cut = cut[:i]
+	}
+	return strings.TrimSpace(cut) + "…"
+}
@@ -1,6 +1,7 @@
 package tts
 
 import (
+	"context"
 	"os/exec"
 	"testing"
 )
@@ -226,3 +227,127 @@ func TestSpeaker_SanitizeText(t *testing.T) {
 		})
 	}
 }
+
+func TestSpeaker_BuildSynthesizeCommand(t *testing.T) {
+	tests := []struct {
+		name      string
+		speaker   Speaker
+		wantCmd   string
+		wantArgs  []string
+		wantStdin bool
+		wantOut   string
+	}{
+		{
+			name:      "espeak with voice and speed",
+			speaker:   Speaker{Command: "espeak", Voice: "en-us", Speed: 1.2},
+			wantCmd:   "espeak",
+			wantArgs:  []string{"--stdin", "-w", "/tmp/x/speech.wav", "-v", "en-us", "-s", "210"},
+			wantStdin: true,
+			wantOut:   "/tmp/x/speech.wav",
+		},
+		{
+			name:      "say writes aiff",
+			speaker:   Speaker{Command: "say"},
+			wantCmd:   "say",
+			wantArgs:  []string{"-f", "-", "-o", "/tmp/x/speech.aiff"},
+			wantStdin: true,
+			wantOut:   "/tmp/x/speech.aiff",
+		},
+		{
+			name:      "piper reads stdin",
+			speaker:   Speaker{Command: "piper", Voice: "/models/en.onnx", Speed: 2},
+			wantCmd:   "piper",
+			wantArgs:  []string{"--output_file", "/tmp/x/speech.wav", "--model", "/models/en.onnx", "--length_scale", "0.50"},
+			wantStdin: true,
+			wantOut:   "/tmp/x/speech.wav",
+		},
+		{
+			name:      "festival uses text2wave",
+			speaker:   Speaker{Command: "festival"},
+			wantCmd:   "text2wave",
+			wantArgs:  []string{"-o", "/tmp/x/speech.wav"},
+			wantStdin: true,
+			wantOut:   "/tmp/x/speech.wav",
+		},
+		{
+			name:    "unknown command unsupported",
+			speaker: Speaker{Command: "my-tts"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, args, stdin, out := tt.speaker.BuildSynthesizeCommand("Hello", "/tmp/x")
+			if cmd != tt.wantCmd {
+				t.Errorf("cmd = %q, want %q", cmd, tt.wantCmd)
+			}
+			if len(args) != len(tt.wantArgs) {
+				t.Fatalf("args = %v, want %v", args, tt.wantArgs)
+			}
+			for i := range args {
+				if args[i] != tt.wantArgs[i] {
+					t.Errorf("args[%d] = %q, want %q", i, args[i], tt.wantArgs[i])
+				}
+			}
+			if stdin != tt.wantStdin {
+				t.Errorf("needsStdin = %v, want %v", stdin, tt.wantStdin)
+			}
+			if out != tt.wantOut {
+				t.Errorf("output = %q, want %q", out, tt.wantOut)
+			}
+		})
+	}
+}
+
+func TestSpeaker_BuildSynthesizeCommand_DashText(t *testing.T) {
+	// Replies like "- item" must not reach the engine as options
+	text := "- -w /etc/passwd"
+	for _, command := range []string{"espeak", "espeak-ng", "say", "piper", "festival"} {
+		sp := Speaker{Command: command}
+		_, args, stdin, _ := sp.BuildSynthesizeCommand(text, "/tmp/x")
+		if !stdin {
+			t.Errorf("%s: text should be passed on stdin", command)
+		}
+		for _, arg := range args {
+			if arg == text {
+				t.Errorf("%s: text passed as an argument: %v", command, args)
+			}
+		}
+	}
+}
+
+func TestSpeaker_Synthesize_Errors(t *testing.T) {
+	if _, err := (&Speaker{}).Synthesize(context.Background(), "Hello"); err != ErrNoTTSCommand {
+		t.Errorf("expected ErrNoTTSCommand, got %v", err)
+	}
+	if _, err := (&Speaker{Command: "espeak"}).Synthesize(context.Background(), "Hello"); err != ErrNoFFmpeg {
+		t.Errorf("expected ErrNoFFmpeg, got %v", err)
+	}
+
+	audio, err := (&Speaker{Command: "espeak", FFmpeg: "ffmpeg"}).Synthesize(context.Background(), "🎉")
+	if err != nil || audio != nil {
+		t.Errorf("expected nothing to synthesize for emoji-only text, got %v, %v", audio, err)
+	}
+}
+
+func TestTruncateText(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		maxLen int
+		want   string
+	}{
+		{"no limit", "hello world", 0, "hello world"},
+		{"short enough", "hello world", 20, "hello world"},
+		{"cut at word", "the quick brown fox jumps", 12, "the quick…"},
+		{"no space to cut", "abcdefghijkl", 5, "abcde…"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TruncateText(tt.input, tt.maxLen); got != tt.want {
+				t.Errorf("TruncateText(%q, %d) = %q, want %q", tt.input, tt.maxLen, got, tt.want)
+			}
+		})
+	}
+}
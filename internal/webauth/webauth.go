@@ -0,0 +1,237 @@
+// Package webauth manages browser logins to the dashboard: cookie sessions
+// with a CSRF token each, and single-use login links.
+package webauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Roles
+const (
+	RoleAdmin  = "admin"  // Full access
+	RoleViewer = "viewer" // Read-only
+)
+
+const (
+	// CookieName is the name of the session cookie
+	CookieName = "feelpulse_session"
+
+	// CSRFHeader carries the CSRF token of mutating requests
+	CSRFHeader = "X-CSRF-Token"
+
+	// DefaultSessionTTL is how long a login lasts
+	DefaultSessionTTL = 24 * time.Hour
+
+	// LoginLinkTTL is how long a login link can be used
+	LoginLinkTTL = 10 * time.Minute
+)
+
+// Session is a dashboard login
+type Session struct {
+	ID        string // SHA-256 of the cookie value, hex
+	Role      string
+	Label     string // How the session logged in, e.g. "password"
+	CSRFToken string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// ReadOnly reports whether the session may only view
+func (s *Session) ReadOnly() bool {
+	return s.Role != RoleAdmin
+}
+
+// Persister stores sessions so logins survive restarts
+type Persister interface {
+	SaveSession(s *Session) error
+	LoadSessions(now time.Time) ([]*Session, error) // Unexpired sessions
+	DeleteSession(id string) error
+}
+
+// Manager keeps dashboard sessions and login links
+type Manager struct {
+	sessions  map[string]*Session  // By ID
+	links     map[string]time.Time // Link code hash -> expiry
+	persister Persister
+	now       func() time.Time
+	mu        sync.Mutex
+}
+
+// NewManager creates a session manager
+func NewManager() *Manager {
+	return &Manager{
+		sessions: make(map[string]*Session),
+		links:    make(map[string]time.Time),
+		now:      time.Now,
+	}
+}
+
+// SetPersister sets the persistence backend and loads existing sessions
+func (m *Manager) SetPersister(p Persister) error {
+	sessions, err := p.LoadSessions(m.now())
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.persister = p
+	for _, s := range sessions {
+		m.sessions[s.ID] = s
+	}
+	return nil
+}
+
+// Login starts a session and returns the cookie value for it
+func (m *Manager) Login(role, label string, ttl time.Duration) (string, *Session, error) {
+	if role != RoleAdmin && role != RoleViewer {
+		return "", nil, fmt.Errorf("unknown role '%s'", role)
+	}
+	if ttl <= 0 {
+		ttl = DefaultSessionTTL
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		return "", nil, err
+	}
+	csrf, err := randomToken()
+	if err != nil {
+		return "", nil, err
+	}
+
+	now := m.now()
+	s := &Session{
+		ID:        hash(token),
+		Role:      role,
+		Label:     label,
+		CSRFToken: csrf,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.persister != nil {
+		if err := m.persister.SaveSession(s); err != nil {
+			return "", nil, fmt.Errorf("failed to save session: %w", err)
+		}
+	}
+	m.sessions[s.ID] = s
+	return token, s, nil
+}
+
+// Get returns the session for a cookie value, or nil if it is unknown or expired
+func (m *Manager) Get(token string) *Session {
+	if token == "" {
+		return nil
+	}
+	id := hash(token)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.sessions[id]
+	if !ok {
+		return nil
+	}
+	if !m.now().Before(s.ExpiresAt) {
+		m.deleteLocked(id)
+		return nil
+	}
+	return s
+}
+
+// Logout ends the session for a cookie value
+func (m *Manager) Logout(token string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deleteLocked(hash(token))
+}
+
+func (m *Manager) deleteLocked(id string) {
+	if _, ok := m.sessions[id]; !ok {
+		return
+	}
+	delete(m.sessions, id)
+	if m.persister != nil {
+		_ = m.persister.DeleteSession(id)
+	}
+}
+
+// Count returns the number of active sessions
+func (m *Manager) Count() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.sessions)
+}
+
+// NewLoginLink returns a single-use code that logs in as admin within LoginLinkTTL
+func (m *Manager) NewLoginLink() (string, error) {
+	code, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+	for h, expires := range m.links {
+		if !now.Before(expires) {
+			delete(m.links, h)
+		}
+	}
+	m.links[hash(code)] = now.Add(LoginLinkTTL)
+	return code, nil
+}
+
+// RedeemLoginLink consumes a login link code and reports whether it was valid
+func (m *Manager) RedeemLoginLink(code string) bool {
+	if code == "" {
+		return false
+	}
+	h := hash(code)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	expires, ok := m.links[h]
+	delete(m.links, h)
+	return ok && m.now().Before(expires)
+}
+
+// CheckCSRF reports whether a request's CSRF token matches its session
+func CheckCSRF(s *Session, token string) bool {
+	return s != nil && token != "" && subtle.ConstantTimeCompare([]byte(s.CSRFToken), []byte(token)) == 1
+}
+
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+type contextKey struct{}
+
+// NewContext returns a context carrying the session a request was made with
+func NewContext(ctx context.Context, s *Session) context.Context {
+	return context.WithValue(ctx, contextKey{}, s)
+}
+
+// FromContext returns the session a request was made with, or nil
+func FromContext(ctx context.Context) *Session {
+	s, _ := ctx.Value(contextKey{}).(*Session)
+	return s
+}
@@ -0,0 +1,130 @@
+package webauth
+
+import (
+	"testing"
+	"time"
+)
+
+// memPersister keeps sessions in memory
+type memPersister struct {
+	sessions map[string]*Session
+}
+
+func (p *memPersister) SaveSession(s *Session) error {
+	p.sessions[s.ID] = s
+	return nil
+}
+
+func (p *memPersister) LoadSessions(now time.Time) ([]*Session, error) {
+	var sessions []*Session
+	for _, s := range p.sessions {
+		if now.Before(s.ExpiresAt) {
+			sessions = append(sessions, s)
+		}
+	}
+	return sessions, nil
+}
+
+func (p *memPersister) DeleteSession(id string) error {
+	delete(p.sessions, id)
+	return nil
+}
+
+func TestManager_Sessions(t *testing.T) {
+	p := &memPersister{sessions: make(map[string]*Session)}
+	m := NewManager()
+	if err := m.SetPersister(p); err != nil {
+		t.Fatalf("SetPersister error: %v", err)
+	}
+	now := time.Now()
+	m.now = func() time.Time { return now }
+
+	token, s, err := m.Login(RoleViewer, "password", time.Hour)
+	if err != nil {
+		t.Fatalf("Login error: %v", err)
+	}
+	if s.ID == token || p.sessions[s.ID] == nil {
+		t.Error("only the hash of the cookie should be stored")
+	}
+	if !s.ReadOnly() || s.CSRFToken == "" {
+		t.Errorf("unexpected session: %+v", s)
+	}
+	if got := m.Get(token); got != s {
+		t.Errorf("Get() = %+v, want %+v", got, s)
+	}
+	if m.Get("guess") != nil || m.Get("") != nil {
+		t.Error("unknown cookies should have no session")
+	}
+
+	// Sessions survive a restart
+	restarted := NewManager()
+	restarted.SetPersister(p)
+	if restarted.Get(token) == nil {
+		t.Error("persisted session not loaded")
+	}
+
+	now = now.Add(2 * time.Hour)
+	if m.Get(token) != nil || len(p.sessions) != 0 {
+		t.Error("expired session should be deleted")
+	}
+
+	token, _, _ = m.Login(RoleAdmin, "password", 0)
+	m.Logout(token)
+	if m.Get(token) != nil || m.Count() != 0 {
+		t.Error("session should end on logout")
+	}
+
+	if _, _, err := m.Login("root", "", time.Hour); err == nil {
+		t.Error("unknown role should fail")
+	}
+}
+
+func TestManager_LoginLinks(t *testing.T) {
+	m := NewManager()
+	now := time.Now()
+	m.now = func() time.Time { return now }
+
+	code, err := m.NewLoginLink()
+	if err != nil {
+		t.Fatalf("NewLoginLink error: %v", err)
+	}
+	if !m.RedeemLoginLink(code) {
+		t.Error("fresh link should be valid")
+	}
+	if m.RedeemLoginLink(code) {
+		t.Error("link should be single-use")
+	}
+
+	code, _ = m.NewLoginLink()
+	now = now.Add(LoginLinkTTL)
+	if m.RedeemLoginLink(code) {
+		t.Error("expired link should be rejected")
+	}
+	if m.RedeemLoginLink("") {
+		t.Error("empty code should be rejected")
+	}
+}
+
+func TestCheckCSRF(t *testing.T) {
+	s := &Session{CSRFToken: "abc"}
+
+	tests := []struct {
+		name    string
+		session *Session
+		token   string
+		want    bool
+	}{
+		{"match", s, "abc", true},
+		{"mismatch", s, "abd", false},
+		{"empty", s, "", false},
+		{"no session", nil, "abc", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CheckCSRF(tt.session, tt.token); got != tt.want {
+				t.Errorf("CheckCSRF() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,494 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/FeelPulse/feelpulse/internal/events"
+	"github.com/FeelPulse/feelpulse/internal/logger"
+	"github.com/google/uuid"
+)
+
+// Event types, the same as on the internal event bus
+const (
+	EventMessageReceived   = events.TypeMessageReceived
+	EventReplySent         = events.TypeReplySent
+	EventToolCalled        = events.TypeToolCalled
+	EventSubAgentCompleted = events.TypeSubAgentCompleted
+	EventReminderFired     = events.TypeReminderFired
+	EventConfigReloaded    = events.TypeConfigReloaded
+)
+
+// Events lists the event types subscriptions can select
+var Events = []string{
+	EventMessageReceived,
+	EventReplySent,
+	EventToolCalled,
+	EventSubAgentCompleted,
+	EventReminderFired,
+	EventConfigReloaded,
+}
+
+// IsEvent reports whether name is a known event type
+func IsEvent(name string) bool {
+	for _, e := range Events {
+		if e == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Delivery statuses
+const (
+	StatusPending   = "pending"
+	StatusDelivered = "delivered"
+	StatusFailed    = "failed"
+)
+
+const (
+	// DefaultMaxAttempts is how often a delivery is tried before it fails
+	DefaultMaxAttempts = 8
+
+	// SignatureHeader carries the HMAC-SHA256 of the body, "sha256=<hex>"
+	SignatureHeader = "X-FeelPulse-Signature-256"
+
+	requestTimeout = 10 * time.Second
+	batchSize      = 20
+)
+
+// Subscription sends selected events to a URL
+type Subscription struct {
+	Name   string
+	URL    string
+	Secret string   // Key for the payload signature (empty = unsigned)
+	Events []string // Empty = all events
+}
+
+// wants reports whether the subscription receives an event type
+func (s *Subscription) wants(eventType string) bool {
+	if len(s.Events) == 0 {
+		return true
+	}
+	for _, e := range s.Events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Event is the JSON payload posted to subscribers
+type Event struct {
+	ID   string    `json:"id"`
+	Type string    `json:"type"`
+	Time time.Time `json:"time"`
+	Data any       `json:"data"`
+}
+
+// DeliveryData is one event queued for one subscription
+type DeliveryData struct {
+	ID           string
+	Subscription string
+	URL          string
+	EventID      string
+	EventType    string
+	Payload      []byte
+	Status       string
+	Attempts     int
+	LastError    string
+	NextAttempt  time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// Persister stores the outbox
+type Persister interface {
+	SaveDelivery(d *DeliveryData) error
+	LoadDelivery(id string) (*DeliveryData, error) // nil if not found
+	LoadDueDeliveries(now time.Time, limit int) ([]*DeliveryData, error)
+	ListDeliveries(status string, limit int) ([]*DeliveryData, error) // Newest first; empty status = all
+}
+
+// Sign returns the signature header value for a payload
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Backoff returns the wait after a failed attempt: 30s, doubling up to an hour
+func Backoff(attempt int) time.Duration {
+	wait := 30 * time.Second
+	for i := 1; i < attempt && wait < time.Hour; i++ {
+		wait *= 2
+	}
+	if wait > time.Hour {
+		wait = time.Hour
+	}
+	return wait
+}
+
+// Dispatcher queues events in an outbox and posts them to subscriptions,
+// retrying failed deliveries with backoff
+type Dispatcher struct {
+	mu            sync.RWMutex
+	subscriptions []Subscription
+	persister     Persister
+	maxAttempts   int
+
+	client       *http.Client
+	pollInterval time.Duration
+	wake         chan struct{}
+	stopCh       chan struct{}
+	done         chan struct{}
+	running      bool
+	sendMu       sync.Mutex // One batch at a time
+}
+
+// New creates a dispatcher with an in-memory outbox
+func New() *Dispatcher {
+	return &Dispatcher{
+		persister:    newMemoryOutbox(),
+		maxAttempts:  DefaultMaxAttempts,
+		client:       &http.Client{Timeout: requestTimeout},
+		pollInterval: 5 * time.Second,
+		wake:         make(chan struct{}, 1),
+	}
+}
+
+// SetPersister sets the outbox store, e.g. SQLite
+func (d *Dispatcher) SetPersister(p Persister) {
+	d.mu.Lock()
+	d.persister = p
+	d.mu.Unlock()
+}
+
+// SetSubscriptions replaces the subscriptions
+func (d *Dispatcher) SetSubscriptions(subs []Subscription) {
+	d.mu.Lock()
+	d.subscriptions = subs
+	d.mu.Unlock()
+}
+
+// SetMaxAttempts sets how often a delivery is tried (<= 0 = default)
+func (d *Dispatcher) SetMaxAttempts(n int) {
+	if n <= 0 {
+		n = DefaultMaxAttempts
+	}
+	d.mu.Lock()
+	d.maxAttempts = n
+	d.mu.Unlock()
+}
+
+// Publish queues an event for every subscription that wants it
+func (d *Dispatcher) Publish(eventType string, data any) error {
+	d.mu.RLock()
+	subs := d.subscriptions
+	persister := d.persister
+	d.mu.RUnlock()
+
+	event := Event{ID: uuid.New().String(), Type: eventType, Time: time.Now().UTC(), Data: data}
+	var payload []byte
+	now := time.Now()
+	for _, sub := range subs {
+		if !sub.wants(eventType) {
+			continue
+		}
+		if payload == nil {
+			var err error
+			if payload, err = json.Marshal(event); err != nil {
+				return fmt.Errorf("failed to encode %s event: %w", eventType, err)
+			}
+		}
+		delivery := &DeliveryData{
+			ID:           uuid.New().String(),
+			Subscription: sub.Name,
+			URL:          sub.URL,
+			EventID:      event.ID,
+			EventType:    eventType,
+			Payload:      payload,
+			Status:       StatusPending,
+			NextAttempt:  now,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		}
+		if err := persister.SaveDelivery(delivery); err != nil {
+			return fmt.Errorf("failed to queue %s event: %w", eventType, err)
+		}
+	}
+
+	if payload != nil {
+		d.notify()
+	}
+	return nil
+}
+
+// List returns recent deliveries, newest first (empty status = all)
+func (d *Dispatcher) List(status string, limit int) ([]*DeliveryData, error) {
+	d.mu.RLock()
+	persister := d.persister
+	d.mu.RUnlock()
+	return persister.ListDeliveries(status, limit)
+}
+
+// Replay queues a delivery again with a fresh set of attempts
+func (d *Dispatcher) Replay(id string) (*DeliveryData, error) {
+	d.mu.RLock()
+	persister := d.persister
+	d.mu.RUnlock()
+
+	delivery, err := persister.LoadDelivery(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load delivery: %w", err)
+	}
+	if delivery == nil {
+		return nil, fmt.Errorf("delivery not found: %s", id)
+	}
+	if err := d.requeue(persister, delivery); err != nil {
+		return nil, err
+	}
+	d.notify()
+	return delivery, nil
+}
+
+// ReplayFailed queues all failed deliveries again and returns how many
+func (d *Dispatcher) ReplayFailed() (int, error) {
+	d.mu.RLock()
+	persister := d.persister
+	d.mu.RUnlock()
+
+	failed, err := persister.ListDeliveries(StatusFailed, 1000)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list deliveries: %w", err)
+	}
+	for _, delivery := range failed {
+		if err := d.requeue(persister, delivery); err != nil {
+			return 0, err
+		}
+	}
+	if len(failed) > 0 {
+		d.notify()
+	}
+	return len(failed), nil
+}
+
+func (d *Dispatcher) requeue(persister Persister, delivery *DeliveryData) error {
+	delivery.Status = StatusPending
+	delivery.Attempts = 0
+	delivery.LastError = ""
+	delivery.NextAttempt = time.Now()
+	delivery.UpdatedAt = time.Now()
+	if err := persister.SaveDelivery(delivery); err != nil {
+		return fmt.Errorf("failed to requeue delivery: %w", err)
+	}
+	return nil
+}
+
+// Start begins sending queued deliveries in the background
+func (d *Dispatcher) Start() {
+	d.mu.Lock()
+	if d.running {
+		d.mu.Unlock()
+		return
+	}
+	d.running = true
+	d.stopCh = make(chan struct{})
+	d.done = make(chan struct{})
+	d.mu.Unlock()
+
+	go d.loop()
+}
+
+// Stop stops the background sender; queued deliveries stay in the outbox
+func (d *Dispatcher) Stop() {
+	d.mu.Lock()
+	if !d.running {
+		d.mu.Unlock()
+		return
+	}
+	d.running = false
+	close(d.stopCh)
+	done := d.done
+	d.mu.Unlock()
+
+	<-done
+}
+
+func (d *Dispatcher) notify() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (d *Dispatcher) loop() {
+	defer close(d.done)
+
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+
+	d.SendDue()
+	for {
+		select {
+		case <-d.stopCh:
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+		d.SendDue()
+	}
+}
+
+// SendDue posts the deliveries whose next attempt is due
+func (d *Dispatcher) SendDue() {
+	d.sendMu.Lock()
+	defer d.sendMu.Unlock()
+
+	d.mu.RLock()
+	persister := d.persister
+	subs := make(map[string]Subscription, len(d.subscriptions))
+	for _, sub := range d.subscriptions {
+		subs[sub.Name] = sub
+	}
+	maxAttempts := d.maxAttempts
+	d.mu.RUnlock()
+
+	due, err := persister.LoadDueDeliveries(time.Now(), batchSize)
+	if err != nil {
+		logger.Warn("⚠️ Failed to load webhook outbox: %v", err)
+		return
+	}
+
+	for _, delivery := range due {
+		sub, ok := subs[delivery.Subscription]
+		if !ok {
+			delivery.Attempts = maxAttempts
+			err = fmt.Errorf("subscription %s no longer exists", delivery.Subscription)
+		} else {
+			delivery.Attempts++
+			err = d.send(sub, delivery)
+		}
+
+		delivery.UpdatedAt = time.Now()
+		switch {
+		case err == nil:
+			delivery.Status = StatusDelivered
+			delivery.LastError = ""
+			logger.Debug("📤 Webhook %s delivered to %s", delivery.EventType, delivery.Subscription)
+		case delivery.Attempts >= maxAttempts:
+			delivery.Status = StatusFailed
+			delivery.LastError = err.Error()
+			logger.Warn("⚠️ Webhook %s to %s failed after %d attempts: %v", delivery.EventType, delivery.Subscription, delivery.Attempts, err)
+		default:
+			delivery.LastError = err.Error()
+			delivery.NextAttempt = time.Now().Add(Backoff(delivery.Attempts))
+			logger.Debug("📤 Webhook %s to %s failed (attempt %d), retrying at %s: %v", delivery.EventType, delivery.Subscription, delivery.Attempts, delivery.NextAttempt.Format(time.TimeOnly), err)
+		}
+
+		if err := persister.SaveDelivery(delivery); err != nil {
+			logger.Warn("⚠️ Failed to update webhook delivery %s: %v", delivery.ID, err)
+		}
+	}
+}
+
+// send posts one delivery; any 2xx response counts as delivered
+func (d *Dispatcher) send(sub Subscription, delivery *DeliveryData) error {
+	req, err := http.NewRequest(http.MethodPost, sub.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "FeelPulse-Webhook")
+	req.Header.Set("X-FeelPulse-Event", delivery.EventType)
+	req.Header.Set("X-FeelPulse-Delivery", delivery.ID)
+	if sub.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(sub.Secret, delivery.Payload))
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// memoryOutbox keeps deliveries in memory when no store is configured
+type memoryOutbox struct {
+	mu         sync.Mutex
+	deliveries map[string]*DeliveryData
+}
+
+func newMemoryOutbox() *memoryOutbox {
+	return &memoryOutbox{deliveries: make(map[string]*DeliveryData)}
+}
+
+func (m *memoryOutbox) SaveDelivery(d *DeliveryData) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	saved := *d
+	m.deliveries[d.ID] = &saved
+	return nil
+}
+
+func (m *memoryOutbox) LoadDelivery(id string) (*DeliveryData, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	d, ok := m.deliveries[id]
+	if !ok {
+		return nil, nil
+	}
+	loaded := *d
+	return &loaded, nil
+}
+
+func (m *memoryOutbox) LoadDueDeliveries(now time.Time, limit int) ([]*DeliveryData, error) {
+	due := m.filter(func(d *DeliveryData) bool {
+		return d.Status == StatusPending && !d.NextAttempt.After(now)
+	})
+	sort.Slice(due, func(i, j int) bool { return due[i].NextAttempt.Before(due[j].NextAttempt) })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	return due, nil
+}
+
+func (m *memoryOutbox) ListDeliveries(status string, limit int) ([]*DeliveryData, error) {
+	list := m.filter(func(d *DeliveryData) bool {
+		return status == "" || d.Status == status
+	})
+	sort.Slice(list, func(i, j int) bool { return list[i].CreatedAt.After(list[j].CreatedAt) })
+	if len(list) > limit {
+		list = list[:limit]
+	}
+	return list, nil
+}
+
+func (m *memoryOutbox) filter(keep func(d *DeliveryData) bool) []*DeliveryData {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var result []*DeliveryData
+	for _, d := range m.deliveries {
+		if keep(d) {
+			copied := *d
+			result = append(result, &copied)
+		}
+	}
+	return result
+}
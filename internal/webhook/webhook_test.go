@@ -0,0 +1,168 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder is a webhook receiver that answers with a configurable status
+type recorder struct {
+	mu       sync.Mutex
+	status   int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (rec *recorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.requests = append(rec.requests, r)
+	rec.bodies = append(rec.bodies, body)
+	w.WriteHeader(rec.status)
+}
+
+func (rec *recorder) count() int {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return len(rec.requests)
+}
+
+func TestDispatcher_Deliver(t *testing.T) {
+	rec := &recorder{status: http.StatusOK}
+	server := httptest.NewServer(rec)
+	defer server.Close()
+
+	d := New()
+	d.SetSubscriptions([]Subscription{
+		{Name: "all", URL: server.URL, Secret: "s3cret"},
+		{Name: "tools", URL: server.URL, Events: []string{EventToolCalled}},
+	})
+
+	if err := d.Publish(EventReplySent, map[string]any{"channel": "telegram"}); err != nil {
+		t.Fatalf("Publish error: %v", err)
+	}
+	d.SendDue()
+
+	if rec.count() != 1 {
+		t.Fatalf("expected 1 request, got %d", rec.count())
+	}
+	req, body := rec.requests[0], rec.bodies[0]
+	if req.Header.Get("X-FeelPulse-Event") != EventReplySent {
+		t.Errorf("event header = %q", req.Header.Get("X-FeelPulse-Event"))
+	}
+	if req.Header.Get(SignatureHeader) != Sign("s3cret", body) {
+		t.Errorf("signature %q does not match body", req.Header.Get(SignatureHeader))
+	}
+
+	var event Event
+	if err := json.Unmarshal(body, &event); err != nil {
+		t.Fatalf("invalid payload: %v", err)
+	}
+	if event.Type != EventReplySent || event.ID == "" || event.Data.(map[string]any)["channel"] != "telegram" {
+		t.Errorf("unexpected event: %+v", event)
+	}
+
+	deliveries, _ := d.List("", 10)
+	if len(deliveries) != 1 || deliveries[0].Status != StatusDelivered || deliveries[0].Attempts != 1 {
+		t.Errorf("unexpected deliveries: %+v", deliveries)
+	}
+}
+
+func TestDispatcher_RetryAndReplay(t *testing.T) {
+	rec := &recorder{status: http.StatusInternalServerError}
+	server := httptest.NewServer(rec)
+	defer server.Close()
+
+	d := New()
+	d.SetMaxAttempts(2)
+	d.SetSubscriptions([]Subscription{{Name: "flaky", URL: server.URL}})
+	_ = d.Publish(EventConfigReloaded, nil)
+
+	d.SendDue()
+	deliveries, _ := d.List("", 10)
+	delivery := deliveries[0]
+	if delivery.Status != StatusPending || delivery.Attempts != 1 || delivery.LastError != "HTTP 500" {
+		t.Fatalf("expected a pending retry: %+v", delivery)
+	}
+	if wait := time.Until(delivery.NextAttempt); wait < 25*time.Second {
+		t.Errorf("retry should back off, next attempt in %s", wait)
+	}
+
+	// Not due yet
+	d.SendDue()
+	if rec.count() != 1 {
+		t.Fatalf("retry sent before backoff elapsed")
+	}
+
+	// Make it due and fail again: out of attempts
+	delivery.NextAttempt = time.Now()
+	_ = d.persister.SaveDelivery(delivery)
+	d.SendDue()
+	failed, _ := d.List(StatusFailed, 10)
+	if len(failed) != 1 || failed[0].Attempts != 2 {
+		t.Fatalf("expected a failed delivery: %+v", failed)
+	}
+
+	rec.mu.Lock()
+	rec.status = http.StatusNoContent
+	rec.mu.Unlock()
+
+	n, err := d.ReplayFailed()
+	if err != nil || n != 1 {
+		t.Fatalf("ReplayFailed = %d, %v", n, err)
+	}
+	d.SendDue()
+	delivered, _ := d.List(StatusDelivered, 10)
+	if len(delivered) != 1 || delivered[0].Attempts != 1 {
+		t.Errorf("replayed delivery should be delivered: %+v", delivered)
+	}
+
+	if _, err := d.Replay("missing"); err == nil {
+		t.Error("expected an error for an unknown delivery")
+	}
+}
+
+func TestDispatcher_RemovedSubscription(t *testing.T) {
+	d := New()
+	d.SetSubscriptions([]Subscription{{Name: "old", URL: "http://127.0.0.1:1"}})
+	_ = d.Publish(EventReminderFired, nil)
+
+	d.SetSubscriptions(nil)
+	d.SendDue()
+
+	failed, _ := d.List(StatusFailed, 10)
+	if len(failed) != 1 || failed[0].LastError == "" {
+		t.Errorf("delivery to a removed subscription should fail: %+v", failed)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{4, 4 * time.Minute},
+		{8, time.Hour},
+		{20, time.Hour},
+	}
+
+	for _, tt := range tests {
+		if got := Backoff(tt.attempt); got != tt.want {
+			t.Errorf("Backoff(%d) = %s, want %s", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestIsEvent(t *testing.T) {
+	if !IsEvent(EventToolCalled) || IsEvent("tool.finished") {
+		t.Error("IsEvent should only accept known event types")
+	}
+}
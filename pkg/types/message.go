@@ -1,6 +1,9 @@
 package types
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // Message represents a chat message
 type Message struct {
@@ -23,10 +26,18 @@ type AgentRequest struct {
 
 // AgentResponse is received from the AI model
 type AgentResponse struct {
-	Text       string   `json:"text"`
-	TextBlocks []string `json:"textBlocks,omitempty"` // individual text blocks from each agentic iteration
-	Model      string   `json:"model"`
-	Usage      Usage    `json:"usage"`
+	Text       string     `json:"text"`
+	TextBlocks []string   `json:"textBlocks,omitempty"` // individual text blocks from each agentic iteration
+	Model      string     `json:"model"`
+	Usage      Usage      `json:"usage"`
+	ToolCalls  []ToolCall `json:"toolCalls,omitempty"` // client tool calls the caller must answer
+}
+
+// ToolCall is a tool invocation requested by the model
+type ToolCall struct {
+	ID    string          `json:"id"`
+	Name  string          `json:"name"`
+	Input json.RawMessage `json:"input"`
 }
 
 // Usage tracks token consumption